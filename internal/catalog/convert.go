@@ -0,0 +1,207 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ConvertOptions controls the scope written into converted entities
+type ConvertOptions struct {
+	OrgIdentifier     string
+	ProjectIdentifier string
+}
+
+var invalidIdentifierChars = regexp.MustCompile(`[^0-9a-zA-Z_$]`)
+
+// IsLegacy reports whether any document in the content uses the Backstage schema
+func IsLegacy(content string) bool {
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	for {
+		var entity map[interface{}]interface{}
+		if err := decoder.Decode(&entity); err != nil {
+			return false
+		}
+		if strings.HasPrefix(stringField(entity, "apiVersion"), "backstage.io/") {
+			return true
+		}
+	}
+}
+
+// ConvertLegacy converts Backstage-format entities (backstage.io/v1alpha1) to the
+// harness.io/v1 schema. Documents already in the Harness format keep their key order
+// and comments.
+func ConvertLegacy(content string, opts ConvertOptions) (string, error) {
+	docs, err := ConvertLegacyDocuments(content, opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// ConvertLegacyDocuments is like ConvertLegacy but returns each entity as a separate document
+func ConvertLegacyDocuments(content string, opts ConvertOptions) ([]string, error) {
+	var docs []string
+
+	decoder := yamlv3.NewDecoder(bytes.NewBufferString(content))
+	for doc := 1; ; doc++ {
+		var node yamlv3.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: invalid YAML: %w", doc, err)
+		}
+		if len(node.Content) == 0 || node.Content[0].Tag == "!!null" {
+			continue
+		}
+
+		out, err := convertDocument(&node, opts)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		docs = append(docs, out)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no entities found")
+	}
+
+	return docs, nil
+}
+
+// convertDocument converts a Backstage entity, and writes any other document back
+// from its node so key order and comments survive
+func convertDocument(node *yamlv3.Node, opts ConvertOptions) (string, error) {
+	if v := mappingValue(node.Content[0], "apiVersion"); v == nil || !strings.HasPrefix(v.Value, "backstage.io/") {
+		var out bytes.Buffer
+		encoder := yamlv3.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			return "", err
+		}
+		if err := encoder.Close(); err != nil {
+			return "", err
+		}
+		return out.String(), nil
+	}
+
+	var decoded interface{}
+	if err := node.Decode(&decoded); err != nil {
+		return "", err
+	}
+	entity, _ := genericMaps(decoded).(map[interface{}]interface{})
+	converted, err := convertEntity(entity, opts)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(converted)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// genericMaps turns the string-keyed maps yaml.v3 decodes to into the generic maps
+// convertEntity works on
+func genericMaps(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			out[key] = genericMaps(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			v[key] = genericMaps(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = genericMaps(item)
+		}
+		return v
+	}
+	return value
+}
+
+// ToIdentifier converts a name such as a Backstage metadata.name or a team slug
+// into a valid Harness identifier
+func ToIdentifier(name string) string {
+	identifier := invalidIdentifierChars.ReplaceAllString(name, "_")
+	if identifier != "" && identifier[0] >= '0' && identifier[0] <= '9' {
+		identifier = "_" + identifier
+	}
+	if len(identifier) > 128 {
+		identifier = identifier[:128]
+	}
+	return identifier
+}
+
+func convertEntity(entity map[interface{}]interface{}, opts ConvertOptions) (yaml.MapSlice, error) {
+	metadata, _ := entity["metadata"].(map[interface{}]interface{})
+	spec, _ := entity["spec"].(map[interface{}]interface{})
+
+	name := stringField(metadata, "name")
+	if name == "" {
+		return nil, fmt.Errorf("metadata.name is required")
+	}
+
+	displayName := stringField(metadata, "title")
+	if displayName == "" {
+		displayName = name
+	}
+
+	out := yaml.MapSlice{
+		{Key: "apiVersion", Value: "harness.io/v1"},
+		{Key: "kind", Value: stringField(entity, "kind")},
+	}
+	if entityType := stringField(spec, "type"); entityType != "" {
+		out = append(out, yaml.MapItem{Key: "type", Value: entityType})
+	}
+	out = append(out,
+		yaml.MapItem{Key: "identifier", Value: ToIdentifier(name)},
+		yaml.MapItem{Key: "name", Value: displayName},
+	)
+	if opts.OrgIdentifier != "" {
+		out = append(out, yaml.MapItem{Key: "orgIdentifier", Value: opts.OrgIdentifier})
+	}
+	if opts.ProjectIdentifier != "" {
+		out = append(out, yaml.MapItem{Key: "projectIdentifier", Value: opts.ProjectIdentifier})
+	}
+	if owner := stringField(spec, "owner"); owner != "" {
+		out = append(out, yaml.MapItem{Key: "owner", Value: owner})
+	}
+
+	// Keep descriptive metadata; name and title moved to the top level, and
+	// namespaces have no equivalent in IDP 2.0
+	newMetadata := withoutKeys(metadata, "name", "title", "namespace")
+	if len(newMetadata) > 0 {
+		out = append(out, yaml.MapItem{Key: "metadata", Value: newMetadata})
+	}
+
+	newSpec := withoutKeys(spec, "type", "owner")
+	if len(newSpec) > 0 {
+		out = append(out, yaml.MapItem{Key: "spec", Value: newSpec})
+	}
+
+	return out, nil
+}
+
+func withoutKeys(m map[interface{}]interface{}, keys ...string) map[interface{}]interface{} {
+	out := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, key := range keys {
+		delete(out, key)
+	}
+	return out
+}
@@ -0,0 +1,232 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Severity levels for lint issues
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a single problem found in a catalog file
+type Issue struct {
+	Document int
+	Severity string
+	Field    string
+	Message  string
+}
+
+func (i Issue) String() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Field, i.Message)
+}
+
+var (
+	// Harness identifiers must start with a letter or underscore and contain no hyphens
+	identifierPattern = regexp.MustCompile(`^[a-zA-Z_][0-9a-zA-Z_$]{0,127}$`)
+
+	// Annotation keys follow the Kubernetes qualified name format with an optional prefix
+	annotationKeyPattern = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9.]*[a-zA-Z0-9])?/)?[a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?$`)
+
+	validKinds = map[string]bool{
+		"Component": true,
+		"API":       true,
+		"Resource":  true,
+		"System":    true,
+		"Domain":    true,
+		"Group":     true,
+		"User":      true,
+		"Workflow":  true,
+	}
+
+	validLifecycles = map[string]bool{
+		"experimental": true,
+		"production":   true,
+		"deprecated":   true,
+	}
+
+	// Kinds that require a type and spec.lifecycle
+	typedKinds = map[string]bool{
+		"Component": true,
+		"API":       true,
+		"Resource":  true,
+	}
+)
+
+// Lint validates catalog-info.yaml content, which may contain multiple documents
+func Lint(content string) []Issue {
+	var issues []Issue
+
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	for doc := 1; ; doc++ {
+		var entity map[interface{}]interface{}
+		err := decoder.Decode(&entity)
+		if err == io.EOF {
+			if doc == 1 {
+				issues = append(issues, Issue{Document: doc, Severity: SeverityError, Message: "file is empty"})
+			}
+			break
+		}
+		if err != nil {
+			issues = append(issues, Issue{Document: doc, Severity: SeverityError, Message: fmt.Sprintf("invalid YAML: %v", err)})
+			break
+		}
+		if entity == nil {
+			continue
+		}
+
+		for _, issue := range lintEntity(entity) {
+			issue.Document = doc
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// HasErrors reports whether any issue is an error rather than a warning
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func lintEntity(entity map[interface{}]interface{}) []Issue {
+	var issues []Issue
+	errorf := func(field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Severity: SeverityError, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	warnf := func(field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Severity: SeverityWarning, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	apiVersion := stringField(entity, "apiVersion")
+	switch {
+	case apiVersion == "":
+		errorf("apiVersion", "is required")
+	case strings.HasPrefix(apiVersion, "backstage.io/"):
+		warnf("apiVersion", "legacy Backstage format %q, migrate to harness.io/v1", apiVersion)
+		return append(issues, lintLegacyEntity(entity)...)
+	case apiVersion != "harness.io/v1":
+		errorf("apiVersion", "unsupported value %q, expected harness.io/v1", apiVersion)
+	}
+
+	kind := stringField(entity, "kind")
+	if kind == "" {
+		errorf("kind", "is required")
+	} else if !validKinds[kind] {
+		errorf("kind", "unknown kind %q", kind)
+	}
+
+	identifier := stringField(entity, "identifier")
+	if identifier == "" {
+		errorf("identifier", "is required")
+	} else if !identifierPattern.MatchString(identifier) {
+		errorf("identifier", "%q must match %s (no hyphens)", identifier, identifierPattern.String())
+	}
+
+	if stringField(entity, "name") == "" {
+		errorf("name", "is required")
+	}
+	if stringField(entity, "owner") == "" && kind != "Group" && kind != "User" {
+		errorf("owner", "is required")
+	}
+
+	if stringField(entity, "projectIdentifier") == "" && stringField(entity, "orgIdentifier") == "" {
+		warnf("projectIdentifier", "not set, entity will be created at account scope")
+	}
+
+	if typedKinds[kind] {
+		if stringField(entity, "type") == "" {
+			errorf("type", "is required for %s", kind)
+		}
+
+		spec, _ := entity["spec"].(map[interface{}]interface{})
+		lifecycle := stringField(spec, "lifecycle")
+		if lifecycle == "" {
+			errorf("spec.lifecycle", "is required for %s", kind)
+		} else if !validLifecycles[lifecycle] {
+			warnf("spec.lifecycle", "%q is not one of experimental, production, deprecated", lifecycle)
+		}
+	}
+
+	metadata, _ := entity["metadata"].(map[interface{}]interface{})
+	issues = append(issues, lintAnnotations(metadata)...)
+
+	return issues
+}
+
+// lintLegacyEntity checks the minimum a Backstage entity needs to be imported
+func lintLegacyEntity(entity map[interface{}]interface{}) []Issue {
+	var issues []Issue
+
+	metadata, _ := entity["metadata"].(map[interface{}]interface{})
+	name := stringField(metadata, "name")
+	if name == "" {
+		issues = append(issues, Issue{Severity: SeverityError, Field: "metadata.name", Message: "is required"})
+	} else if !identifierPattern.MatchString(strings.ReplaceAll(name, "-", "_")) {
+		issues = append(issues, Issue{Severity: SeverityError, Field: "metadata.name", Message: fmt.Sprintf("%q cannot be converted to a valid identifier", name)})
+	}
+
+	if stringField(entity, "kind") == "" {
+		issues = append(issues, Issue{Severity: SeverityError, Field: "kind", Message: "is required"})
+	}
+
+	return append(issues, lintAnnotations(metadata)...)
+}
+
+func lintAnnotations(metadata map[interface{}]interface{}) []Issue {
+	var issues []Issue
+
+	raw, ok := metadata["annotations"]
+	if !ok || raw == nil {
+		return nil
+	}
+
+	annotations, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return []Issue{{Severity: SeverityError, Field: "metadata.annotations", Message: "must be a map"}}
+	}
+
+	keys := make([]string, 0, len(annotations))
+	values := make(map[string]interface{}, len(annotations))
+	for k, v := range annotations {
+		keys = append(keys, fmt.Sprint(k))
+		values[fmt.Sprint(k)] = v
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := values[key]
+		if !annotationKeyPattern.MatchString(key) {
+			issues = append(issues, Issue{Severity: SeverityError, Field: "metadata.annotations", Message: fmt.Sprintf("invalid annotation key %q", key)})
+		}
+		if _, ok := v.(string); !ok {
+			issues = append(issues, Issue{Severity: SeverityError, Field: "metadata.annotations." + key, Message: "value must be a string"})
+		}
+	}
+
+	return issues
+}
+
+func stringField(m map[interface{}]interface{}, key string) string {
+	if m == nil {
+		return ""
+	}
+	v, _ := m[key].(string)
+	return strings.TrimSpace(v)
+}
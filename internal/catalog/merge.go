@@ -0,0 +1,249 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Merge folds generated catalog content into an existing catalog file using base, the
+// content generated on the previous run, as the common ancestor:
+//   - fields the user changed since base keep the user's value
+//   - fields the user left alone take the generated value
+//   - fields the user added are kept and fields the user removed stay removed
+//   - lists the user edited keep their entries and gain newly generated ones
+//
+// With an empty base every existing value wins and generated fields are only added.
+// Documents are matched by kind and identifier, or paired when each side has a single
+// entity; comments in the existing file are kept.
+func Merge(base, existing, generated string) (string, error) {
+	baseDocs, err := decodeNodes(base)
+	if err != nil {
+		return "", fmt.Errorf("base: %w", err)
+	}
+	existingDocs, err := decodeNodes(existing)
+	if err != nil {
+		return "", fmt.Errorf("existing file: %w", err)
+	}
+	generatedDocs, err := decodeNodes(generated)
+	if err != nil {
+		return "", fmt.Errorf("generated content: %w", err)
+	}
+
+	baseByKey := make(map[string]*yaml.Node, len(baseDocs))
+	for _, doc := range baseDocs {
+		baseByKey[documentKey(doc)] = doc
+	}
+	existingByKey := make(map[string]*yaml.Node, len(existingDocs))
+	for _, doc := range existingDocs {
+		existingByKey[documentKey(doc)] = doc
+	}
+
+	merged := existingDocs
+	for _, doc := range generatedDocs {
+		key := documentKey(doc)
+		current, ok := existingByKey[key]
+		baseDoc := baseByKey[key]
+		if !ok && len(existingDocs) == 1 && len(generatedDocs) == 1 {
+			// A single entity is the same one even if the user renamed it
+			current, ok = existingDocs[0], true
+			if baseDoc == nil && len(baseDocs) == 1 {
+				baseDoc = baseDocs[0]
+			}
+		}
+		if !ok {
+			if _, removed := baseByKey[key]; !removed {
+				merged = append(merged, doc)
+			}
+			continue
+		}
+		mergeNode(baseDoc, current, doc)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for _, doc := range merged {
+		if err := encoder.Encode(doc); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// decodeNodes returns the root node of each non-empty document
+func decodeNodes(content string) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for doc := 1; ; doc++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: invalid YAML: %w", doc, err)
+		}
+		if len(node.Content) > 0 {
+			docs = append(docs, node.Content[0])
+		}
+	}
+}
+
+// documentKey identifies a document by kind and identifier, normalized so that
+// component/my-svc in a user's file matches the generated Component/my_svc
+func documentKey(doc *yaml.Node) string {
+	var kind, identifier string
+	if v := mappingValue(doc, "kind"); v != nil {
+		kind = strings.ToLower(v.Value)
+	}
+	if v := mappingValue(doc, "identifier"); v != nil {
+		identifier = ToIdentifier(v.Value)
+	}
+	return kind + "/" + identifier
+}
+
+// mergeNode returns the merged value for a field. existing is updated in place where
+// possible; a nil result means the field should be removed.
+func mergeNode(base, existing, generated *yaml.Node) *yaml.Node {
+	userUnchanged := base != nil && existing != nil && nodesEqual(base, existing)
+
+	switch {
+	case existing == nil:
+		if base != nil {
+			return nil
+		}
+		return generated
+	case generated == nil:
+		if userUnchanged {
+			return nil
+		}
+		return existing
+	case existing.Kind == yaml.MappingNode && generated.Kind == yaml.MappingNode:
+		if base != nil && base.Kind != yaml.MappingNode {
+			base = nil
+		}
+		mergeMappings(base, existing, generated)
+		return existing
+	case existing.Kind == yaml.SequenceNode && generated.Kind == yaml.SequenceNode:
+		if userUnchanged {
+			return generated
+		}
+		for _, item := range generated.Content {
+			if !containsNode(existing.Content, item) && (base == nil || !containsNode(base.Content, item)) {
+				existing.Content = append(existing.Content, item)
+			}
+		}
+		return existing
+	case userUnchanged:
+		generated.HeadComment = existing.HeadComment
+		generated.LineComment = existing.LineComment
+		return generated
+	default:
+		return existing
+	}
+}
+
+func mergeMappings(base, existing, generated *yaml.Node) {
+	for i := 0; i+1 < len(generated.Content); i += 2 {
+		key := generated.Content[i].Value
+		var baseValue *yaml.Node
+		if base != nil {
+			baseValue = mappingValue(base, key)
+		}
+		existingValue := mappingValue(existing, key)
+
+		merged := mergeNode(baseValue, existingValue, generated.Content[i+1])
+		switch {
+		case merged == nil:
+			removeMappingKey(existing, key)
+		case existingValue == nil:
+			existing.Content = append(existing.Content, generated.Content[i], merged)
+		case merged != existingValue:
+			replaceMappingValue(existing, key, merged)
+		}
+	}
+
+	// Fields no longer generated are dropped unless the user changed them
+	if base == nil {
+		return
+	}
+	for i := 0; i+1 < len(existing.Content); {
+		key := existing.Content[i].Value
+		if mappingValue(generated, key) == nil {
+			if baseValue := mappingValue(base, key); baseValue != nil && nodesEqual(baseValue, existing.Content[i+1]) {
+				existing.Content = append(existing.Content[:i], existing.Content[i+2:]...)
+				continue
+			}
+		}
+		i += 2
+	}
+}
+
+func replaceMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+}
+
+func removeMappingKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func containsNode(nodes []*yaml.Node, node *yaml.Node) bool {
+	for _, n := range nodes {
+		if nodesEqual(n, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodesEqual compares values, ignoring comments, styles and mapping key order
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind == yaml.DocumentNode && len(a.Content) == 1 {
+		a = a.Content[0]
+	}
+	if b.Kind == yaml.DocumentNode && len(b.Content) == 1 {
+		b = b.Content[0]
+	}
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			other := mappingValue(b, a.Content[i].Value)
+			if other == nil || !nodesEqual(a.Content[i+1], other) {
+				return false
+			}
+		}
+		return true
+	case yaml.AliasNode:
+		return nodesEqual(a.Alias, b.Alias)
+	default:
+		for i := range a.Content {
+			if !nodesEqual(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,186 @@
+package catalog
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		existing  string
+		generated string
+		want      string
+	}{
+		{
+			name:      "user-changed scalar is kept",
+			base:      "kind: component\nidentifier: svc\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: svc\nowner: team-b\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-c\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-b\n",
+		},
+		{
+			name:      "untouched scalar takes the generated value",
+			base:      "kind: component\nidentifier: svc\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: svc\nowner: team-a\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-c\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-c\n",
+		},
+		{
+			name:      "user-added field is kept",
+			base:      "kind: component\nidentifier: svc\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: svc\nowner: team-a\nsystem: payments\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-a\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-a\nsystem: payments\n",
+		},
+		{
+			name:      "user-removed key stays removed",
+			base:      "kind: component\nidentifier: svc\ndescription: old\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: svc\nowner: team-a\n",
+			generated: "kind: component\nidentifier: svc\ndescription: new\nowner: team-a\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-a\n",
+		},
+		{
+			name:      "field no longer generated is dropped unless the user changed it",
+			base:      "kind: component\nidentifier: svc\nlifecycle: experimental\ntype: service\n",
+			existing:  "kind: component\nidentifier: svc\nlifecycle: experimental\ntype: website\n",
+			generated: "kind: component\nidentifier: svc\n",
+			want:      "kind: component\nidentifier: svc\ntype: website\n",
+		},
+		{
+			name:      "edited list keeps its entries and gains generated ones",
+			base:      "kind: component\nidentifier: svc\ntags: [go]\n",
+			existing:  "kind: component\nidentifier: svc\ntags: [go, payments]\n",
+			generated: "kind: component\nidentifier: svc\ntags: [go, docker]\n",
+			want:      "kind: component\nidentifier: svc\ntags: [go, payments, docker]\n",
+		},
+		{
+			name:      "list entry the user removed isn't added back",
+			base:      "kind: component\nidentifier: svc\ntags: [go, docker]\n",
+			existing:  "kind: component\nidentifier: svc\ntags: [go, payments]\n",
+			generated: "kind: component\nidentifier: svc\ntags: [go, docker, grpc]\n",
+			want:      "kind: component\nidentifier: svc\ntags: [go, payments, grpc]\n",
+		},
+		{
+			name:      "untouched list takes the generated value",
+			base:      "kind: component\nidentifier: svc\ntags: [go]\n",
+			existing:  "kind: component\nidentifier: svc\ntags: [go]\n",
+			generated: "kind: component\nidentifier: svc\ntags: [java]\n",
+			want:      "kind: component\nidentifier: svc\ntags: [java]\n",
+		},
+		{
+			name:      "nested mapping is merged field by field",
+			base:      "kind: component\nidentifier: svc\nmetadata:\n  annotations:\n    a: '1'\n    b: '1'\n",
+			existing:  "kind: component\nidentifier: svc\nmetadata:\n  annotations:\n    a: '1'\n    b: user\n",
+			generated: "kind: component\nidentifier: svc\nmetadata:\n  annotations:\n    a: '2'\n    b: '2'\n    c: '2'\n",
+			want:      "kind: component\nidentifier: svc\nmetadata:\n  annotations:\n    a: '2'\n    b: user\n    c: '2'\n",
+		},
+		{
+			name:      "empty base keeps every existing value and only adds fields",
+			base:      "",
+			existing:  "kind: component\nidentifier: svc\nowner: team-b\ntags: [payments]\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-c\nlifecycle: production\ntags: [go]\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-b\ntags: [payments, go]\nlifecycle: production\n",
+		},
+		{
+			name: "documents are matched by kind and identifier",
+			base: "kind: component\nidentifier: svc\nowner: team-a\n---\n" +
+				"kind: api\nidentifier: svc\nowner: team-a\n",
+			existing: "kind: api\nidentifier: svc\nowner: team-b\n---\n" +
+				"kind: component\nidentifier: svc\nowner: team-a\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-c\n---\n" +
+				"kind: api\nidentifier: svc\nowner: team-c\n---\n" +
+				"kind: component\nidentifier: worker\nowner: team-c\n",
+			want: "kind: api\nidentifier: svc\nowner: team-b\n---\n" +
+				"kind: component\nidentifier: svc\nowner: team-c\n---\n" +
+				"kind: component\nidentifier: worker\nowner: team-c\n",
+		},
+		{
+			name:      "hyphenated identifier matches the generated one",
+			base:      "",
+			existing:  "kind: component\nidentifier: my-svc\nowner: team-b\n",
+			generated: "kind: component\nidentifier: my_svc\nowner: team-c\nlifecycle: production\n",
+			want:      "kind: component\nidentifier: my-svc\nowner: team-b\nlifecycle: production\n",
+		},
+		{
+			name:      "lowercase kind matches the generated one",
+			base:      "kind: Component\nidentifier: svc\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: svc\nowner: team-a\n",
+			generated: "kind: Component\nidentifier: svc\nowner: team-c\n",
+			want:      "kind: component\nidentifier: svc\nowner: team-c\n",
+		},
+		{
+			name:      "single entities are paired even when renamed",
+			base:      "kind: component\nidentifier: svc\nowner: team-a\n",
+			existing:  "kind: component\nidentifier: payments\nowner: team-a\n",
+			generated: "kind: component\nidentifier: svc\nowner: team-c\n",
+			want:      "kind: component\nidentifier: payments\nowner: team-c\n",
+		},
+		{
+			name: "document the user removed isn't added back",
+			base: "kind: component\nidentifier: svc\n---\n" +
+				"kind: api\nidentifier: svc\n",
+			existing: "kind: component\nidentifier: svc\n",
+			generated: "kind: component\nidentifier: svc\n---\n" +
+				"kind: api\nidentifier: svc\n",
+			want: "kind: component\nidentifier: svc\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Merge(tt.base, tt.existing, tt.generated)
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if gotDocs, wantDocs := decodeDocuments(t, got), decodeDocuments(t, tt.want); !reflect.DeepEqual(gotDocs, wantDocs) {
+				t.Errorf("Merge() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeKeepsComments(t *testing.T) {
+	existing := "# maintained by the payments team\nkind: component\nidentifier: svc\nowner: team-a # on call\n"
+	got, err := Merge(existing, existing, "kind: component\nidentifier: svc\nowner: team-c\n")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	for _, comment := range []string{"# maintained by the payments team", "# on call"} {
+		if !strings.Contains(got, comment) {
+			t.Errorf("Merge() dropped comment %q:\n%s", comment, got)
+		}
+	}
+	if !strings.Contains(got, "owner: team-c") {
+		t.Errorf("Merge() didn't update the untouched owner:\n%s", got)
+	}
+}
+
+func TestMergeInvalidYAML(t *testing.T) {
+	if _, err := Merge("", "kind: [", "kind: component\n"); err == nil {
+		t.Error("Merge() with an invalid existing file succeeded")
+	}
+}
+
+// decodeDocuments decodes each document so results compare by value, not formatting
+func decodeDocuments(t *testing.T, content string) []any {
+	t.Helper()
+	var docs []any
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc any
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			return docs
+		}
+		if err != nil {
+			t.Fatalf("invalid YAML %q: %v", content, err)
+		}
+		docs = append(docs, doc)
+	}
+}
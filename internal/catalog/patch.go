@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// patchedFields are the top-level fields Patch adds when an entity lacks them
+var patchedFields = []string{"identifier", "orgIdentifier", "projectIdentifier"}
+
+// Patch adds only what an existing catalog file is missing from the generated content:
+// the identifier and scope fields and any generated annotations whose keys are absent.
+// Everything else in the file, including comments, is left untouched. Existing
+// documents are matched to generated ones by kind and identifier, compared the way
+// Merge does, or by kind alone when the existing document has no identifier.
+func Patch(existing, generated string) (string, error) {
+	existingDocs, err := decodeNodes(existing)
+	if err != nil {
+		return "", fmt.Errorf("existing file: %w", err)
+	}
+	generatedDocs, err := decodeNodes(generated)
+	if err != nil {
+		return "", fmt.Errorf("generated content: %w", err)
+	}
+
+	for _, doc := range existingDocs {
+		if doc.Kind != yaml.MappingNode {
+			continue
+		}
+		if source := matchGenerated(doc, generatedDocs); source != nil {
+			patchEntity(doc, source)
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for _, doc := range existingDocs {
+		if err := encoder.Encode(doc); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func matchGenerated(doc *yaml.Node, generated []*yaml.Node) *yaml.Node {
+	key := documentKey(doc)
+	for _, g := range generated {
+		if documentKey(g) == key {
+			return g
+		}
+	}
+	if mappingValue(doc, "identifier") != nil {
+		return nil
+	}
+
+	var kind string
+	if v := mappingValue(doc, "kind"); v != nil {
+		kind = v.Value
+	}
+	for _, g := range generated {
+		if v := mappingValue(g, "kind"); v != nil && strings.EqualFold(v.Value, kind) {
+			return g
+		}
+	}
+	return nil
+}
+
+func patchEntity(doc, source *yaml.Node) {
+	for _, field := range patchedFields {
+		if mappingValue(doc, field) != nil {
+			continue
+		}
+		if value := mappingValue(source, field); value != nil {
+			setMappingValue(doc, field, value.Value)
+		}
+	}
+
+	sourceMetadata := mappingValue(source, "metadata")
+	if sourceMetadata == nil {
+		return
+	}
+	sourceAnnotations := mappingValue(sourceMetadata, "annotations")
+	if sourceAnnotations == nil || len(sourceAnnotations.Content) == 0 {
+		return
+	}
+
+	metadata := mappingValue(doc, "metadata")
+	if metadata == nil {
+		metadata = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "metadata"}, metadata)
+	}
+	annotations := mappingValue(metadata, "annotations")
+	if annotations == nil {
+		annotations = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		metadata.Content = append(metadata.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "annotations"}, annotations)
+	}
+
+	for i := 0; i+1 < len(sourceAnnotations.Content); i += 2 {
+		if mappingValue(annotations, sourceAnnotations.Content[i].Value) == nil {
+			annotations.Content = append(annotations.Content, sourceAnnotations.Content[i], sourceAnnotations.Content[i+1])
+		}
+	}
+}
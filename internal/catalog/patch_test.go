@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	generated := "kind: Component\nidentifier: my_svc\norgIdentifier: default\nprojectIdentifier: payments\n" +
+		"metadata:\n  annotations:\n    github.com/project-slug: acme/my-svc\n"
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "lowercase kind gets the missing scope",
+			existing: "kind: component\nidentifier: my_svc\nowner: team-a\n",
+			want: "kind: component\nidentifier: my_svc\nowner: team-a\norgIdentifier: default\nprojectIdentifier: payments\n" +
+				"metadata:\n  annotations:\n    github.com/project-slug: acme/my-svc\n",
+		},
+		{
+			name:     "hyphenated identifier gets the missing scope",
+			existing: "kind: Component\nidentifier: my-svc\norgIdentifier: platform\n",
+			want: "kind: Component\nidentifier: my-svc\norgIdentifier: platform\nprojectIdentifier: payments\n" +
+				"metadata:\n  annotations:\n    github.com/project-slug: acme/my-svc\n",
+		},
+		{
+			name:     "entity without identifier is matched by kind",
+			existing: "kind: component\nowner: team-a\n",
+			want: "kind: component\nowner: team-a\nidentifier: my_svc\norgIdentifier: default\nprojectIdentifier: payments\n" +
+				"metadata:\n  annotations:\n    github.com/project-slug: acme/my-svc\n",
+		},
+		{
+			name:     "other entity is left alone",
+			existing: "kind: Component\nidentifier: billing\n",
+			want:     "kind: Component\nidentifier: billing\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Patch(tt.existing, generated)
+			if err != nil {
+				t.Fatalf("Patch: %v", err)
+			}
+			if gotDocs, wantDocs := decodeDocuments(t, got), decodeDocuments(t, tt.want); !reflect.DeepEqual(gotDocs, wantDocs) {
+				t.Errorf("Patch() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sanitize prepares catalog content for registration with Harness IDP. Each document
+// is parsed as a YAML node tree so comments, key order and multi-document files
+// survive. In every document it:
+//   - converts the top-level identifier into a valid Harness identifier
+//   - adds orgIdentifier and projectIdentifier to harness.io/v1 entities missing them
+//   - lowercases type and spec.lifecycle, which IDP matches case-sensitively
+func Sanitize(content string, opts ConvertOptions) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+
+	for doc := 1; ; doc++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("document %d: invalid YAML: %w", doc, err)
+		}
+
+		if len(node.Content) > 0 && node.Content[0].Kind == yaml.MappingNode {
+			sanitizeEntity(node.Content[0], opts)
+		}
+
+		if err := encoder.Encode(&node); err != nil {
+			return "", fmt.Errorf("document %d: %w", doc, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func sanitizeEntity(entity *yaml.Node, opts ConvertOptions) {
+	if identifier := mappingValue(entity, "identifier"); identifier != nil && identifier.Kind == yaml.ScalarNode {
+		identifier.Value = ToIdentifier(strings.TrimSpace(identifier.Value))
+		identifier.Style = 0
+	}
+
+	if apiVersion := mappingValue(entity, "apiVersion"); apiVersion != nil && apiVersion.Value == "harness.io/v1" {
+		if opts.OrgIdentifier != "" && mappingValue(entity, "orgIdentifier") == nil {
+			setMappingValue(entity, "orgIdentifier", opts.OrgIdentifier)
+		}
+		if opts.ProjectIdentifier != "" && mappingValue(entity, "projectIdentifier") == nil {
+			setMappingValue(entity, "projectIdentifier", opts.ProjectIdentifier)
+		}
+	}
+
+	lowercaseScalar(mappingValue(entity, "type"))
+	if spec := mappingValue(entity, "spec"); spec != nil && spec.Kind == yaml.MappingNode {
+		lowercaseScalar(mappingValue(spec, "lifecycle"))
+	}
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue adds key after identifier (or after apiVersion when there is no
+// identifier), so injected fields sit where they do in generated files
+func setMappingValue(mapping *yaml.Node, key, value string) {
+	pair := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	}
+
+	at := len(mapping.Content)
+	for _, anchor := range []string{"identifier", "apiVersion"} {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == anchor && at == len(mapping.Content) {
+				at = i + 2
+			}
+		}
+	}
+	mapping.Content = append(mapping.Content[:at], append(pair, mapping.Content[at:]...)...)
+}
+
+func lowercaseScalar(node *yaml.Node) {
+	if node != nil && node.Kind == yaml.ScalarNode {
+		node.Value = strings.ToLower(strings.TrimSpace(node.Value))
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// processAPIModeBatched inspects repositories concurrently, then creates their
+// components --api-batch-size at a time with one CreateComponents call per batch
+func processAPIModeBatched(ctx context.Context, repos []models.Repository) error {
+	size := config.Runtime.APIBatchSize
+	log.Printf("Processing %d repositories in API mode, %d components per batch", len(repos), size)
+
+	prepared := make([]models.Repository, len(repos))
+	components := make([]models.HarnessComponent, len(repos))
+	violations := make([]*errors.ProcessingError, len(repos))
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, r models.Repository) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			components[i] = prepareAPIComponent(ctx, &r)
+			prepared[i] = r
+			violations[i] = checkComponentPolicies(ctx, r)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	// Entities failing the policies are reported without being sent
+	summary := newRunSummary()
+	n := 0
+	for i, repo := range prepared {
+		if violations[i] != nil {
+			result := policyResult(repo, violations[i])
+			recordState(repo, result)
+			summary.AddResult(result)
+			continue
+		}
+		prepared[n], components[n] = repo, components[i]
+		n++
+	}
+	prepared, components = prepared[:n], components[:n]
+
+	for start := 0; start < len(prepared); start += size {
+		end := min(start+size, len(prepared))
+		errs := createComponentBatch(ctx, prepared[start:end], components[start:end])
+		for i, err := range errs {
+			repo := prepared[start+i]
+			result := withScores(ctx, repo, apiResult(ctx, repo, components[start+i], err))
+			recordState(repo, result)
+			markOnboarded(ctx, repo, result)
+			summary.AddResult(result)
+		}
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during API processing", summary.Total)
+	}
+	return nil
+}
+
+// createComponentBatch creates a batch of components with one CreateComponents call per
+// Harness project the repositories are routed to, returning errors in batch order
+func createComponentBatch(ctx context.Context, repos []models.Repository, components []models.HarnessComponent) []error {
+	clients := make(map[*harness.Client][]int)
+	var order []*harness.Client
+	for i, repo := range repos {
+		client := harnessFor(repo)
+		if _, ok := clients[client]; !ok {
+			order = append(order, client)
+		}
+		clients[client] = append(clients[client], i)
+	}
+
+	errs := make([]error, len(components))
+	for _, client := range order {
+		indexes := clients[client]
+		group := make([]models.HarnessComponent, len(indexes))
+		for j, i := range indexes {
+			group[j] = components[i]
+		}
+		for j, err := range client.CreateComponents(ctx, group) {
+			errs[indexes[j]] = err
+		}
+	}
+	return errs
+}
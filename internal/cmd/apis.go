@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// apiIdentifier returns the identifier of the API entity generated for a repository
+func apiIdentifier(repo models.Repository) string {
+	return repoIdentifier(repo) + "_api"
+}
+
+// providedAPIs returns the providesApis references for a repository's component
+func providedAPIs(repo models.Repository) []string {
+	if repo.APISpecPath == "" || repoKind(repo) != "Component" {
+		return nil
+	}
+	return []string{"api:" + apiIdentifier(repo)}
+}
+
+// buildAPIEntity returns the API entity for a repository's OpenAPI definition, or nil
+// when the repository has none or isn't a Component. A repository onboarded as an API
+// is its own API entity.
+func buildAPIEntity(repo models.Repository) *models.HarnessEntity {
+	if repo.APISpecPath == "" || repoKind(repo) != "Component" {
+		return nil
+	}
+
+	defaults := repoDefaults(repo)
+
+	spec := map[string]interface{}{
+		"lifecycle": defaults.Lifecycle,
+		"definition": map[string]string{
+			"$text": apiDefinitionURL(repo),
+		},
+	}
+	if defaults.System != "" {
+		spec["system"] = defaults.System
+	}
+
+	return &models.HarnessEntity{
+		Kind:        "API",
+		Type:        "openapi",
+		Identifier:  apiIdentifier(repo),
+		Name:        repo.Name + "-api",
+		Owner:       getOwner(repo),
+		Description: fmt.Sprintf("API definition for %s", repo.Name),
+		Annotations: map[string]string{
+			"github.com/project-slug": repo.FullName,
+		},
+		Spec: spec,
+	}
+}
+
+// apiEntityYAML renders the API entity for a repository as an additional catalog
+// document, or an empty string when the repository has no API definition
+func apiEntityYAML(repo models.Repository) (string, error) {
+	entity := buildAPIEntity(repo)
+	if entity == nil {
+		return "", nil
+	}
+	orgID, projectID := repoScope(repo)
+	return harness.EntityYAML(*entity, orgID, projectID)
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// Audit verdicts, ordered roughly by severity
+const (
+	verdictOK                 = "OK"
+	verdictMissingCatalog     = "MISSING_CATALOG"
+	verdictNotRegistered      = "NOT_REGISTERED"
+	verdictIdentifierMismatch = "IDENTIFIER_MISMATCH"
+	verdictError              = "ERROR"
+)
+
+// auditResult is the read-only verdict for a single repository
+type auditResult struct {
+	Repository         string
+	CatalogPath        string
+	CatalogIdentifier  string
+	ExpectedIdentifier string
+	Registered         bool
+	Verdict            string
+	Detail             string
+}
+
+func processAuditMode(ctx context.Context, repos []models.Repository) error {
+	log.Printf("Auditing %d repositories against Harness IDP", len(repos))
+
+	// One paged listing replaces a lookup per repository; fall back to lookups if it fails
+	registered, err := registeredComponents(ctx)
+	if err != nil {
+		log.Printf("Warning: could not list components, looking each one up instead: %v", err)
+	}
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan auditResult, len(repos))
+
+	for _, repo := range repos {
+		go func(r models.Repository) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			results <- auditRepository(ctx, r, registered)
+		}(repo)
+	}
+
+	var audits []auditResult
+	for i := 0; i < len(repos); i++ {
+		audits = append(audits, <-results)
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		return audits[i].Repository < audits[j].Repository
+	})
+
+	gaps := printAuditTable(audits)
+	if gaps > 0 {
+		return fmt.Errorf("audit found %d of %d repositories with coverage gaps", gaps, len(audits))
+	}
+
+	return nil
+}
+
+// registeredComponents returns every entity at the configured scope of the kinds the
+// onboarder generates, keyed by entityKey. With project routes entities span several
+// projects, so it returns nil and each repository is looked up in its own project
+// instead.
+func registeredComponents(ctx context.Context) (map[string]bool, error) {
+	if len(projectRoutes) > 0 {
+		return nil, nil
+	}
+	registered := make(map[string]bool)
+	for _, kind := range harness.EntityKinds {
+		components, err := harnessClient.ListComponents(ctx, harness.ComponentFilter{Kind: kind})
+		if err != nil {
+			return nil, err
+		}
+		for _, component := range components {
+			registered[entityKey(kind, component.Identifier)] = true
+		}
+	}
+	return registered, nil
+}
+
+// entityKey identifies an entity by kind and identifier
+func entityKey(kind, identifier string) string {
+	return strings.ToLower(kind) + "/" + identifier
+}
+
+// auditRepository compares a repository's catalog file with Harness IDP. registered,
+// when non-nil, holds every registered identifier and saves a lookup per repository.
+func auditRepository(ctx context.Context, repo models.Repository, registered map[string]bool) auditResult {
+	result := auditResult{
+		Repository:         repo.FullName,
+		ExpectedIdentifier: repoIdentifier(repo),
+	}
+
+	catalogPath, catalogContent, err := getCatalogInfoPathAndContent(ctx, repo)
+	if err != nil {
+		if !strings.Contains(err.Error(), "no catalog-info.yaml file found") {
+			result.Verdict = verdictError
+			result.Detail = err.Error()
+			return result
+		}
+	} else {
+		result.CatalogPath = catalogPath
+		identifier, err := harness.ExtractEntityIdentifier(catalogContent)
+		if err != nil {
+			result.Verdict = verdictError
+			result.Detail = fmt.Sprintf("invalid catalog file: %v", err)
+			return result
+		}
+		// Register mode sanitizes hyphens before import, so compare the same way
+		result.CatalogIdentifier = strings.ReplaceAll(identifier, "-", "_")
+	}
+
+	lookup := result.ExpectedIdentifier
+	if result.CatalogIdentifier != "" {
+		lookup = result.CatalogIdentifier
+	}
+
+	// Repositories generated as Resources or other kinds are looked up as that kind,
+	// which for IaC repositories needs the root files audit doesn't otherwise list
+	if err := githubClient.DetectRootSignals(ctx, &repo); err != nil {
+		log.Printf("Warning: failed to inspect root of %s: %v", repo.FullName, err)
+	}
+	kind := repoKind(repo)
+	if registered != nil {
+		result.Registered = registered[entityKey(kind, lookup)]
+	} else {
+		component, err := harnessFor(repo).GetEntity(ctx, kind, lookup)
+		if err != nil {
+			result.Verdict = verdictError
+			result.Detail = err.Error()
+			return result
+		}
+		result.Registered = component != nil
+	}
+
+	switch {
+	case result.CatalogPath == "" && !result.Registered:
+		result.Verdict = verdictMissingCatalog
+		result.Detail = "no catalog file and no entity"
+	case result.CatalogIdentifier != "" && result.CatalogIdentifier != result.ExpectedIdentifier:
+		result.Verdict = verdictIdentifierMismatch
+		result.Detail = fmt.Sprintf("catalog uses %s, expected %s", result.CatalogIdentifier, result.ExpectedIdentifier)
+	case !result.Registered:
+		result.Verdict = verdictNotRegistered
+		result.Detail = "catalog file exists but entity not registered (use register mode)"
+	case result.CatalogPath == "":
+		result.Verdict = verdictMissingCatalog
+		result.Detail = "entity registered but no catalog file in repository"
+	default:
+		result.Verdict = verdictOK
+	}
+
+	return result
+}
+
+// printAuditTable prints the verdict table and returns the number of repositories with gaps
+func printAuditTable(audits []auditResult) int {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tCATALOG\tREGISTERED\tIDENTIFIER\tVERDICT\tDETAIL")
+
+	gaps := 0
+	byVerdict := make(map[string]int)
+	for _, a := range audits {
+		catalog := a.CatalogPath
+		if catalog == "" {
+			catalog = "-"
+		}
+		identifier := a.CatalogIdentifier
+		if identifier == "" {
+			identifier = a.ExpectedIdentifier
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", a.Repository, catalog, a.Registered, identifier, a.Verdict, a.Detail)
+
+		byVerdict[a.Verdict]++
+		if a.Verdict != verdictOK {
+			gaps++
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Audit Summary:\n")
+	fmt.Printf("   Total repositories: %d\n", len(audits))
+	for _, verdict := range []string{verdictOK, verdictMissingCatalog, verdictNotRegistered, verdictIdentifierMismatch, verdictError} {
+		if byVerdict[verdict] > 0 {
+			fmt.Printf("   %s: %d\n", verdict, byVerdict[verdict])
+		}
+	}
+	if len(audits) > 0 {
+		fmt.Printf("   Coverage: %.1f%%\n", float64(byVerdict[verdictOK])*100/float64(len(audits)))
+	}
+
+	return gaps
+}
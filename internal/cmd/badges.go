@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/models"
+)
+
+// defaultBadgeURL links the badge to the entity's page in the Harness IDP catalog
+const defaultBadgeURL = "{{ .BaseURL }}/ng/account/{{ .AccountID }}/module/idp/catalog/{{ .OrgID }}/{{ .ProjectID }}/{{ .Kind | lower }}/{{ .Identifier }}"
+
+// badgeImage is the shields.io image shown for the README badge
+const badgeImage = "https://img.shields.io/badge/Harness%20IDP-View%20in%20catalog-00ADE4"
+
+// badgeTemplate is the parsed badge link template, nil when the badge is disabled
+var badgeTemplate *template.Template
+
+// badgeData is what badge link templates are rendered against. Repository fields such
+// as .Name are available alongside the entity and Harness scope.
+type badgeData struct {
+	models.Repository
+	Identifier string
+	Kind       string
+	BaseURL    string
+	AccountID  string
+	OrgID      string
+	ProjectID  string
+}
+
+// loadBadgeTemplate parses the badge link template when the README badge is enabled
+func loadBadgeTemplate() error {
+	badgeTemplate = nil
+	if !config.Runtime.ReadmeBadge {
+		return nil
+	}
+
+	text := config.Runtime.ReadmeBadgeURL
+	if text == "" {
+		text = defaultBadgeURL
+	}
+	tmpl, err := template.New("badge").Funcs(identifierFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid README badge URL template: %w", err)
+	}
+	badgeTemplate = tmpl
+	return nil
+}
+
+// readmeBadgeFiles returns the README.md edit adding a "View in Harness IDP" badge to
+// onboarding PRs when enabled. READMEs that are missing or already link to the entity
+// are left alone. Nil when disabled or rendering fails.
+func readmeBadgeFiles(repo models.Repository) []github.PRFile {
+	if badgeTemplate == nil {
+		return nil
+	}
+
+	harnessConfig := repoHarnessConfig(repo)
+	data := badgeData{
+		Repository: repo,
+		Identifier: repoIdentifier(repo),
+		Kind:       repoKind(repo),
+		BaseURL:    strings.TrimSuffix(harnessConfig.BaseURL, "/"),
+		AccountID:  harnessConfig.AccountID,
+		OrgID:      harnessConfig.OrgID,
+		ProjectID:  harnessConfig.ProjectID,
+	}
+	var buf bytes.Buffer
+	if err := badgeTemplate.Execute(&buf, data); err != nil {
+		log.Printf("Warning: failed to render README badge for %s, leaving it out: %v", repo.FullName, err)
+		return nil
+	}
+	url := strings.TrimSpace(buf.String())
+	badge := fmt.Sprintf("[![View in Harness IDP](%s)](%s)", badgeImage, url)
+
+	return []github.PRFile{{Path: "README.md", Edit: func(existing string) string {
+		if existing == "" || strings.Contains(existing, url) {
+			return existing
+		}
+		return addBadge(existing, badge)
+	}}}
+}
+
+// addBadge inserts the badge below the README's leading title, or at the top when the
+// README doesn't start with one
+func addBadge(readme, badge string) string {
+	lines := strings.SplitAfter(readme, "\n")
+	if strings.HasPrefix(lines[0], "# ") {
+		title := strings.TrimRight(lines[0], "\n") + "\n"
+		return title + "\n" + badge + "\n" + strings.Join(lines[1:], "")
+	}
+	return badge + "\n\n" + readme
+}
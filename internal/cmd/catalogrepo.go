@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// catalogRepoPath is where a repository's catalog file lives in the central catalog repo
+func catalogRepoPath(repo models.Repository) string {
+	return path.Join(config.GitHub.CatalogDir, repo.Name+".yaml")
+}
+
+// processCatalogRepoYAML generates every repository's catalog file and adds them to the
+// central catalog repository, in one PR or one per batch (see catalogBatches)
+func processCatalogRepoYAML(ctx context.Context, repos []models.Repository) error {
+	log.Printf("Processing %d repositories into catalog repository %s", len(repos), config.GitHub.CatalogRepo)
+
+	summary := newRunSummary()
+	var files []github.CatalogFile
+	generated := make(map[string]string, len(repos))
+	byName := make(map[string]models.Repository, len(repos))
+	for _, repo := range repos {
+		yamlContent, err := generateCatalogYAML(repo)
+		if err != nil {
+			result := errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      errors.CategorizeError(fmt.Errorf("failed to marshal catalog-info.yaml: %w", err), repo.FullName),
+				Message:    "YAML generation failed",
+				Action:     "failed",
+			}
+			recordState(repo, result)
+			summary.AddResult(result)
+			continue
+		}
+		if procErr := checkPolicies(ctx, repo, yamlContent); procErr != nil {
+			result := policyResult(repo, procErr)
+			recordState(repo, result)
+			summary.AddResult(result)
+			continue
+		}
+		if procErr := validateWithHarness(ctx, repo, yamlContent); procErr != nil {
+			result := errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      procErr,
+				Message:    "Harness rejected the generated catalog file",
+				Action:     "failed",
+			}
+			recordState(repo, result)
+			summary.AddResult(result)
+			continue
+		}
+		files = append(files, github.CatalogFile{Path: catalogRepoPath(repo), Content: yamlContent, Repository: repo.FullName})
+		generated[repo.FullName] = yamlContent
+		byName[repo.FullName] = repo
+	}
+
+	for _, batch := range catalogBatches(files, byName) {
+		prURL, changed, err := githubClient.CreateCatalogRepoPR(ctx, config.GitHub.CatalogRepo, batch.name, batch.files)
+		changedRepos := make(map[string]bool, len(changed))
+		for _, file := range changed {
+			changedRepos[file.Repository] = true
+		}
+
+		for _, file := range batch.files {
+			repo := byName[file.Repository]
+			var result errors.ProcessingResult
+			switch {
+			case err != nil:
+				result = errors.ProcessingResult{
+					Repository: repo.FullName,
+					Success:    false,
+					Error:      errors.CategorizeError(err, repo.FullName),
+					Message:    "Catalog repository PR failed",
+					Action:     "failed",
+				}
+			case changedRepos[repo.FullName]:
+				result = errors.ProcessingResult{
+					Repository: repo.FullName,
+					Success:    true,
+					Message:    fmt.Sprintf("Added %s to catalog repository PR", file.Path),
+					Action:     "created",
+					Identifier: repoIdentifier(repo),
+					URL:        prURL,
+				}
+			default:
+				result = errors.ProcessingResult{
+					Repository: repo.FullName,
+					Success:    true,
+					Message:    "Catalog file already up to date",
+					Skipped:    true,
+					Action:     "skipped",
+					Identifier: repoIdentifier(repo),
+				}
+			}
+			if err == nil && stateManager != nil {
+				stateManager.SetGenerated(repo.FullName, generated[repo.FullName])
+			}
+			recordState(repo, result)
+			summary.AddResult(result)
+		}
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during YAML processing", summary.Total)
+	}
+	return nil
+}
+
+// catalogBatch is a group of catalog files reviewed together in one PR
+type catalogBatch struct {
+	name  string
+	files []github.CatalogFile
+}
+
+// catalogBatches groups files by owner when --batch-by owner is set, then splits groups
+// larger than --batch-size. A single unnamed batch means one PR for the whole run.
+func catalogBatches(files []github.CatalogFile, repos map[string]models.Repository) []catalogBatch {
+	var groups []catalogBatch
+	if config.Runtime.BatchBy == "owner" {
+		index := make(map[string]int)
+		for _, file := range files {
+			owner := getOwner(repos[file.Repository])
+			i, ok := index[owner]
+			if !ok {
+				i = len(groups)
+				index[owner] = i
+				groups = append(groups, catalogBatch{name: owner})
+			}
+			groups[i].files = append(groups[i].files, file)
+		}
+		sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
+	} else {
+		groups = []catalogBatch{{files: files}}
+	}
+
+	size := config.Runtime.BatchSize
+	if size <= 0 {
+		return groups
+	}
+	var batches []catalogBatch
+	for _, group := range groups {
+		if len(group.files) <= size {
+			batches = append(batches, group)
+			continue
+		}
+		for part, start := 1, 0; start < len(group.files); part, start = part+1, start+size {
+			end := min(start+size, len(group.files))
+			name := fmt.Sprintf("part %d", part)
+			if group.name != "" {
+				name = fmt.Sprintf("%s part %d", group.name, part)
+			}
+			batches = append(batches, catalogBatch{name: name, files: group.files[start:end]})
+		}
+	}
+	return batches
+}
+
+// processCatalogRepoRegister registers each repository's file from the central catalog
+// repository with Harness IDP
+func processCatalogRepoRegister(ctx context.Context, repos []models.Repository) error {
+	log.Printf("Registering %d repositories from catalog repository %s", len(repos), config.GitHub.CatalogRepo)
+
+	branch, files, err := githubClient.ListCatalogRepoFiles(ctx, config.GitHub.CatalogRepo, config.GitHub.CatalogDir)
+	if err != nil {
+		return err
+	}
+
+	summary := newRunSummary()
+	for _, repo := range repos {
+		throttle(ctx)
+		result := withScores(ctx, repo, registerFromCatalogRepo(ctx, repo, branch, files))
+		recordState(repo, result)
+		markOnboarded(ctx, repo, result)
+		summary.AddResult(result)
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during REGISTER processing", summary.Total)
+	}
+	return nil
+}
+
+func registerFromCatalogRepo(ctx context.Context, repo models.Repository, branch string, files map[string]string) errors.ProcessingResult {
+	filePath := catalogRepoPath(repo)
+	content, ok := files[filePath]
+	if !ok {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    fmt.Sprintf("No %s in catalog repository", filePath),
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+
+	sanitized, err := catalog.Sanitize(content, convertOptions(repo))
+	if err != nil {
+		log.Printf("Warning: could not sanitize %s in %s, registering as-is: %v", filePath, config.GitHub.CatalogRepo, err)
+		sanitized = content
+	}
+
+	err = harnessFor(repo).RegisterCatalogLocation(ctx, config.GitHub.CatalogRepo, branch, filePath, sanitized)
+	if err != nil {
+		procErr := errors.CategorizeError(err, repo.FullName)
+		if procErr.Type == errors.ErrorTypeEntityAlreadyRegistered {
+			return errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      procErr,
+				Message:    "Entity already registered",
+				Skipped:    true,
+				Action:     "skipped",
+			}
+		}
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      procErr,
+			Message:    "Registration failed",
+			Action:     "failed",
+		}
+	}
+
+	log.Printf("Registered %s from %s:%s", repo.FullName, config.GitHub.CatalogRepo, filePath)
+	identifier, _ := harness.ExtractEntityIdentifier(sanitized)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Message:    fmt.Sprintf("Entity registered from %s", config.GitHub.CatalogRepo),
+		Action:     "registered",
+		Identifier: strings.ReplaceAll(identifier, "-", "_"),
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// applyCIMetadata records the CI systems detected in the repository in the
+// harness.io/ci annotation and adds the annotations and links for the ones the catalog
+// can point to. Annotations already set, such as by rules, are kept.
+func applyCIMetadata(repo models.Repository, annotations map[string]string, links []models.ComponentLink) []models.ComponentLink {
+	if len(repo.CISystems) == 0 {
+		return links
+	}
+	setDefaultAnnotation(annotations, "harness.io/ci", strings.Join(repo.CISystems, ","))
+
+	for _, system := range repo.CISystems {
+		switch system {
+		case "github-actions":
+			links = append(links, ciLink("GitHub Actions", repo.HTMLURL+"/actions"))
+		case "circleci":
+			setDefaultAnnotation(annotations, "circleci.com/project-slug", "github/"+repo.FullName)
+			links = append(links, ciLink("CircleCI", "https://app.circleci.com/pipelines/github/"+repo.FullName))
+		case "harness":
+			if url := harnessPipelinesURL(repo); url != "" {
+				links = append(links, ciLink("Harness CI", url))
+			}
+		}
+	}
+	return links
+}
+
+func ciLink(title, url string) models.ComponentLink {
+	return models.ComponentLink{Title: title, URL: url, Icon: "dashboard", Type: "ci"}
+}
+
+func setDefaultAnnotation(annotations map[string]string, key, value string) {
+	if _, exists := annotations[key]; !exists {
+		annotations[key] = value
+	}
+}
+
+// harnessPipelinesURL links to the pipelines of the Harness project the repository is
+// routed to, or returns an empty string when no project is configured
+func harnessPipelinesURL(repo models.Repository) string {
+	cfg := repoHarnessConfig(repo)
+	if cfg.BaseURL == "" || cfg.OrgID == "" || cfg.ProjectID == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cfg.BaseURL, "/") + fmt.Sprintf("/ng/account/%s/module/ci/orgs/%s/projects/%s/pipelines",
+		cfg.AccountID, cfg.OrgID, cfg.ProjectID)
+}
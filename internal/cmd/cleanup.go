@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/models"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Close superseded onboarding PRs and delete stale onboarding branches",
+	Long: `Finds harness-onboarding-* and harness-idp-migration-* branches left by earlier
+runs. When a repository has several open onboarding PRs, all but the newest are
+closed and their branches deleted. Branches without an open PR that are older
+than --older-than are deleted.
+
+With --reopen, open onboarding PRs older than --older-than are closed too and a
+fresh PR is opened in their place using the current configuration.`,
+	RunE: runCleanup,
+}
+
+func init() {
+	cleanupCmd.Flags().String("older-than", "14d", "Age after which branches without a PR are deleted (e.g. 14d, 72h)")
+	cleanupCmd.Flags().Bool("reopen", false, "Replace open onboarding PRs older than --older-than with a fresh PR")
+	cleanupCmd.Flags().Bool("dry-run", false, "Show what would be closed and deleted without changing anything")
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	age, err := parseAge(olderThan)
+	if err != nil {
+		return err
+	}
+	reopen, _ := cmd.Flags().GetBool("reopen")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := validateConnectionConfig(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	// Reopening generates catalogs, so it needs everything yaml mode needs
+	if reopen {
+		if config.Defaults.Owner == "" {
+			return fmt.Errorf("config validation failed: default owner is required for --reopen")
+		}
+		for _, load := range []func() error{loadRepoOverrides, loadRules, loadProjectRoutes, loadIdentifierTemplate, loadPipelineTemplate, loadBadgeTemplate, loadOwnersMap, loadSystems} {
+			if err := load(); err != nil {
+				return err
+			}
+		}
+		if err := initClients(); err != nil {
+			return err
+		}
+	} else {
+		githubClient, err = newGitHubClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	repos, err := discoverRepositories(ctx, reopen)
+	if err != nil {
+		return err
+	}
+	if reopen {
+		indexModules(repos)
+	}
+
+	cutoff := time.Now().Add(-age)
+	log.Printf("Cleaning up onboarding branches in %d repositories (older than %s)", len(repos), olderThan)
+
+	summary := errors.NewErrorSummary()
+	for _, repo := range repos {
+		throttle(ctx)
+		summary.AddResult(cleanupRepository(ctx, repo, cutoff, reopen, dryRun))
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during CLEANUP processing", summary.Total)
+	}
+	return nil
+}
+
+// cleanupRepository closes superseded onboarding PRs and deletes their branches and
+// stale branches without a PR, then opens a fresh PR when reopening
+func cleanupRepository(ctx context.Context, repo models.Repository, cutoff time.Time, reopen, dryRun bool) errors.ProcessingResult {
+	failed := func(err error) errors.ProcessingResult {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    "Cleanup failed",
+			Action:     "failed",
+		}
+	}
+
+	branches, err := githubClient.ListOnboardingBranches(ctx, repo)
+	if err != nil {
+		return failed(err)
+	}
+
+	var withPR []github.OnboardingBranch
+	var stale []github.OnboardingBranch
+	for _, branch := range branches {
+		if branch.PRNumber != 0 {
+			withPR = append(withPR, branch)
+		} else if !branch.CreatedAt.IsZero() && branch.CreatedAt.Before(cutoff) {
+			stale = append(stale, branch)
+		}
+	}
+
+	// Newest first; everything after the first open PR is superseded
+	sort.Slice(withPR, func(i, j int) bool { return withPR[i].CreatedAt.After(withPR[j].CreatedAt) })
+	var superseded []github.OnboardingBranch
+	if len(withPR) > 1 {
+		superseded = withPR[1:]
+	}
+	replace := reopen && len(withPR) > 0 && withPR[0].CreatedAt.Before(cutoff)
+	if replace {
+		superseded = withPR
+	}
+
+	if len(superseded) == 0 && len(stale) == 0 {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    "Nothing to clean up",
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+
+	var actions []string
+	for _, branch := range superseded {
+		comment := "Closed by harness-onboarder: replaced by a fresh onboarding PR."
+		if !replace {
+			comment = fmt.Sprintf("Closed by harness-onboarder: superseded by #%d.", withPR[0].PRNumber)
+		}
+		if !dryRun {
+			if err := githubClient.ClosePR(ctx, repo, branch.PRNumber, comment); err != nil {
+				return failed(err)
+			}
+			if err := githubClient.DeleteBranch(ctx, repo, branch.Name); err != nil {
+				return failed(err)
+			}
+		}
+		actions = append(actions, fmt.Sprintf("closed PR #%d", branch.PRNumber))
+	}
+	for _, branch := range stale {
+		if !dryRun {
+			if err := githubClient.DeleteBranch(ctx, repo, branch.Name); err != nil {
+				return failed(err)
+			}
+		}
+		actions = append(actions, fmt.Sprintf("deleted %s", branch.Name))
+	}
+
+	message := strings.Join(actions, ", ")
+	if dryRun {
+		log.Printf("[dry-run] %s: would have %s", repo.FullName, message)
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    "Would have " + message,
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+	log.Printf("%s: %s", repo.FullName, message)
+
+	if replace {
+		result := processRepositoryYAMLWithResult(ctx, repo)
+		result.Message = message + "; " + result.Message
+		return result
+	}
+
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Message:    strings.ToUpper(message[:1]) + message[1:],
+		Action:     "cleaned",
+	}
+}
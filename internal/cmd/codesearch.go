@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"path"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/models"
+)
+
+// catalogIndex holds the catalog files found by --code-search, nil when repositories
+// are probed path by path
+var catalogIndex *github.CatalogIndex
+
+// indexCatalogFiles searches the organization for catalog files once, before register
+// mode looks for them repository by repository. When the search fails every
+// repository is probed as without --code-search.
+func indexCatalogFiles(ctx context.Context) {
+	if !config.Runtime.CodeSearch {
+		return
+	}
+
+	var filenames []string
+	for _, p := range catalogSearchPaths() {
+		if name := path.Base(p); !contains(filenames, name) {
+			filenames = append(filenames, name)
+		}
+	}
+
+	index, err := githubClient.SearchCatalogFiles(ctx, config.GitHub.Organization, filenames)
+	if err != nil {
+		log.Printf("Warning: %v; probing each repository instead", err)
+		return
+	}
+	catalogIndex = index
+}
+
+// indexedCatalogPaths returns the catalog search paths code search or the enrichment
+// tree found in a repository, in search order, and false when the repository has to
+// be probed
+func indexedCatalogPaths(repo models.Repository) ([]string, bool) {
+	if repo.CatalogFiles != nil {
+		return repo.CatalogFiles, true
+	}
+	if catalogIndex == nil {
+		return nil, false
+	}
+	var paths []string
+	for _, p := range catalogSearchPaths() {
+		if contains(catalogIndex.Paths[repo.FullName], p) {
+			paths = append(paths, p)
+		}
+	}
+	// Partial results prove only that a file exists, not that it doesn't
+	if len(paths) == 0 && !catalogIndex.Complete {
+		return nil, false
+	}
+	return paths, true
+}
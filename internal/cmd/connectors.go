@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/harness"
+)
+
+// defaultConnectorRef names the connector --provision-connector creates when no
+// connector_ref is configured
+const defaultConnectorRef = "account.harness_onboarder_github"
+
+// harnessTargets returns the default Harness client followed by every target's, by name
+func harnessTargets() ([]string, []*harness.Client) {
+	names := make([]string, 0, len(targetClients))
+	for name := range targetClients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clients := []*harness.Client{harnessClient}
+	for _, name := range names {
+		clients = append(clients, targetClients[name])
+	}
+	return append([]string{""}, names...), clients
+}
+
+// checkConnectors verifies the GitHub connector of the default Harness config and of
+// every target before register mode imports anything
+func checkConnectors(ctx context.Context) error {
+	names, clients := harnessTargets()
+	for i, client := range clients {
+		if err := client.CheckGitHubConnector(ctx, config.GitHub.Organization); err != nil {
+			return targetError(names[i], err)
+		}
+	}
+	return nil
+}
+
+// provisionConnectors creates or updates the GitHub connector of the default Harness
+// config and of every target from the GitHub App credentials, when
+// --provision-connector is set
+func provisionConnectors(ctx context.Context) error {
+	if !config.Runtime.ProvisionConnector {
+		return nil
+	}
+	if config.Runtime.DryRun {
+		log.Printf("Would provision GitHub connector %s", config.Harness.ConnectorRef)
+		return nil
+	}
+
+	key, err := github.PrivateKeyPEM(config.GitHub)
+	if err != nil {
+		return fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	app := harness.GitHubAppConnector{
+		Organization: config.GitHub.Organization,
+		AppID:        config.GitHub.AppID,
+		InstallID:    config.GitHub.InstallID,
+		PrivateKey:   key,
+	}
+
+	names, clients := harnessTargets()
+	for i, client := range clients {
+		if err := client.ProvisionGitHubConnector(ctx, app); err != nil {
+			return targetError(names[i], err)
+		}
+	}
+	return nil
+}
+
+// targetError prefixes an error with the target it happened for; the default Harness
+// config has no name
+func targetError(name string, err error) error {
+	if name == "" {
+		return err
+	}
+	return fmt.Errorf("target %q: %w", name, err)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// moduleIndex maps module names published by discovered repositories to the
+// identifiers of their components
+var moduleIndex = make(map[string]string)
+
+// indexModules records which repository publishes each module, so dependencies
+// between repositories in the organization can be resolved
+func indexModules(repos []models.Repository) {
+	moduleIndex = make(map[string]string)
+	for _, repo := range repos {
+		for _, module := range repo.Modules {
+			moduleIndex[module] = repoIdentifier(repo)
+		}
+	}
+
+	if len(moduleIndex) > 0 {
+		log.Printf("Indexed %d modules for dependency resolution", len(moduleIndex))
+	}
+}
+
+// repoDependsOn returns dependsOn references to the components of other
+// organization repositories that this repository depends on
+func repoDependsOn(repo models.Repository) []string {
+	self := repoIdentifier(repo)
+	seen := make(map[string]bool)
+
+	var dependsOn []string
+	for _, dep := range repo.Dependencies {
+		identifier, ok := moduleIndex[dep]
+		if !ok {
+			identifier, ok = goModuleRepo(dep)
+		}
+		if !ok || identifier == self || seen[identifier] {
+			continue
+		}
+		seen[identifier] = true
+		dependsOn = append(dependsOn, "component:"+identifier)
+	}
+
+	sort.Strings(dependsOn)
+	return dependsOn
+}
+
+// goModuleRepo resolves Go modules hosted in the organization, such as
+// github.com/<org>/<repo>/v2, even when that repository wasn't discovered in this run
+func goModuleRepo(module string) (string, bool) {
+	prefix := strings.ToLower("github.com/" + config.GitHub.Organization + "/")
+	if config.GitHub.Organization == "" || !strings.HasPrefix(strings.ToLower(module), prefix) {
+		return "", false
+	}
+
+	name := strings.SplitN(module[len(prefix):], "/", 2)[0]
+	if name == "" {
+		return "", false
+	}
+	return repoIdentifier(models.Repository{Name: name}), true
+}
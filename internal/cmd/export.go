@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/errors"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write generated catalog-info.yaml files to a local directory",
+	Long: `Runs discovery and catalog generation like YAML mode, but writes one
+<repo>.yaml file per repository to a local directory instead of opening pull
+requests, so the files can be reviewed and committed through your own workflow.`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().String("output-dir", "catalog-infos", "Directory to write generated catalog files to")
+	exportCmd.Flags().Bool("overwrite", false, "Overwrite files that already exist in the output directory")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if config.GitHub.Organization == "" {
+		return fmt.Errorf("config validation failed: GitHub organization is required")
+	}
+	if config.Defaults.Owner == "" {
+		return fmt.Errorf("config validation failed: default owner is required")
+	}
+
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+	if err := loadRepoOverrides(); err != nil {
+		return err
+	}
+	if err := loadReposFile(); err != nil {
+		return err
+	}
+	if err := loadRules(); err != nil {
+		return err
+	}
+	if err := loadProjectRoutes(); err != nil {
+		return err
+	}
+	if err := loadIdentifierTemplate(); err != nil {
+		return err
+	}
+	if err := loadOwnersMap(); err != nil {
+		return err
+	}
+	if err := loadSystems(); err != nil {
+		return err
+	}
+
+	var err error
+	githubClient, err = newGitHubClient()
+	if err != nil {
+		return err
+	}
+
+	// Enrich so owners and signals match what YAML mode would generate
+	repos, err := discoverRepositories(ctx, true)
+	if err != nil {
+		return err
+	}
+	indexModules(repos)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	log.Printf("Exporting catalog files for %d repositories to %s", len(repos), outputDir)
+
+	summary := errors.NewErrorSummary()
+	for _, repo := range repos {
+		path := filepath.Join(outputDir, repo.Name+".yaml")
+
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			summary.AddResult(errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    true,
+				Message:    fmt.Sprintf("%s already exists", path),
+				Skipped:    true,
+				Action:     "skipped",
+			})
+			continue
+		}
+
+		content, err := generateCatalogYAML(repo)
+		if err == nil {
+			err = os.WriteFile(path, []byte(content), 0644)
+		}
+		if err != nil {
+			summary.AddResult(errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      errors.CategorizeError(err, repo.FullName),
+				Message:    "Export failed",
+				Action:     "failed",
+			})
+			continue
+		}
+
+		summary.AddResult(errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    fmt.Sprintf("Wrote %s", path),
+			Action:     "exported",
+		})
+	}
+
+	summary.PrintSummary()
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during export", summary.Total)
+	}
+
+	return nil
+}
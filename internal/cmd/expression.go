@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+
+	"harness-onboarder/internal/models"
+)
+
+// repoFilter is the compiled --filter expression, nil when none is set
+var repoFilter cel.Program
+
+// compileRepoFilter compiles a CEL expression over the repository, available as repo
+// with the fields of the Repository model, e.g.
+// repo.Language == "Go" && !repo.Archived && "platform" in repo.Topics
+func compileRepoFilter(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Variable("repo", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid --filter expression: %w", issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("--filter expression must be a boolean, not %s", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// filterMatches evaluates --filter for a repository. Repositories the expression
+// fails for, such as by reading a missing custom property, are skipped.
+func filterMatches(repo models.Repository) bool {
+	out, _, err := repoFilter.Eval(map[string]interface{}{"repo": repoFields(repo)})
+	if err != nil {
+		log.Printf("Warning: --filter failed for %s, skipping it: %v", repo.FullName, err)
+		return false
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		log.Printf("Warning: --filter returned %v for %s instead of a boolean, skipping it", out.Value(), repo.FullName)
+	}
+	return ok && matched
+}
+
+// repoFields maps the Repository model's field names to their values. Nil slices and
+// maps become empty ones so expressions like "x" in repo.Topics don't fail.
+func repoFields(repo models.Repository) map[string]interface{} {
+	fields := make(map[string]interface{})
+	value := reflect.ValueOf(repo)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		switch {
+		case field.Kind() == reflect.Slice && field.IsNil():
+			fields[value.Type().Field(i).Name] = reflect.MakeSlice(field.Type(), 0, 0).Interface()
+		case field.Kind() == reflect.Map && field.IsNil():
+			fields[value.Type().Field(i).Name] = reflect.MakeMap(field.Type()).Interface()
+		default:
+			fields[value.Type().Field(i).Name] = field.Interface()
+		}
+	}
+	return fields
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"harness-onboarder/internal/models"
+)
+
+// repoSelected applies the attribute filters to a discovered repository, after the
+// include/exclude lists and the archived check
+func repoSelected(repo models.Repository) bool {
+	if len(config.Runtime.IncludeTopics) > 0 && !hasAnyTopic(repo, config.Runtime.IncludeTopics) {
+		return false
+	}
+	if hasAnyTopic(repo, config.Runtime.ExcludeTopics) {
+		return false
+	}
+	if len(config.Runtime.Visibility) > 0 && !contains(config.Runtime.Visibility, repo.Visibility) {
+		return false
+	}
+	// Forks, templates and mirrors are copies nobody owns as a service
+	if config.Runtime.ExcludeForks && repo.Fork ||
+		config.Runtime.ExcludeTemplates && repo.IsTemplate ||
+		config.Runtime.ExcludeMirrors && repo.MirrorURL != "" {
+		return false
+	}
+	if activeWithin > 0 && time.Since(repo.PushedAt) > activeWithin {
+		return false
+	}
+	if repo.Stars < config.Runtime.MinStars || repo.SizeKB < config.Runtime.MinSizeKB {
+		return false
+	}
+	if config.Runtime.MaxSizeKB > 0 && repo.SizeKB > config.Runtime.MaxSizeKB {
+		return false
+	}
+	if repoFilter != nil && !filterMatches(repo) {
+		return false
+	}
+	if config.Runtime.Team != "" && !teamRepos[repo.Name] {
+		return false
+	}
+	return true
+}
+
+// teamRepos holds the repositories --team can write to, loaded by loadTeamRepositories
+var teamRepos map[string]bool
+
+// loadTeamRepositories looks up the repositories --team has admin or write access to,
+// so each team can onboard its own repositories in self-service runs. It's called on
+// every discovery since access changes between daemon runs.
+func loadTeamRepositories(ctx context.Context) error {
+	if config.Runtime.Team == "" {
+		return nil
+	}
+	names, err := githubClient.ListTeamRepositories(ctx, config.GitHub.Organization, config.Runtime.Team)
+	if err != nil {
+		return err
+	}
+	teamRepos = make(map[string]bool, len(names))
+	for _, name := range names {
+		teamRepos[name] = true
+	}
+	log.Printf("Team %s can write to %d repositories", config.Runtime.Team, len(names))
+	return nil
+}
+
+// activeWithin is the parsed --active-within, 0 when every repository is active
+var activeWithin time.Duration
+
+// sortAndLimit puts the repositories in --sort order and keeps the first --limit of
+// them, so pilot runs pick the same repositories every time
+func sortAndLimit(repos []models.Repository) []models.Repository {
+	switch config.Runtime.Sort {
+	case "pushed":
+		sort.SliceStable(repos, func(i, j int) bool {
+			if !repos[i].PushedAt.Equal(repos[j].PushedAt) {
+				return repos[i].PushedAt.After(repos[j].PushedAt)
+			}
+			return repos[i].FullName < repos[j].FullName
+		})
+	default:
+		sort.SliceStable(repos, func(i, j int) bool { return repos[i].FullName < repos[j].FullName })
+	}
+
+	if config.Runtime.Limit > 0 && len(repos) > config.Runtime.Limit {
+		log.Printf("Limiting the run to %d of %d repositories", config.Runtime.Limit, len(repos))
+		repos = repos[:config.Runtime.Limit]
+	}
+	return repos
+}
+
+// repoSorts are the values --sort accepts
+var repoSorts = []string{"name", "pushed"}
+
+// repoVisibilities are the values --visibility accepts
+var repoVisibilities = []string{"public", "private", "internal"}
+
+// validateFilters checks the repository filter options
+func validateFilters() error {
+	if err := validateRepoPatterns(); err != nil {
+		return err
+	}
+
+	for i, visibility := range config.Runtime.Visibility {
+		visibility = strings.ToLower(strings.TrimSpace(visibility))
+		if !contains(repoVisibilities, visibility) {
+			return fmt.Errorf("unsupported visibility: %s (supported: %s)", visibility, strings.Join(repoVisibilities, ", "))
+		}
+		config.Runtime.Visibility[i] = visibility
+	}
+
+	if config.Runtime.ActiveWithin != "" {
+		age, err := parseAge(config.Runtime.ActiveWithin)
+		if err != nil {
+			return fmt.Errorf("--active-within: %w", err)
+		}
+		activeWithin = age
+	}
+
+	if config.Runtime.MinStars < 0 || config.Runtime.MinSizeKB < 0 || config.Runtime.MaxSizeKB < 0 {
+		return fmt.Errorf("--min-stars, --min-size-kb and --max-size-kb must not be negative")
+	}
+	if config.Runtime.MaxSizeKB > 0 && config.Runtime.MaxSizeKB < config.Runtime.MinSizeKB {
+		return fmt.Errorf("--max-size-kb must not be less than --min-size-kb")
+	}
+
+	if config.Runtime.Sort != "" && !contains(repoSorts, config.Runtime.Sort) {
+		return fmt.Errorf("unsupported sort: %s (supported: %s)", config.Runtime.Sort, strings.Join(repoSorts, ", "))
+	}
+	if config.Runtime.Limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if config.Runtime.Shard != "" {
+		parsed, err := parseShard(config.Runtime.Shard)
+		if err != nil {
+			return err
+		}
+		runShard = parsed
+	}
+
+	if config.Runtime.Filter != "" {
+		program, err := compileRepoFilter(config.Runtime.Filter)
+		if err != nil {
+			return err
+		}
+		repoFilter = program
+	}
+	return nil
+}
+
+// hasAnyTopic reports whether the repository carries any of the topics, ignoring case
+func hasAnyTopic(repo models.Repository, topics []string) bool {
+	for _, topic := range repo.Topics {
+		for _, t := range topics {
+			if strings.EqualFold(topic, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// harnessCodeSource reports whether repositories are discovered in Harness Code
+// instead of GitHub
+func harnessCodeSource() bool {
+	return config.Runtime.Source == harness.SourceHarnessCode
+}
+
+// validateSource checks the repository source and the options it supports. Harness
+// Code repositories are discovered and read through the Harness API and imported
+// without a connector, so only register mode and GitHub-free options work with them.
+func validateSource() error {
+	switch config.Runtime.Source {
+	case "github":
+		return nil
+	case harness.SourceHarnessCode:
+	default:
+		return fmt.Errorf("unsupported source: %s (supported: github, harness-code)", config.Runtime.Source)
+	}
+
+	if config.Runtime.Mode != "register" {
+		return fmt.Errorf("--source harness-code only supports register mode")
+	}
+	if config.Harness.ProjectID == "" {
+		return fmt.Errorf("--source harness-code requires the Harness project ID whose repositories are registered")
+	}
+	for flag, set := range map[string]bool{
+		"--legacy-strategy pr":  config.Runtime.LegacyStrategy == "pr",
+		"--catalog-repo":        config.GitHub.CatalogRepo != "",
+		"--only-failed":         config.Runtime.OnlyFailed,
+		"--onboarded-topic":     config.Runtime.OnboardedTopic != "",
+		"--sync-teams":          config.Runtime.SyncTeams,
+		"--provision-connector": config.Runtime.ProvisionConnector,
+		"--code-search":         config.Runtime.CodeSearch,
+		"--team":                config.Runtime.Team != "",
+		"--search":              config.Runtime.Search != "",
+		"--stream":              config.Runtime.Stream,
+	} {
+		if set {
+			return fmt.Errorf("%s is not supported with --source harness-code", flag)
+		}
+	}
+	return nil
+}
+
+// discoverHarnessCodeRepositories lists the configured project's Harness Code
+// repositories and applies the configured filters
+func discoverHarnessCodeRepositories(ctx context.Context) ([]models.Repository, error) {
+	repos, err := harnessClient.ListCodeRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover repositories: %w", err)
+	}
+
+	filteredRepos := filterRepositories(repos)
+	log.Printf("Found %d Harness Code repositories, %d after filtering", len(repos), len(filteredRepos))
+	return filteredRepos, nil
+}
+
+// harnessCodeCatalog returns the path and content of a Harness Code repository's
+// catalog file, searching the same locations as for GitHub repositories
+func harnessCodeCatalog(ctx context.Context, repo models.Repository) (string, string, error) {
+	for _, path := range catalogSearchPaths() {
+		content, found, err := harnessClient.GetCodeFile(ctx, repo, repo.DefaultBranch, path)
+		if err != nil {
+			return "", "", fmt.Errorf("error checking %s: %w", path, err)
+		}
+		if found {
+			log.Printf("Found catalog file in %s at path: %s", repo.FullName, path)
+			return path, content, nil
+		}
+	}
+	return "", "", fmt.Errorf("no catalog-info.yaml file found in %s", repo.FullName)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/models"
+)
+
+// identifierTemplate is the parsed --identifier-template, nil when unset
+var identifierTemplate *template.Template
+
+// identifierData is what identifier templates are rendered against. Repository fields
+// such as .Name and .Language are available alongside .Org and .Repo.
+type identifierData struct {
+	models.Repository
+	Org  string
+	Repo string
+}
+
+var wordBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+var wordSeparators = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+var identifierFuncs = template.FuncMap{
+	"snakecase": func(s string) string { return joinWords(s, "_") },
+	"kebabcase": func(s string) string { return joinWords(s, "-") },
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+}
+
+// joinWords lowercases s and joins its words with sep, splitting on camelCase
+// boundaries and any non-alphanumeric characters
+func joinWords(s, sep string) string {
+	s = wordBoundary.ReplaceAllString(s, "${1} ${2}")
+	words := strings.Fields(wordSeparators.ReplaceAllString(s, " "))
+	return strings.ToLower(strings.Join(words, sep))
+}
+
+// loadIdentifierTemplate parses the identifier template, if configured
+func loadIdentifierTemplate() error {
+	identifierTemplate = nil
+	if config.Runtime.IdentifierTemplate == "" {
+		return nil
+	}
+
+	tmpl, err := template.New("identifier").Funcs(identifierFuncs).Option("missingkey=error").Parse(config.Runtime.IdentifierTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid identifier template: %w", err)
+	}
+	identifierTemplate = tmpl
+	return nil
+}
+
+// templatedIdentifier renders the identifier template and applies the prefix and
+// suffix. The result is coerced into a valid Harness identifier.
+func templatedIdentifier(repo models.Repository) string {
+	name := strings.ReplaceAll(sanitizeName(repo.Name), "-", "_")
+	if identifierTemplate != nil {
+		data := identifierData{Repository: repo, Org: config.GitHub.Organization, Repo: repo.Name}
+		var buf bytes.Buffer
+		if err := identifierTemplate.Execute(&buf, data); err != nil {
+			// Fall back to the default so one bad repository doesn't stop the run
+			log.Printf("Warning: failed to render identifier for %s: %v", repo.FullName, err)
+		} else if rendered := strings.TrimSpace(buf.String()); rendered != "" {
+			name = rendered
+		}
+	}
+	return catalog.ToIdentifier(config.Runtime.IdentifierPrefix + name + config.Runtime.IdentifierSuffix)
+}
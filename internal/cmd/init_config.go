@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/harness"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively create a config.yaml",
+	Long: `Prompts for GitHub App details, the Harness account, organization and project,
+and component defaults, checks the credentials against both APIs, and writes a
+config file that can be used for subsequent runs.`,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().String("output", "config.yaml", "Path of the config file to write")
+	initCmd.Flags().Bool("force", false, "Overwrite the output file if it already exists")
+	initCmd.Flags().Bool("skip-validation", false, "Don't check credentials against GitHub and Harness")
+	rootCmd.AddCommand(initCmd)
+}
+
+// prompter reads answers from the terminal, offering a default for each question
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, _ := p.in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+func (p *prompter) askRequired(question, def string) string {
+	for {
+		if answer := p.ask(question, def); answer != "" {
+			return answer
+		}
+		fmt.Fprintln(p.out, "  A value is required.")
+	}
+}
+
+func (p *prompter) askInt(question string, def int64) int64 {
+	defStr := ""
+	if def != 0 {
+		defStr = strconv.FormatInt(def, 10)
+	}
+	for {
+		answer := p.askRequired(question, defStr)
+		value, err := strconv.ParseInt(answer, 10, 64)
+		if err == nil && value > 0 {
+			return value
+		}
+		fmt.Fprintln(p.out, "  Please enter a positive number.")
+	}
+}
+
+func (p *prompter) confirm(question string) bool {
+	answer := strings.ToLower(p.ask(question+" (y/N)", ""))
+	return answer == "y" || answer == "yes"
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	output, _ := cmd.Flags().GetString("output")
+	force, _ := cmd.Flags().GetBool("force")
+	skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+
+	if _, err := os.Stat(output); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+
+	// Start from whatever was already loaded from flags, environment or an existing file
+	cfg := config
+
+	fmt.Println("GitHub App")
+	for {
+		cfg.GitHub.Organization = p.askRequired("  Organization or user", cfg.GitHub.Organization)
+		cfg.GitHub.AppID = p.askInt("  App ID", cfg.GitHub.AppID)
+		cfg.GitHub.InstallID = p.askInt("  Installation ID", cfg.GitHub.InstallID)
+		cfg.GitHub.PrivateKey = p.askRequired("  Private key file path", cfg.GitHub.PrivateKey)
+
+		if skipValidation {
+			break
+		}
+		client, err := github.NewClient(cfg.GitHub)
+		if err == nil {
+			err = client.ValidateAccess(ctx, cfg.GitHub.Organization)
+		}
+		if err == nil {
+			fmt.Println("  ✅ GitHub App credentials verified")
+			break
+		}
+		fmt.Printf("  ❌ %v\n", err)
+		if p.confirm("  Keep these values anyway?") {
+			break
+		}
+	}
+
+	fmt.Println("\nHarness")
+	for {
+		cfg.Harness.APIKey = p.askRequired("  API key", cfg.Harness.APIKey)
+		cfg.Harness.AccountID = p.askRequired("  Account ID", cfg.Harness.AccountID)
+		cfg.Harness.OrgID = p.askRequired("  Organization ID", orDefault(cfg.Harness.OrgID, "default"))
+		cfg.Harness.ProjectID = p.askRequired("  Project ID", cfg.Harness.ProjectID)
+		cfg.Harness.BaseURL = p.askRequired("  Base URL", orDefault(cfg.Harness.BaseURL, "https://app.harness.io"))
+		cfg.Harness.ConnectorRef = p.ask("  GitHub connector reference (register mode)", cfg.Harness.ConnectorRef)
+
+		if skipValidation {
+			break
+		}
+		client, err := harness.NewClient(cfg.Harness)
+		if err == nil {
+			err = client.ValidateConnection(ctx)
+		}
+		if err == nil {
+			fmt.Println("  ✅ Harness connection verified")
+			break
+		}
+		fmt.Printf("  ❌ %v\n", err)
+		if p.confirm("  Keep these values anyway?") {
+			break
+		}
+	}
+
+	fmt.Println("\nComponent defaults")
+	cfg.Defaults.Owner = p.askRequired("  Default owner", cfg.Defaults.Owner)
+	cfg.Defaults.Type = p.askRequired("  Default type", orDefault(cfg.Defaults.Type, "service"))
+	cfg.Defaults.Lifecycle = p.askRequired("  Default lifecycle", orDefault(cfg.Defaults.Lifecycle, "production"))
+	cfg.Defaults.System = p.ask("  Default system", cfg.Defaults.System)
+
+	fmt.Println("\nRuntime")
+	cfg.Runtime.Mode = p.askRequired("  Mode (yaml, api, register)", orDefault(cfg.Runtime.Mode, "yaml"))
+
+	// Written as an ordered map in section order. Config files are decoded by matching
+	// keys against the Go field names, so multi-word keys are run together.
+	doc := yaml.MapSlice{
+		{Key: "github", Value: yaml.MapSlice{
+			{Key: "organization", Value: cfg.GitHub.Organization},
+			{Key: "appid", Value: cfg.GitHub.AppID},
+			{Key: "privatekey", Value: cfg.GitHub.PrivateKey},
+			{Key: "installid", Value: cfg.GitHub.InstallID},
+		}},
+		{Key: "harness", Value: yaml.MapSlice{
+			{Key: "apikey", Value: cfg.Harness.APIKey},
+			{Key: "accountid", Value: cfg.Harness.AccountID},
+			{Key: "orgid", Value: cfg.Harness.OrgID},
+			{Key: "projectid", Value: cfg.Harness.ProjectID},
+			{Key: "baseurl", Value: cfg.Harness.BaseURL},
+			{Key: "connectorref", Value: cfg.Harness.ConnectorRef},
+		}},
+		{Key: "defaults", Value: yaml.MapSlice{
+			{Key: "owner", Value: cfg.Defaults.Owner},
+			{Key: "type", Value: cfg.Defaults.Type},
+			{Key: "lifecycle", Value: cfg.Defaults.Lifecycle},
+			{Key: "system", Value: cfg.Defaults.System},
+		}},
+		{Key: "runtime", Value: yaml.MapSlice{
+			{Key: "mode", Value: cfg.Runtime.Mode},
+			{Key: "concurrency", Value: orDefaultInt(cfg.Runtime.Concurrency, 5)},
+			{Key: "loglevel", Value: "info"},
+		}},
+	}
+
+	content, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	header := "# Generated by harness-onboarder init\n# The API key is stored in plain text; consider HARNESS_ONBOARDER_HARNESS_API_KEY instead.\n\n"
+	if err := os.WriteFile(output, append([]byte(header), content...), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("\n✅ Wrote %s\n", output)
+	fmt.Printf("   Try: ./harness-onboarder --config %s --dry-run\n", output)
+	return nil
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+func orDefaultInt(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// apiTypes are the API entity types a configured or inferred type is kept as; any
+// other type, such as the inferred service, becomes openapi
+var apiTypes = []string{"openapi", "asyncapi", "graphql", "grpc"}
+
+// validateKind checks that an entity kind from the config or a rule is supported
+func validateKind(kind string) error {
+	if kind == "" || contains(harness.EntityKinds, kind) {
+		return nil
+	}
+	return fmt.Errorf("unsupported kind: %s (supported: %s)", kind, strings.Join(harness.EntityKinds, ", "))
+}
+
+// apiDefinitionURL returns the URL of a repository's OpenAPI definition on GitHub
+func apiDefinitionURL(repo models.Repository) string {
+	return fmt.Sprintf("%s/blob/%s/%s", repo.HTMLURL, repo.DefaultBranch, repo.APISpecPath)
+}
+
+// applyKindSpec replaces the Component spec fields of a repository's entity with those
+// of its kind. An API entity is the repository's API definition itself, a System
+// belongs to the configured domain and a Group's members are its CODEOWNERS users.
+func applyKindSpec(repo models.Repository, defaults models.DefaultsConfig, component *models.HarnessComponent) {
+	switch component.Kind {
+	case "API":
+		if !contains(apiTypes, component.Type) {
+			component.Type = "openapi"
+		}
+		if repo.APISpecPath != "" {
+			component.Definition = apiDefinitionURL(repo)
+		}
+		component.DependsOn = nil
+		component.ProvidesAPIs = nil
+	case "System":
+		component.Type = ""
+		component.Lifecycle = ""
+		component.System = ""
+		component.Domain = defaults.Domain
+		component.DependsOn = nil
+		component.ProvidesAPIs = nil
+	case "Group":
+		component.Type = "team"
+		component.Lifecycle = ""
+		component.System = ""
+		component.DependsOn = nil
+		component.ProvidesAPIs = nil
+		component.Members = codeOwnerMembers(repo)
+	}
+}
+
+// codeOwnerMembers maps a repository's CODEOWNERS users, skipping teams, to Harness users
+func codeOwnerMembers(repo models.Repository) []string {
+	var members []string
+	for _, handle := range repo.CodeOwners {
+		login := normalizeHandle(handle)
+		if login == "" || strings.ContainsAny(login, "/@") {
+			continue
+		}
+		if owner, ok := mapOwner(login); ok {
+			login = owner
+		}
+		if !contains(members, login) {
+			members = append(members, login)
+		}
+	}
+	return members
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import "harness-onboarder/internal/models"
+
+// applyKubernetesAnnotations points the Kubernetes plugin at the pods and namespace
+// found in the repository's manifests or Helm chart. Annotations already set, such as
+// by rules, are kept.
+func applyKubernetesAnnotations(repo models.Repository, annotations map[string]string) {
+	if repo.K8sSelector != "" {
+		setDefaultAnnotation(annotations, "backstage.io/kubernetes-label-selector", repo.K8sSelector)
+	}
+	if repo.K8sNamespace != "" {
+		setDefaultAnnotation(annotations, "backstage.io/kubernetes-namespace", repo.K8sNamespace)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"sort"
+
+	"harness-onboarder/internal/models"
+)
+
+// significantLanguages returns the repository's languages making up at least
+// --language-threshold percent of its code, largest first. Without a language
+// breakdown it falls back to the primary language.
+func significantLanguages(repo models.Repository) []string {
+	total := 0
+	for _, bytes := range repo.Languages {
+		total += bytes
+	}
+	if total == 0 {
+		if repo.Language == "" {
+			return nil
+		}
+		return []string{repo.Language}
+	}
+
+	var languages []string
+	for language, bytes := range repo.Languages {
+		if float64(bytes)*100/float64(total) >= config.Runtime.LanguageThreshold {
+			languages = append(languages, language)
+		}
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		a, b := repo.Languages[languages[i]], repo.Languages[languages[j]]
+		if a != b {
+			return a > b
+		}
+		return languages[i] < languages[j]
+	})
+
+	// The primary language is always kept, even below the threshold
+	if repo.Language != "" && !contains(languages, repo.Language) {
+		languages = append([]string{repo.Language}, languages...)
+	}
+	return languages
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"time"
+
+	"harness-onboarder/internal/models"
+)
+
+// inferLifecycle guesses the lifecycle from the repository's state. Archived
+// repositories are deprecated; repositories with an experimental topic or created in
+// the last --experimental-days are experimental. It returns an empty string otherwise.
+func inferLifecycle(repo models.Repository, now time.Time) string {
+	if repo.Archived {
+		return "deprecated"
+	}
+
+	for _, topic := range config.Defaults.ExperimentalTopics {
+		if contains(repo.Topics, topic) {
+			return "experimental"
+		}
+	}
+
+	days := config.Defaults.ExperimentalDays
+	if days > 0 && !repo.CreatedAt.IsZero() && now.Sub(repo.CreatedAt) < time.Duration(days)*24*time.Hour {
+		return "experimental"
+	}
+
+	return ""
+}
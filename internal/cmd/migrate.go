@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+func processMigrateMode(ctx context.Context, repos []models.Repository) error {
+	log.Printf("Processing %d repositories in MIGRATE mode", len(repos))
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan errors.ProcessingResult, len(repos))
+
+	for _, repo := range repos {
+		go func(r models.Repository) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			result := retryOnSecondaryLimit(ctx, r, processRepositoryMigrateWithResult)
+			recordState(r, result)
+			results <- result
+		}(repo)
+	}
+
+	summary := newRunSummary()
+	for i := 0; i < len(repos); i++ {
+		result := <-results
+		summary.AddResult(result)
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during MIGRATE processing", summary.Total)
+	}
+
+	return nil
+}
+
+func processRepositoryMigrateWithResult(ctx context.Context, repo models.Repository) errors.ProcessingResult {
+	log.Printf("Processing repository %s in MIGRATE mode", repo.FullName)
+
+	catalogPath, catalogContent, err := getCatalogInfoPathAndContent(ctx, repo)
+	if err != nil {
+		log.Printf("Skipping %s: %v", repo.FullName, err)
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Error:      nil,
+			Message:    "No catalog-info.yaml found",
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+
+	if !catalog.IsLegacy(catalogContent) {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Error:      nil,
+			Message:    "Catalog file already uses harness.io/v1",
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+
+	return migrateCatalog(ctx, repo, catalogPath, catalogContent)
+}
+
+// migrateCatalog opens a PR converting a legacy catalog file to harness.io/v1
+func migrateCatalog(ctx context.Context, repo models.Repository, catalogPath, catalogContent string) errors.ProcessingResult {
+	existingPR, err := githubClient.CheckForExistingOnboardingPR(ctx, repo)
+	if err != nil {
+		log.Printf("DEBUG: Error checking for existing PRs in %s: %v", repo.FullName, err)
+	}
+	if existingPR != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Error:      nil,
+			Message:    fmt.Sprintf("Open PR #%d already exists (%s)", existingPR.GetNumber(), existingPR.GetTitle()),
+			Skipped:    true,
+			Action:     "skipped",
+			URL:        existingPR.GetHTMLURL(),
+		}
+	}
+
+	converted, err := catalog.ConvertLegacy(catalogContent, convertOptions(repo))
+	if err == nil {
+		if issues := catalog.Lint(converted); catalog.HasErrors(issues) {
+			err = fmt.Errorf("converted catalog is invalid: %s", issues[0])
+		}
+	}
+	if err != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    "Catalog conversion failed",
+			Action:     "failed",
+		}
+	}
+
+	identifier, _ := harness.ExtractEntityIdentifier(converted)
+
+	prURL, err := githubClient.CreateMigrationPR(ctx, repo, catalogPath, converted)
+	if err != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    "Migration PR creation failed",
+			Action:     "failed",
+		}
+	}
+
+	log.Printf("Successfully opened migration PR for repository: %s", repo.FullName)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Error:      nil,
+		Message:    "Migration PR created successfully",
+		Action:     "migrated",
+		Identifier: identifier,
+		URL:        prURL,
+	}
+}
+
+// registerLegacyCatalog registers a Backstage-format catalog file according to the
+// configured legacy strategy
+func registerLegacyCatalog(ctx context.Context, repo models.Repository, catalogPath, catalogContent string) errors.ProcessingResult {
+	if config.Runtime.LegacyStrategy == "pr" {
+		log.Printf("Catalog file in %s uses the legacy Backstage format, opening conversion PR", repo.FullName)
+		return migrateCatalog(ctx, repo, catalogPath, catalogContent)
+	}
+
+	log.Printf("Catalog file in %s uses the legacy Backstage format, converting before registration", repo.FullName)
+
+	docs, err := catalog.ConvertLegacyDocuments(catalogContent, convertOptions(repo))
+	if err != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    "Catalog conversion failed",
+			Action:     "failed",
+		}
+	}
+
+	var identifier string
+	created := 0
+	for _, doc := range docs {
+		if identifier == "" {
+			identifier, _ = harness.ExtractEntityIdentifier(doc)
+		}
+
+		err := harnessFor(repo).CreateEntityYAML(ctx, doc)
+		if err != nil {
+			procErr := errors.CategorizeError(err, repo.FullName)
+			if procErr.Type == errors.ErrorTypeEntityExists {
+				continue
+			}
+			return errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      procErr,
+				Message:    "Registration of converted entity failed",
+				Action:     "failed",
+				Identifier: identifier,
+			}
+		}
+		created++
+	}
+
+	if created == 0 {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Error:      nil,
+			Message:    "Entity already registered",
+			Skipped:    true,
+			Action:     "skipped",
+			Identifier: identifier,
+		}
+	}
+
+	log.Printf("Successfully registered converted entity for repository: %s", repo.FullName)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Error:      nil,
+		Message:    fmt.Sprintf("Converted legacy catalog and registered %d entities", created),
+		Action:     "converted",
+		Identifier: identifier,
+	}
+}
+
+// convertOptions returns the scope written into the repository's entities converted
+// from Backstage format
+func convertOptions(repo models.Repository) catalog.ConvertOptions {
+	orgID, projectID := repoScope(repo)
+	return catalog.ConvertOptions{
+		OrgIdentifier:     orgID,
+		ProjectIdentifier: projectID,
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/state"
+)
+
+// processOffboardMode removes IDP components for repositories recorded in the
+// state file that have since been archived or deleted in GitHub
+func processOffboardMode(ctx context.Context) error {
+	if stateManager == nil {
+		return fmt.Errorf("offboard mode requires a state file (--state-file)")
+	}
+
+	entries := filterStateEntries(stateManager.All())
+	log.Printf("Checking %d previously onboarded repositories for offboarding", len(entries))
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan *errors.ProcessingResult, len(entries))
+
+	for _, entry := range entries {
+		go func(e state.RepoState) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			results <- offboardRepository(ctx, e)
+		}(entry)
+	}
+
+	summary := errors.NewErrorSummary()
+	for i := 0; i < len(entries); i++ {
+		if result := <-results; result != nil {
+			summary.AddResult(*result)
+		}
+	}
+
+	if len(summary.Results) == 0 {
+		log.Printf("No archived or deleted repositories to offboard")
+		return nil
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during OFFBOARD processing", summary.Total)
+	}
+
+	return nil
+}
+
+// filterStateEntries applies the include/exclude lists to recorded state entries
+func filterStateEntries(entries []state.RepoState) []state.RepoState {
+	var filtered []state.RepoState
+	for _, entry := range entries {
+		if !repoIncluded(entry.Repository[strings.LastIndex(entry.Repository, "/")+1:]) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// offboardRepository returns nil when the repository is still active
+func offboardRepository(ctx context.Context, entry state.RepoState) *errors.ProcessingResult {
+	repo, err := githubClient.GetRepository(ctx, entry.Repository)
+	if err != nil {
+		return &errors.ProcessingResult{
+			Repository: entry.Repository,
+			Success:    false,
+			Error:      errors.CategorizeError(err, entry.Repository),
+			Message:    "Failed to check repository status",
+			Action:     "failed",
+		}
+	}
+
+	reason := "deleted"
+	if repo != nil {
+		if !repo.Archived {
+			return nil
+		}
+		reason = "archived"
+	}
+
+	if entry.Identifier == "" {
+		return &errors.ProcessingResult{
+			Repository: entry.Repository,
+			Success:    false,
+			Error: &errors.ProcessingError{
+				Category:     errors.ErrorCategoryValidation,
+				Type:         errors.ErrorTypeMissingField,
+				Message:      "no entity identifier recorded in state",
+				Repository:   entry.Repository,
+				Recoverable:  false,
+				UserFriendly: fmt.Sprintf("Repository '%s' was %s but the state file has no entity identifier for it. Delete the component manually.", entry.Repository, reason),
+			},
+			Message: "Missing entity identifier",
+			Action:  "failed",
+		}
+	}
+
+	if config.Runtime.DryRun {
+		log.Printf("Would offboard %s (%s): delete component %s", entry.Repository, reason, entry.Identifier)
+		return &errors.ProcessingResult{
+			Repository: entry.Repository,
+			Success:    true,
+			Message:    fmt.Sprintf("Would delete component %s (repository %s)", entry.Identifier, reason),
+			Skipped:    true,
+			Action:     "skipped",
+		}
+	}
+
+	log.Printf("Offboarding %s (%s): deleting component %s", entry.Repository, reason, entry.Identifier)
+
+	// Deleted repositories can't be routed, so their components are looked for in the
+	// configured project
+	client := harnessClient
+	if repo != nil {
+		client = harnessFor(*repo)
+	}
+	if err := client.DeleteComponent(ctx, entry.Identifier); err != nil {
+		procErr := errors.CategorizeError(err, entry.Repository)
+		if procErr.Type != errors.ErrorTypeRepositoryNotFound {
+			return &errors.ProcessingResult{
+				Repository: entry.Repository,
+				Success:    false,
+				Error:      procErr,
+				Message:    "Component deletion failed",
+				Action:     "failed",
+			}
+		}
+		log.Printf("Component %s no longer exists in Harness IDP", entry.Identifier)
+	}
+
+	// Open onboarding PRs are left alone: archived repositories are read-only, so
+	// GitHub rejects closing them, and deleted repositories have none
+	message := fmt.Sprintf("Component %s deleted (repository %s)", entry.Identifier, reason)
+
+	stateManager.Remove(entry.Repository)
+
+	return &errors.ProcessingResult{
+		Repository: entry.Repository,
+		Success:    true,
+		Message:    message,
+		Action:     "deleted",
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"harness-onboarder/internal/models"
+)
+
+// repoOverrides maps repository names to values loaded from the repositories CSV
+var repoOverrides = make(map[string]models.RepoOverride)
+
+// csvColumns is the column order assumed when the CSV has no header row
+var csvColumns = []string{"repo", "owner", "type", "lifecycle", "system", "tags", "base_branch"}
+
+// loadRepoOverrides reads the repositories CSV, if configured, adding each row to the
+// include list and recording its overrides
+func loadRepoOverrides() error {
+	if config.Runtime.ReposCSV == "" {
+		return nil
+	}
+
+	f, err := os.Open(config.Runtime.ReposCSV)
+	if err != nil {
+		return fmt.Errorf("failed to open repositories CSV: %w", err)
+	}
+	defer f.Close()
+
+	overrides, err := parseRepoCSV(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", config.Runtime.ReposCSV, err)
+	}
+	// An empty include list means every repository, which an empty CSV must not trigger
+	if len(overrides) == 0 {
+		return fmt.Errorf("%s lists no repositories", config.Runtime.ReposCSV)
+	}
+
+	for name, override := range overrides {
+		repoOverrides[name] = override
+		if !contains(config.Runtime.IncludeRepos, name) {
+			config.Runtime.IncludeRepos = append(config.Runtime.IncludeRepos, name)
+		}
+	}
+
+	log.Printf("Loaded %d repositories from %s", len(overrides), config.Runtime.ReposCSV)
+	return nil
+}
+
+// parseRepoCSV parses rows of repo, owner, type, lifecycle, system, tags, base_branch. A
+// header row may reorder or omit columns; tags within a cell are separated by semicolons.
+func parseRepoCSV(r io.Reader) (map[string]models.RepoOverride, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are numbered as in the file, counting the header
+	columns, firstRow := csvColumns, 1
+	if len(records) > 0 && isCSVHeader(records[0]) {
+		columns = make([]string, len(records[0]))
+		for i, h := range records[0] {
+			columns[i] = strings.ToLower(strings.TrimSpace(h))
+		}
+		records, firstRow = records[1:], 2
+	}
+
+	overrides := make(map[string]models.RepoOverride)
+	for i, record := range records {
+		var name string
+		var override models.RepoOverride
+
+		for col, value := range record {
+			if col >= len(columns) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			switch columns[col] {
+			case "repo", "repository":
+				// Accept either "repo" or "org/repo"
+				name = value[strings.LastIndex(value, "/")+1:]
+			case "owner":
+				override.Owner = value
+			case "type":
+				override.Type = value
+			case "lifecycle":
+				override.Lifecycle = value
+			case "system":
+				override.System = value
+			case "tags":
+				for _, tag := range strings.Split(value, ";") {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						override.Tags = append(override.Tags, tag)
+					}
+				}
+			case "base_branch":
+				override.BaseBranch = value
+			}
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("row %d has no repository name", i+firstRow)
+		}
+		overrides[name] = override
+	}
+
+	return overrides, nil
+}
+
+// isCSVHeader reports whether a row names the columns: every cell is a known column
+// and one of them holds the repository, in any position
+func isCSVHeader(record []string) bool {
+	hasRepo := false
+	for _, cell := range record {
+		switch column := strings.ToLower(strings.TrimSpace(cell)); column {
+		case "repo", "repository":
+			hasRepo = true
+		default:
+			if !contains(csvColumns, column) {
+				return false
+			}
+		}
+	}
+	return hasRepo
+}
+
+// repoDefaults returns the global defaults with any per-repository overrides applied
+func repoDefaults(repo models.Repository) models.DefaultsConfig {
+	defaults := config.Defaults
+
+	if !defaults.SkipTypeInference {
+		if inferred := inferComponentType(repo); inferred != "" {
+			defaults.Type = inferred
+		}
+	}
+
+	if !defaults.SkipLifecycleInference {
+		if inferred := inferLifecycle(repo, time.Now()); inferred != "" {
+			defaults.Lifecycle = inferred
+		}
+	}
+
+	if system := matchSystem(repo); system != "" {
+		defaults.System = system
+	}
+
+	actions := ruleActions(repo)
+	if actions.Type != "" {
+		defaults.Type = actions.Type
+	}
+	if actions.Lifecycle != "" {
+		defaults.Lifecycle = actions.Lifecycle
+	}
+	if actions.System != "" {
+		defaults.System = actions.System
+	}
+	if actions.Domain != "" {
+		defaults.Domain = actions.Domain
+	}
+	if actions.Owner != "" {
+		defaults.Owner = actions.Owner
+	}
+
+	// Custom properties are maintained per repository, so they win over rules
+	if lifecycle := customProperty(repo, config.CustomProperties.Lifecycle); lifecycle != "" {
+		defaults.Lifecycle = lifecycle
+	}
+	if system := customProperty(repo, config.CustomProperties.System); system != "" {
+		defaults.System = system
+	}
+	if owner := propertyOwner(repo); owner != "" {
+		defaults.Owner = owner
+	}
+
+	override, ok := repoOverrides[repo.Name]
+	if !ok {
+		return defaults
+	}
+
+	if override.Owner != "" {
+		defaults.Owner = override.Owner
+	}
+	if override.Type != "" {
+		defaults.Type = override.Type
+	}
+	if override.Lifecycle != "" {
+		defaults.Lifecycle = override.Lifecycle
+	}
+	if override.System != "" {
+		defaults.System = override.System
+	}
+
+	return defaults
+}
+
+// applyBaseBranches sets the PR base branch of repositories the repositories CSV gives
+// one for; the rest target the configured base branch or their default branch
+func applyBaseBranches(repos []models.Repository) {
+	for i := range repos {
+		if base := repoOverrides[repos[i].Name].BaseBranch; base != "" {
+			repos[i].BaseBranch = base
+		}
+	}
+}
+
+// overrideTags returns tags from matching rules, custom properties and the
+// repositories CSV that aren't already present
+func overrideTags(repo models.Repository, tags []string) []string {
+	extra := append(ruleActions(repo).Tags, propertyTags(repo)...)
+	extra = append(extra, repoOverrides[repo.Name].Tags...)
+	for _, tag := range extra {
+		if !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
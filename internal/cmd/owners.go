@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"harness-onboarder/internal/models"
+)
+
+// ownersMap maps lowercased GitHub users and teams to Harness owner references
+var ownersMap = make(map[string]string)
+
+// loadOwnersMap reads the owners mapping file, if configured. Keys are GitHub handles
+// or teams (org/team or just team) and values are Harness owners such as
+// group:account/platform.
+func loadOwnersMap() error {
+	if config.Runtime.OwnersMap == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(config.Runtime.OwnersMap)
+	if err != nil {
+		return fmt.Errorf("failed to read owners map: %w", err)
+	}
+
+	var entries map[string]string
+	if err := yaml.Unmarshal(content, &entries); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", config.Runtime.OwnersMap, err)
+	}
+
+	ownersMap = make(map[string]string, len(entries))
+	for handle, owner := range entries {
+		ownersMap[normalizeHandle(handle)] = owner
+	}
+
+	log.Printf("Loaded %d owner mappings from %s", len(ownersMap), config.Runtime.OwnersMap)
+	return nil
+}
+
+// mapOwner resolves a CODEOWNERS entry to a Harness owner. Teams match on either
+// org/team or the bare team name.
+func mapOwner(handle string) (string, bool) {
+	handle = normalizeHandle(handle)
+	if owner, ok := ownersMap[handle]; ok {
+		return owner, true
+	}
+	if idx := strings.LastIndex(handle, "/"); idx >= 0 {
+		if owner, ok := ownersMap[handle[idx+1:]]; ok {
+			return owner, true
+		}
+	}
+	return "", false
+}
+
+func normalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// invalidOwners holds the owners checkOwners found missing in Harness, keyed by
+// ownerKey; getOwner replaces them with the default owner. checkedOwners holds one
+// lookup per owner, so each is looked up once while streaming without holding
+// ownersMu, which guards both maps, during the request.
+var (
+	invalidOwners = make(map[string]bool)
+	checkedOwners = make(map[string]*sync.Once)
+	ownersMu      sync.Mutex
+)
+
+// ownerKey identifies an owner within the Harness account the repository is routed to
+func ownerKey(repo models.Repository, owner string) string {
+	return repoHarnessConfig(repo).AccountID + "|" + owner
+}
+
+// ownerInvalid reports whether checkOwner found the owner missing in Harness
+func ownerInvalid(repo models.Repository, owner string) bool {
+	ownersMu.Lock()
+	defer ownersMu.Unlock()
+	return invalidOwners[ownerKey(repo, owner)]
+}
+
+// resetOwnerChecks forgets the owners looked up in a previous run
+func resetOwnerChecks() {
+	ownersMu.Lock()
+	defer ownersMu.Unlock()
+	invalidOwners = make(map[string]bool)
+	checkedOwners = make(map[string]*sync.Once)
+}
+
+// checkOwners looks up every group owner the repositories would get in Harness, once
+// per owner, when --validate-owners or --create-owner-groups is set. Missing groups are
+// created with --create-owner-groups; otherwise they're reported and replaced with the
+// default owner instead of leaving components with dangling owners. Users and
+// references outside the account/org/project namespaces aren't checked.
+func checkOwners(ctx context.Context, repos []models.Repository) {
+	resetOwnerChecks()
+	for _, repo := range repos {
+		checkOwner(ctx, repo)
+	}
+}
+
+// checkOwner looks up the owner of one repository, unless another repository with the
+// same owner was already checked
+func checkOwner(ctx context.Context, repo models.Repository) {
+	if !config.Runtime.ValidateOwners && !config.Runtime.CreateOwnerGroups {
+		return
+	}
+
+	owner := resolveOwner(repo)
+	key := ownerKey(repo, owner)
+	ownersMu.Lock()
+	once, ok := checkedOwners[key]
+	if !ok {
+		once = new(sync.Once)
+		checkedOwners[key] = once
+	}
+	ownersMu.Unlock()
+
+	// Repositories with the same owner wait here for the first one's lookup
+	once.Do(func() {
+		invalid := lookupOwner(ctx, repo, owner)
+		ownersMu.Lock()
+		invalidOwners[key] = invalid
+		ownersMu.Unlock()
+	})
+	if ownerInvalid(repo, owner) {
+		log.Printf("Warning: owner %s of %s doesn't exist in Harness, using %s instead", owner, repo.FullName, config.Defaults.Owner)
+	}
+}
+
+// lookupOwner reports whether a group owner is missing in Harness and should be
+// replaced, creating the group with --create-owner-groups
+func lookupOwner(ctx context.Context, repo models.Repository, owner string) bool {
+	identifier, orgID, projectID, ok := parseGroupOwner(owner)
+	if !ok {
+		return false
+	}
+	exists, err := harnessFor(repo).GroupExists(ctx, identifier, orgID, projectID)
+	if err != nil {
+		log.Printf("Warning: could not check owner %s, keeping it: %v", owner, err)
+		return false
+	}
+	if !exists && config.Runtime.CreateOwnerGroups {
+		exists = createOwnerGroup(ctx, repo, owner, identifier, orgID, projectID)
+	}
+	if !exists && owner == config.Defaults.Owner {
+		log.Printf("Warning: default owner %s doesn't exist in Harness", owner)
+		return false
+	}
+	return !exists
+}
+
+// createOwnerGroup creates the missing user group an owner reference points to,
+// reporting whether it now exists
+func createOwnerGroup(ctx context.Context, repo models.Repository, owner, identifier, orgID, projectID string) bool {
+	description := fmt.Sprintf("Created by harness-onboarder for %s, which owns catalog components", owner)
+	if err := harnessFor(repo).CreateUserGroup(ctx, identifier, identifier, description, orgID, projectID); err != nil {
+		log.Printf("Warning: failed to create user group for owner %s: %v", owner, err)
+		return false
+	}
+	log.Printf("Created user group for owner %s; add its members in Harness", owner)
+	return true
+}
+
+// parseGroupOwner splits a group owner reference such as group:account/platform or
+// group:account.org.project/platform into its identifier and scope
+func parseGroupOwner(owner string) (identifier, orgID, projectID string, ok bool) {
+	ref, found := strings.CutPrefix(owner, "group:")
+	if !found {
+		return "", "", "", false
+	}
+	namespace, identifier, found := strings.Cut(ref, "/")
+	if !found || identifier == "" {
+		return "", "", "", false
+	}
+
+	scope := strings.Split(namespace, ".")
+	if scope[0] != "account" || len(scope) > 3 {
+		return "", "", "", false
+	}
+	if len(scope) > 1 {
+		orgID = scope[1]
+	}
+	if len(scope) > 2 {
+		projectID = scope[2]
+	}
+	return identifier, orgID, projectID, true
+}
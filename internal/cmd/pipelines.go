@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"text/template"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/models"
+)
+
+// pipelinePath is where the pipeline starter is added in onboarding PRs
+const pipelinePath = ".harness/pipeline.yaml"
+
+// defaultPipelineTemplate is a minimal CI pipeline building the repository on Harness
+// Cloud, used when no --pipeline-template is configured
+const defaultPipelineTemplate = `pipeline:
+  name: {{ printf "%q" .Name }}
+  identifier: {{ .Identifier }}
+  orgIdentifier: {{ .OrgID }}
+  projectIdentifier: {{ .ProjectID }}
+  properties:
+    ci:
+      codebase:
+        connectorRef: {{ if .ConnectorRef }}{{ .ConnectorRef }}{{ else }}<+input>{{ end }}
+        repoName: {{ .Name }}
+        build: <+input>
+  stages:
+    - stage:
+        name: Build
+        identifier: build
+        type: CI
+        spec:
+          cloneCodebase: true
+          platform:
+            os: Linux
+            arch: Amd64
+          runtime:
+            type: Cloud
+            spec: {}
+          execution:
+            steps:
+              - step:
+                  type: Run
+                  name: Build and test
+                  identifier: build_and_test
+                  spec:
+                    shell: Sh
+                    command: echo "Add the build and test commands for {{ .Name }} here"
+`
+
+// pipelineTemplate is the parsed pipeline starter template, nil when disabled
+var pipelineTemplate *template.Template
+
+// pipelineData is what pipeline templates are rendered against. Repository fields such
+// as .Name and .Language are available alongside the Harness scope.
+type pipelineData struct {
+	models.Repository
+	Identifier   string // the entity identifier, reused as the pipeline identifier
+	OrgID        string
+	ProjectID    string
+	ConnectorRef string
+}
+
+// loadPipelineTemplate parses the pipeline template file, or the built-in starter, when
+// the pipeline starter is enabled
+func loadPipelineTemplate() error {
+	pipelineTemplate = nil
+	if !config.Runtime.PipelineStarter {
+		return nil
+	}
+
+	text := defaultPipelineTemplate
+	if config.Runtime.PipelineTemplate != "" {
+		content, err := os.ReadFile(config.Runtime.PipelineTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to read pipeline template: %w", err)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("pipeline").Funcs(identifierFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline template: %w", err)
+	}
+	pipelineTemplate = tmpl
+	return nil
+}
+
+// pipelineFiles returns the pipeline starter added to onboarding PRs when enabled. An
+// existing pipeline file is never replaced. Nil when disabled or rendering fails.
+func pipelineFiles(repo models.Repository) []github.PRFile {
+	if pipelineTemplate == nil {
+		return nil
+	}
+
+	harnessConfig := repoHarnessConfig(repo)
+	data := pipelineData{
+		Repository:   repo,
+		Identifier:   repoIdentifier(repo),
+		OrgID:        harnessConfig.OrgID,
+		ProjectID:    harnessConfig.ProjectID,
+		ConnectorRef: harnessConfig.ConnectorRef,
+	}
+	var buf bytes.Buffer
+	if err := pipelineTemplate.Execute(&buf, data); err != nil {
+		log.Printf("Warning: failed to render pipeline starter for %s, leaving it out: %v", repo.FullName, err)
+		return nil
+	}
+
+	return []github.PRFile{{Path: pipelinePath, CreateOnly: true, Content: buf.String()}}
+}
+
+// onboardingFiles returns every file added to onboarding changes besides the catalog file
+func onboardingFiles(repo models.Repository) []github.PRFile {
+	files := append(techDocsFiles(repo), pipelineFiles(repo)...)
+	return append(files, readmeBadgeFiles(repo)...)
+}
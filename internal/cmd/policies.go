@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+	"harness-onboarder/internal/policy"
+)
+
+// policyEngine evaluates generated entities against --policy, nil when none are configured
+var policyEngine *policy.Engine
+
+// loadPolicies compiles the configured Rego policies
+func loadPolicies(ctx context.Context) error {
+	policyEngine = nil
+	if len(config.Runtime.Policies) == 0 {
+		return nil
+	}
+
+	engine, err := policy.Load(ctx, config.Runtime.Policies)
+	if err != nil {
+		return err
+	}
+	policyEngine = engine
+	log.Printf("Loaded policies from %s", strings.Join(config.Runtime.Policies, ", "))
+	return nil
+}
+
+// checkPolicies evaluates generated catalog content against the configured policies,
+// returning a validation error listing every violation so the entity is never submitted
+func checkPolicies(ctx context.Context, repo models.Repository, yamlContent string) *errors.ProcessingError {
+	if policyEngine == nil {
+		return nil
+	}
+
+	violations, err := policyEngine.Evaluate(ctx, yamlContent)
+	if err != nil {
+		return &errors.ProcessingError{
+			Category:     errors.ErrorCategoryValidation,
+			Type:         errors.ErrorTypePolicyViolation,
+			Message:      fmt.Sprintf("policy evaluation failed: %s", err.Error()),
+			Repository:   repo.FullName,
+			Cause:        err,
+			Recoverable:  false,
+			UserFriendly: fmt.Sprintf("Could not evaluate policies for '%s': %s", repo.FullName, err.Error()),
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.String()
+	}
+	log.Printf("Catalog file generated for %s violates %d policies", repo.FullName, len(violations))
+	return &errors.ProcessingError{
+		Category:     errors.ErrorCategoryValidation,
+		Type:         errors.ErrorTypePolicyViolation,
+		Message:      "policy violations: " + strings.Join(messages, "; "),
+		Repository:   repo.FullName,
+		Recoverable:  false,
+		UserFriendly: fmt.Sprintf("The entity generated for '%s' violates policies: %s", repo.FullName, strings.Join(messages, "; ")),
+	}
+}
+
+// policyResult reports a repository whose generated entity failed the policies
+func policyResult(repo models.Repository, procErr *errors.ProcessingError) errors.ProcessingResult {
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    false,
+		Error:      procErr,
+		Message:    "Generated entity violates policies",
+		Action:     "failed",
+	}
+}
+
+// checkComponentPolicies evaluates the catalog file equivalent of an API or sync mode
+// component against the configured policies
+func checkComponentPolicies(ctx context.Context, repo models.Repository) *errors.ProcessingError {
+	if policyEngine == nil {
+		return nil
+	}
+	yamlContent, err := generateCatalogYAML(repo)
+	if err != nil {
+		return errors.CategorizeError(fmt.Errorf("failed to marshal catalog-info.yaml: %w", err), repo.FullName)
+	}
+	return checkPolicies(ctx, repo, yamlContent)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// projectRoute is a configured project route with its conditions compiled and its
+// target's config resolved
+type projectRoute struct {
+	rule    compiledRule
+	target  string
+	harness models.HarnessConfig
+}
+
+var projectRoutes []projectRoute
+
+// targetConfigs and targetClients hold the resolved config and a Harness client per
+// configured target
+var (
+	targetConfigs map[string]models.HarnessConfig
+	targetClients map[string]*harness.Client
+)
+
+// projectClients caches a Harness client per routed target, org and project
+var (
+	projectClientsMu sync.Mutex
+	projectClients   = make(map[string]*harness.Client)
+)
+
+// loadProjectRoutes validates the Harness targets and compiles the project routes
+// from the config file
+func loadProjectRoutes() error {
+	targetConfigs = make(map[string]models.HarnessConfig, len(config.Targets))
+	targetClients = make(map[string]*harness.Client, len(config.Targets))
+	for name, target := range config.Targets {
+		resolved := targetConfig(target)
+		if resolved.ConnectorRef == "" && config.Runtime.ProvisionConnector {
+			resolved.ConnectorRef = defaultConnectorRef
+		}
+		if resolved.APIKey == "" || resolved.AccountID == "" {
+			return fmt.Errorf("target %q: api_key and account_id are required", name)
+		}
+		switch resolved.Scope {
+		case "", "project", "org", "account":
+		default:
+			return fmt.Errorf("target %q: invalid scope %q: must be project, org or account", name, resolved.Scope)
+		}
+		// Targets in other accounts get their own connections and circuit breaker
+		client, err := harness.NewClient(resolved)
+		if err != nil {
+			return fmt.Errorf("target %q: %w", name, err)
+		}
+		targetConfigs[name] = resolved
+		targetClients[name] = client
+	}
+
+	projectRoutes = nil
+	for i, route := range config.Projects {
+		label := ruleLabel(i, models.Rule{Name: route.Name})
+		if route.Target == "" && route.OrgID == "" && route.ProjectID == "" {
+			return fmt.Errorf("project route %s: target, org_id or project_id is required", label)
+		}
+
+		cfg := config.Harness
+		if route.Target != "" {
+			target, ok := targetConfigs[route.Target]
+			if !ok {
+				return fmt.Errorf("project route %s: unknown target %q", label, route.Target)
+			}
+			cfg = target
+		}
+		if route.OrgID != "" {
+			cfg.OrgID = route.OrgID
+		}
+		if route.ProjectID != "" || route.OrgID != "" {
+			cfg.ProjectID = route.ProjectID
+		}
+
+		rule, err := compileRule(models.Rule{Name: route.Name, Match: route.Match})
+		if err != nil {
+			return fmt.Errorf("project route %s: %w", label, err)
+		}
+		projectRoutes = append(projectRoutes, projectRoute{rule: rule, target: route.Target, harness: cfg})
+	}
+
+	if len(projectRoutes) > 0 {
+		log.Printf("Loaded %d project routes", len(projectRoutes))
+	}
+	if len(targetConfigs) > 0 {
+		names := make([]string, 0, len(targetConfigs))
+		for name := range targetConfigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		log.Printf("Loaded Harness targets: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// targetConfig fills the unset fields of a target from the harness section. Account
+// credentials are only inherited together, so a target never mixes two accounts.
+func targetConfig(target models.HarnessConfig) models.HarnessConfig {
+	cfg := config.Harness
+	if target.AccountID != "" {
+		cfg.AccountID = target.AccountID
+		cfg.APIKey = target.APIKey
+		cfg.OrgID = ""
+		cfg.ProjectID = ""
+		cfg.ConnectorRef = ""
+	}
+	if target.BaseURL != "" {
+		cfg.BaseURL = target.BaseURL
+	}
+	if target.OrgID != "" {
+		cfg.OrgID = target.OrgID
+	}
+	if target.ProjectID != "" {
+		cfg.ProjectID = target.ProjectID
+	}
+	if target.ConnectorRef != "" {
+		cfg.ConnectorRef = target.ConnectorRef
+	}
+	if target.Scope != "" {
+		cfg.Scope = target.Scope
+	}
+	return cfg
+}
+
+// matchRoute returns the first project route matching the repository, or nil
+func matchRoute(repo models.Repository) *projectRoute {
+	for i := range projectRoutes {
+		if projectRoutes[i].rule.matches(repo) {
+			return &projectRoutes[i]
+		}
+	}
+	return nil
+}
+
+// repoHarnessConfig returns the Harness config of the first route matching the
+// repository, or the harness section when none matches
+func repoHarnessConfig(repo models.Repository) models.HarnessConfig {
+	if route := matchRoute(repo); route != nil {
+		return route.harness
+	}
+	return config.Harness
+}
+
+// repoScope returns the org and project identifiers written into the repository's
+// entities, after routing and --harness-scope
+func repoScope(repo models.Repository) (orgID, projectID string) {
+	return harness.ScopeIdentifiers(repoHarnessConfig(repo))
+}
+
+// harnessFor returns the Harness client for the repository's routed target, org and
+// project
+func harnessFor(repo models.Repository) *harness.Client {
+	route := matchRoute(repo)
+	if route == nil {
+		return harnessClient
+	}
+	return routeClient(route)
+}
+
+// routeClient returns the Harness client for a project route's target, org and project
+func routeClient(route *projectRoute) *harness.Client {
+	base, baseConfig := harnessClient, config.Harness
+	if route.target != "" {
+		base, baseConfig = targetClients[route.target], targetConfigs[route.target]
+	}
+	cfg := route.harness
+	if cfg.OrgID == baseConfig.OrgID && cfg.ProjectID == baseConfig.ProjectID {
+		return base
+	}
+
+	key := route.target + "/" + cfg.OrgID + "/" + cfg.ProjectID
+	projectClientsMu.Lock()
+	defer projectClientsMu.Unlock()
+	client, ok := projectClients[key]
+	if !ok {
+		client = base.WithProject(cfg.OrgID, cfg.ProjectID)
+		projectClients[key] = client
+	}
+	return client
+}
+
+// harnessClients returns the distinct Harness clients repositories can be routed to,
+// starting with the one for the harness section
+func harnessClients() []*harness.Client {
+	clients := []*harness.Client{harnessClient}
+	seen := map[*harness.Client]bool{harnessClient: true}
+	for i := range projectRoutes {
+		client := routeClient(&projectRoutes[i])
+		if !seen[client] {
+			seen[client] = true
+			clients = append(clients, client)
+		}
+	}
+	return clients
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// customProperty returns the value of the named custom property, if mapped and set
+func customProperty(repo models.Repository, name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(repo.Properties[name])
+}
+
+// propertyOwner returns the owner from the mapped custom property. Values that match
+// an entry in the owners map, such as a GitHub team, are translated.
+func propertyOwner(repo models.Repository) string {
+	owner := customProperty(repo, config.CustomProperties.Owner)
+	if mapped, ok := mapOwner(owner); ok {
+		return mapped
+	}
+	return owner
+}
+
+// propertyTags returns the values of the custom properties mapped to tags. Multi-select
+// properties contribute one tag per value.
+func propertyTags(repo models.Repository) []string {
+	var tags []string
+	for _, name := range config.CustomProperties.Tags {
+		for _, value := range strings.Split(customProperty(repo, name), ",") {
+			if value = strings.TrimSpace(value); value != "" && !contains(tags, value) {
+				tags = append(tags, value)
+			}
+		}
+	}
+	return tags
+}
+
+// applyPropertyAnnotations sets the annotations mapped to custom properties that have
+// a value on the repository
+func applyPropertyAnnotations(repo models.Repository, annotations map[string]string) {
+	for key, name := range config.CustomProperties.Annotations {
+		if value := customProperty(repo, name); value != "" {
+			annotations[key] = value
+		}
+	}
+}
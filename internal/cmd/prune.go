@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Find IDP components whose GitHub repository was deleted or archived",
+	Long: `Lists the components in Harness IDP, in every project repositories are routed
+to, that have a github.com/project-slug annotation pointing into the configured
+organization and checks that each repository still exists and isn't archived.
+Orphaned components are reported, and deleted with --delete.
+
+GitHub answers "not found" both for deleted repositories and for ones the app
+installation can't see, such as repositories left out of a "selected
+repositories" installation, so components whose repository wasn't found are
+only deleted when --delete-missing is also set.
+
+Unlike offboard mode this doesn't need a state file, so it also finds components
+created by other tools or earlier runs. Archived repositories are kept when
+--include-archived is set, since they are onboarded on purpose.`,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().Bool("delete", false, "Delete orphaned components instead of only reporting them")
+	pruneCmd.Flags().Bool("delete-missing", false, "With --delete, also delete components whose repository wasn't found, which GitHub can't tell apart from one the app has no access to")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// projectSlugAnnotation links an entity to its GitHub repository
+const projectSlugAnnotation = "github.com/project-slug"
+
+// orphan is a component whose repository is gone
+type orphan struct {
+	Client     *harness.Client
+	Component  models.HarnessComponent
+	Repository string
+	Reason     string // not found or archived
+	Action     string // flagged, deleted or failed
+	Detail     string
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	del, _ := cmd.Flags().GetBool("delete")
+	deleteMissing, _ := cmd.Flags().GetBool("delete-missing")
+
+	if err := validateConnectionConfig(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	if err := loadProjectRoutes(); err != nil {
+		return err
+	}
+	if err := initClients(); err != nil {
+		return err
+	}
+	// Without access to the organization every repository would look deleted
+	if err := githubClient.ValidateAccess(ctx, config.GitHub.Organization); err != nil {
+		return err
+	}
+
+	// Repositories are checked once even when several components point at them
+	byRepo := make(map[string][]orphan)
+	for _, client := range harnessClients() {
+		components, err := client.ListComponents(ctx, harness.ComponentFilter{})
+		if err != nil {
+			return err
+		}
+		for _, component := range components {
+			slug := component.Annotations[projectSlugAnnotation]
+			if strings.Count(slug, "/") != 1 || !pruneIncluded(slug) {
+				continue
+			}
+			byRepo[slug] = append(byRepo[slug], orphan{Client: client, Component: component, Repository: slug})
+		}
+	}
+	log.Printf("Checking %d repositories referenced by IDP components", len(byRepo))
+
+	orphans := findOrphans(ctx, byRepo)
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Repository != orphans[j].Repository {
+			return orphans[i].Repository < orphans[j].Repository
+		}
+		return orphans[i].Component.Identifier < orphans[j].Component.Identifier
+	})
+
+	failed := 0
+	for i := range orphans {
+		o := &orphans[i]
+		if o.Action == "failed" || !del || (o.Reason == "not found" && !deleteMissing) {
+			continue
+		}
+		if err := o.Client.DeleteEntity(ctx, componentKind(o.Component), o.Component.Identifier); err != nil {
+			o.Action, o.Detail = "failed", err.Error()
+			continue
+		}
+		o.Action = "deleted"
+	}
+	for _, o := range orphans {
+		if o.Action == "failed" {
+			failed++
+		}
+	}
+
+	printPruneTable(orphans, del)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d orphaned components could not be checked or deleted", failed, len(orphans))
+	}
+	return nil
+}
+
+// pruneIncluded reports whether a repository slug belongs to the configured
+// organization and passes the include/exclude lists
+func pruneIncluded(slug string) bool {
+	owner, name, _ := strings.Cut(slug, "/")
+	return strings.EqualFold(owner, config.GitHub.Organization) && repoIncluded(name)
+}
+
+// findOrphans looks up each repository concurrently and returns the components of
+// those that weren't found, or archived unless --include-archived is set. Components
+// whose repository couldn't be looked up are returned as failed.
+func findOrphans(ctx context.Context, byRepo map[string][]orphan) []orphan {
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan []orphan, len(byRepo))
+
+	for slug, components := range byRepo {
+		go func(slug string, components []orphan) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			repo, err := githubClient.GetRepository(ctx, slug)
+			reason, action, detail := "", "flagged", ""
+			switch {
+			case err != nil:
+				reason, action, detail = "unknown", "failed", err.Error()
+			case repo == nil:
+				reason = "not found"
+			case repo.Archived && !config.Runtime.IncludeArchived:
+				reason = "archived"
+			default:
+				results <- nil
+				return
+			}
+			for i := range components {
+				components[i].Reason, components[i].Action, components[i].Detail = reason, action, detail
+			}
+			results <- components
+		}(slug, components)
+	}
+
+	var orphans []orphan
+	for i := 0; i < len(byRepo); i++ {
+		orphans = append(orphans, <-results...)
+	}
+	return orphans
+}
+
+// componentKind is the entity kind of a listed component, Component when unset
+func componentKind(component models.HarnessComponent) string {
+	if component.Kind == "" {
+		return "Component"
+	}
+	return component.Kind
+}
+
+// printPruneTable prints the orphaned components and what was done with them
+func printPruneTable(orphans []orphan, del bool) {
+	if len(orphans) == 0 {
+		fmt.Println("✅ Every IDP component's repository still exists")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IDENTIFIER\tREPOSITORY\tREPOSITORY STATUS\tACTION\tDETAIL")
+	byAction := make(map[string]int)
+	for _, o := range orphans {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.Component.Identifier, o.Repository, o.Reason, o.Action, o.Detail)
+		byAction[o.Action]++
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Prune Summary:\n")
+	fmt.Printf("   Orphaned components: %d\n", len(orphans))
+	for _, action := range []string{"flagged", "deleted", "failed"} {
+		if byAction[action] > 0 {
+			fmt.Printf("   %s: %d\n", action, byAction[action])
+		}
+	}
+	switch {
+	case byAction["flagged"] > 0 && !del:
+		fmt.Printf("   Run with --delete to delete flagged components\n")
+	case byAction["flagged"] > 0:
+		fmt.Printf("   Components whose repository wasn't found were kept; run with --delete-missing if the app can see every repository\n")
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// processRegisterModeBatched fetches catalog files concurrently, then imports them
+// --register-batch-size at a time with one RegisterCatalogLocations call per batch
+func processRegisterModeBatched(ctx context.Context, repos []models.Repository) error {
+	size := config.Runtime.RegisterBatchSize
+	log.Printf("Processing %d repositories in REGISTER mode, %d imports per batch", len(repos), size)
+
+	locations := make([]harness.CatalogLocation, len(repos))
+	early := make([]*errors.ProcessingResult, len(repos))
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, r models.Repository) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			locations[i], early[i] = prepareRegistration(ctx, r)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	// Repositories without a file to import already have their result
+	summary := newRunSummary()
+	var pending []models.Repository
+	var pendingLocations []harness.CatalogLocation
+	for i, repo := range repos {
+		if early[i] != nil {
+			finishRegistration(ctx, summary, repo, *early[i])
+			continue
+		}
+		pending = append(pending, repo)
+		pendingLocations = append(pendingLocations, locations[i])
+	}
+
+	for start := 0; start < len(pending); start += size {
+		end := min(start+size, len(pending))
+		errs := registerLocationBatch(ctx, pending[start:end], pendingLocations[start:end])
+		for i, err := range errs {
+			repo := pending[start+i]
+			finishRegistration(ctx, summary, repo, registerResult(repo, pendingLocations[start+i], err))
+		}
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during REGISTER processing", summary.Total)
+	}
+	return nil
+}
+
+// finishRegistration records a register mode result and adds it to the summary
+func finishRegistration(ctx context.Context, summary *errors.ErrorSummary, repo models.Repository, result errors.ProcessingResult) {
+	result = withScores(ctx, repo, result)
+	recordState(repo, result)
+	markOnboarded(ctx, repo, result)
+	summary.AddResult(result)
+}
+
+// registerLocationBatch imports a batch of catalog files with one
+// RegisterCatalogLocations call per Harness project the repositories are routed to,
+// returning errors in batch order
+func registerLocationBatch(ctx context.Context, repos []models.Repository, locations []harness.CatalogLocation) []error {
+	clients := make(map[*harness.Client][]int)
+	var order []*harness.Client
+	for i, repo := range repos {
+		client := harnessFor(repo)
+		if _, ok := clients[client]; !ok {
+			order = append(order, client)
+		}
+		clients[client] = append(clients[client], i)
+	}
+
+	errs := make([]error, len(locations))
+	for _, client := range order {
+		indexes := clients[client]
+		group := make([]harness.CatalogLocation, len(indexes))
+		for j, i := range indexes {
+			group[j] = locations[i]
+		}
+		for j, err := range client.RegisterCatalogLocations(ctx, group) {
+			errs[indexes[j]] = err
+		}
+	}
+	return errs
+}
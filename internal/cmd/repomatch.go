@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// regexPrefix marks an include/exclude entry as a regular expression
+const regexPrefix = "re:"
+
+// repoPatterns caches the compiled regular expressions of include/exclude entries
+var repoPatterns sync.Map
+
+// isRepoPattern reports whether an include/exclude entry is a glob or regular expression
+// rather than an exact repository name
+func isRepoPattern(entry string) bool {
+	return strings.HasPrefix(entry, regexPrefix) || strings.ContainsAny(entry, "*?[")
+}
+
+// hasRepoPatterns reports whether any entry of the list is a pattern, so the listed
+// repositories can't be fetched by name
+func hasRepoPatterns(list []string) bool {
+	for _, entry := range list {
+		if isRepoPattern(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoListed reports whether a repository name matches an entry of an include or
+// exclude list: an exact name, a glob such as payments-*, or a regular expression
+// prefixed with re:
+func repoListed(list []string, name string) bool {
+	for _, entry := range list {
+		if matchRepoEntry(entry, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoIncluded applies --include-repos and --exclude-repos to a repository name
+func repoIncluded(name string) bool {
+	if len(config.Runtime.IncludeRepos) > 0 && !repoListed(config.Runtime.IncludeRepos, name) {
+		return false
+	}
+	return !repoListed(config.Runtime.ExcludeRepos, name)
+}
+
+func matchRepoEntry(entry, name string) bool {
+	if expr, ok := strings.CutPrefix(entry, regexPrefix); ok {
+		pattern, err := compileRepoPattern(expr)
+		return err == nil && pattern.MatchString(name)
+	}
+	if strings.ContainsAny(entry, "*?[") {
+		matched, err := path.Match(entry, name)
+		return err == nil && matched
+	}
+	return entry == name
+}
+
+func compileRepoPattern(expr string) (*regexp.Regexp, error) {
+	if cached, ok := repoPatterns.Load(expr); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	repoPatterns.Store(expr, pattern)
+	return pattern, nil
+}
+
+// validateRepoPatterns checks the globs and regular expressions of the include and
+// exclude lists
+func validateRepoPatterns() error {
+	for _, entry := range append(append([]string(nil), config.Runtime.IncludeRepos...), config.Runtime.ExcludeRepos...) {
+		if expr, ok := strings.CutPrefix(entry, regexPrefix); ok {
+			if _, err := compileRepoPattern(expr); err != nil {
+				return fmt.Errorf("invalid repository pattern %q: %w", entry, err)
+			}
+		} else if isRepoPattern(entry) {
+			if _, err := path.Match(entry, ""); err != nil {
+				return fmt.Errorf("invalid repository pattern %q: %w", entry, err)
+			}
+		}
+	}
+	return nil
+}
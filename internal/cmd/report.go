@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/report"
+	"harness-onboarder/internal/state"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a Markdown or HTML report from the state file",
+	Long: `Renders the last recorded outcome of every repository in the state file -
+action, PR link, entity identifier and errors by category - as a shareable
+Markdown or HTML document.`,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().String("format", "", "Report format: markdown or html (default inferred from --output, else markdown)")
+	reportCmd.Flags().String("output", "", "File to write the report to (default stdout)")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if config.Runtime.StateFile == "" {
+		config.Runtime.StateFile = defaultStateFile
+	}
+
+	manager, err := state.NewManager(config.Runtime.StateFile)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if len(manager.All()) == 0 {
+		return fmt.Errorf("no state found in %s (use --state-file)", manager.Path())
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	if format == "" {
+		format = report.FormatForPath(output)
+	}
+
+	r := &report.Report{
+		Title:        "Harness IDP Onboarding Report",
+		Organization: config.GitHub.Organization,
+		GeneratedAt:  time.Now().UTC(),
+		Entries:      report.FromState(manager.All()),
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := r.Write(w, format); err != nil {
+		return err
+	}
+
+	if output != "" {
+		log.Printf("Wrote %s report for %d repositories to %s", format, len(r.Entries), output)
+	}
+	return nil
+}
+
+// writeRunReport writes the results of the current run to the configured report file
+func writeRunReport(summary *errors.ErrorSummary) {
+	if config.Runtime.ReportFile == "" {
+		return
+	}
+
+	r := &report.Report{
+		Title:        "Harness IDP Onboarding Run",
+		Organization: config.GitHub.Organization,
+		Mode:         config.Runtime.Mode,
+		GeneratedAt:  time.Now().UTC(),
+		Entries:      report.FromSummary(summary),
+	}
+
+	f, err := os.Create(config.Runtime.ReportFile)
+	if err != nil {
+		log.Printf("Warning: failed to create report file: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if err := r.Write(f, report.FormatForPath(config.Runtime.ReportFile)); err != nil {
+		log.Printf("Warning: failed to write report: %v", err)
+		return
+	}
+
+	log.Printf("Wrote run report to %s", config.Runtime.ReportFile)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// loadReposFile adds the repositories listed in --repos-file, or on stdin when it is
+// "-", to the include list. Lines hold a repository name or org/repo; blank lines and
+// # comments are ignored.
+func loadReposFile() error {
+	path := config.Runtime.ReposFile
+	if path == "" {
+		return nil
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open repositories file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	names, err := parseReposList(r)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", reposFileName(path), err)
+	}
+	// An empty include list means every repository, which a failed export must not trigger
+	if len(names) == 0 {
+		return fmt.Errorf("%s lists no repositories", reposFileName(path))
+	}
+
+	for _, name := range names {
+		if !contains(config.Runtime.IncludeRepos, name) {
+			config.Runtime.IncludeRepos = append(config.Runtime.IncludeRepos, name)
+		}
+	}
+	log.Printf("Loaded %d repositories from %s", len(names), reposFileName(path))
+	return nil
+}
+
+// parseReposList reads one repository per line, keeping the name of org/repo entries
+func parseReposList(r io.Reader) ([]string, error) {
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line[strings.LastIndex(line, "/")+1:])
+	}
+	return names, scanner.Err()
+}
+
+func reposFileName(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
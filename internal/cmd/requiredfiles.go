@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// requiredFileSkips holds the results of repositories dropped by --required-files,
+// added to the summary of the run
+var requiredFileSkips []errors.ProcessingResult
+
+// newRunSummary returns the summary of a mode's run, starting with the repositories
+// skipped before processing
+func newRunSummary() *errors.ErrorSummary {
+	summary := errors.NewErrorSummary()
+	for _, result := range requiredFileSkips {
+		summary.AddResult(result)
+	}
+	return summary
+}
+
+// applyRequiredFiles drops the repositories missing any of --required-files,
+// checking them concurrently, and records them as skipped. Repositories whose files
+// can't be checked are kept.
+func applyRequiredFiles(ctx context.Context, repos []models.Repository) []models.Repository {
+	requiredFileSkips = nil
+	if len(config.Runtime.RequiredFiles) == 0 {
+		return repos
+	}
+
+	missing := make([]string, len(repos))
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, r models.Repository) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			path, err := missingRequiredFile(ctx, r)
+			if err != nil {
+				log.Printf("Warning: could not check required files of %s, processing it: %v", r.FullName, err)
+				return
+			}
+			missing[i] = path
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var kept []models.Repository
+	for i, repo := range repos {
+		if missing[i] == "" {
+			kept = append(kept, repo)
+			continue
+		}
+		requiredFileSkips = append(requiredFileSkips, requiredFileSkip(repo, missing[i]))
+	}
+	log.Printf("%d repositories have the required files, %d skipped", len(kept), len(requiredFileSkips))
+	return kept
+}
+
+// requiredFileSkip records a repository skipped for lacking a required file
+func requiredFileSkip(repo models.Repository, path string) errors.ProcessingResult {
+	log.Printf("Skipping %s: missing required file %s", repo.FullName, path)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Message:    fmt.Sprintf("skipped: missing required file %s", path),
+		Skipped:    true,
+		Action:     "skipped",
+	}
+}
+
+// missingRequiredFile returns the first of --required-files the repository lacks, or
+// an empty string when it has them all
+func missingRequiredFile(ctx context.Context, repo models.Repository) (string, error) {
+	for _, path := range config.Runtime.RequiredFiles {
+		var found bool
+		var err error
+		if repo.Source == harness.SourceHarnessCode {
+			_, found, err = harnessClient.GetCodeFile(ctx, repo, repo.DefaultBranch, path)
+		} else {
+			found, err = githubClient.FileExists(ctx, repo, path)
+		}
+		if err != nil {
+			return "", fmt.Errorf("error checking %s: %w", path, err)
+		}
+		if !found {
+			return path, nil
+		}
+	}
+	return "", nil
+}
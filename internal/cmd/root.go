@@ -6,25 +6,37 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"path"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	gogithub "github.com/google/go-github/v50/github"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 
+	"harness-onboarder/internal/catalog"
 	"harness-onboarder/internal/errors"
 	"harness-onboarder/internal/github"
 	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/httpcache"
+	"harness-onboarder/internal/httplog"
+	"harness-onboarder/internal/network"
 	"harness-onboarder/internal/models"
+	"harness-onboarder/internal/state"
 )
 
+const defaultStateFile = ".harness-onboarder-state.json"
+
 var (
 	cfgFile     string
 	config      models.Config
 	githubClient *github.Client
 	harnessClient *harness.Client
+	stateManager *state.Manager
 )
 
 var rootCmd = &cobra.Command{
@@ -34,7 +46,11 @@ var rootCmd = &cobra.Command{
 extracts metadata, and onboards them into Harness IDP using:
 - YAML mode (PR generation)
 - API mode (direct ingestion) 
-- Register mode (register existing catalog-info.yaml files)`,
+- Register mode (register existing catalog-info.yaml files)
+- Sync mode (update existing components from current repository state)
+- Offboard mode (delete components for archived or deleted repositories)
+- Audit mode (read-only coverage check against Harness IDP)
+- Migrate mode (PRs converting Backstage catalog files to IDP 2.0)`,
 	RunE: runOnboarder,
 }
 
@@ -47,38 +63,130 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config.yaml)")
 	
-	rootCmd.Flags().StringP("org", "o", "", "GitHub organization")
-	rootCmd.Flags().StringP("mode", "m", "yaml", "Onboarding mode: yaml, api, or register")
-	rootCmd.Flags().IntP("concurrency", "c", 5, "Number of concurrent operations")
+	rootCmd.PersistentFlags().StringP("org", "o", "", "GitHub organization")
+	rootCmd.Flags().StringP("mode", "m", "yaml", "Onboarding mode: yaml, api, register, sync, offboard, audit, or migrate")
+	rootCmd.Flags().String("source", "github", "Where repositories are hosted: github, or harness-code to register catalog files from the Harness Code repositories of --harness-project-id")
+	rootCmd.PersistentFlags().IntP("concurrency", "c", 5, "Number of concurrent operations")
 	rootCmd.Flags().Bool("dry-run", false, "Dry run mode - don't make actual changes")
-	rootCmd.Flags().String("log-level", "info", "Log level (debug, info, warn, error)")
-	rootCmd.Flags().StringSlice("include-repos", []string{}, "Specific repositories to include")
-	rootCmd.Flags().StringSlice("exclude-repos", []string{}, "Repositories to exclude")
+	rootCmd.PersistentFlags().String("log-level", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().String("proxy-url", "", "Proxy for GitHub and Harness requests (default: HTTP_PROXY/HTTPS_PROXY; NO_PROXY is honored)")
+	rootCmd.PersistentFlags().String("ca-bundle", "", "PEM file of extra CA certificates to trust for GitHub and Harness (e.g. a TLS-intercepting proxy)")
+	rootCmd.PersistentFlags().String("client-cert", "", "PEM client certificate for servers or proxies requiring mTLS (requires --client-key)")
+	rootCmd.PersistentFlags().String("client-key", "", "PEM private key of --client-cert")
+	rootCmd.PersistentFlags().Bool("trace-http", false, "Log every GitHub and Harness HTTP call (method, URL, status, latency, truncated bodies) with credentials redacted")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Cache GitHub responses in this directory and revalidate them with ETags, so repeated runs use almost no rate limit")
+	rootCmd.PersistentFlags().StringSlice("include-repos", []string{}, "Repositories to include: names, globs such as payments-*, or regular expressions prefixed with re:")
+	rootCmd.PersistentFlags().StringSlice("exclude-repos", []string{}, "Repositories to exclude: names, globs, or regular expressions prefixed with re:")
+	rootCmd.PersistentFlags().StringSlice("include-topics", []string{}, "Only process repositories with at least one of these GitHub topics")
+	rootCmd.PersistentFlags().StringSlice("exclude-topics", []string{}, "Skip repositories with any of these GitHub topics")
+	rootCmd.PersistentFlags().StringSlice("visibility", []string{}, "Only process repositories with one of these visibilities: public, private, internal")
+	rootCmd.PersistentFlags().Bool("exclude-forks", false, "Skip forked repositories")
+	rootCmd.PersistentFlags().Bool("exclude-templates", false, "Skip template repositories")
+	rootCmd.PersistentFlags().Bool("exclude-mirrors", false, "Skip mirror repositories")
+	rootCmd.PersistentFlags().String("active-within", "", "Skip repositories not pushed to within this age (e.g. 180d, 720h)")
+	rootCmd.PersistentFlags().Int("min-stars", 0, "Skip repositories with fewer stars")
+	rootCmd.PersistentFlags().Int("min-size-kb", 0, "Skip repositories smaller than this many KB")
+	rootCmd.PersistentFlags().Int("max-size-kb", 0, "Skip repositories larger than this many KB (0 for no limit)")
+	rootCmd.PersistentFlags().String("team", "", "Only process repositories this GitHub team slug has admin or write access to")
+	rootCmd.PersistentFlags().String("filter", "", `CEL expression selecting repositories, e.g. repo.Language == "Go" && !repo.Archived && "platform" in repo.Topics`)
 	
-	rootCmd.Flags().String("github-app-id", "", "GitHub App ID")
-	rootCmd.Flags().String("github-private-key", "", "GitHub App private key file path")
-	rootCmd.Flags().String("github-private-key-b64", "", "GitHub App private key (base64 encoded)")
-	rootCmd.Flags().String("github-install-id", "", "GitHub App installation ID")
+	rootCmd.PersistentFlags().String("github-app-id", "", "GitHub App ID")
+	rootCmd.PersistentFlags().String("github-private-key", "", "GitHub App private key file path")
+	rootCmd.PersistentFlags().String("github-private-key-b64", "", "GitHub App private key (base64 encoded)")
+	rootCmd.PersistentFlags().String("github-install-id", "", "GitHub App installation ID")
 	
-	rootCmd.Flags().String("harness-api-key", "", "Harness API key")
-	rootCmd.Flags().String("harness-account-id", "", "Harness account ID")
-	rootCmd.Flags().String("harness-org-id", "", "Harness organization ID")
-	rootCmd.Flags().String("harness-project-id", "", "Harness project ID")
-	rootCmd.Flags().String("harness-base-url", "https://app.harness.io", "Harness base URL")
+	rootCmd.PersistentFlags().String("harness-api-key", "", "Harness API key")
+	rootCmd.PersistentFlags().String("harness-account-id", "", "Harness account ID")
+	rootCmd.PersistentFlags().String("harness-org-id", "", "Harness organization ID")
+	rootCmd.PersistentFlags().String("harness-project-id", "", "Harness project ID")
+	rootCmd.PersistentFlags().String("harness-base-url", "https://app.harness.io", "Harness base URL")
 	
-	rootCmd.Flags().String("default-owner", "", "Default owner for components")
-	rootCmd.Flags().String("default-type", "service", "Default component type")
-	rootCmd.Flags().String("default-lifecycle", "production", "Default lifecycle")
-	rootCmd.Flags().String("default-system", "", "Default system")
-	rootCmd.Flags().StringToString("default-tags", map[string]string{}, "Default tags (key=value pairs)")
-	rootCmd.Flags().StringToString("default-annotations", map[string]string{}, "Default annotations (key=value pairs)")
-
-	rootCmd.Flags().String("harness-connector-ref", "", "Harness connector reference")
-
-	rootCmd.Flags().Duration("rate-limit", 100*time.Millisecond, "Rate limit between API calls")
-	rootCmd.Flags().StringSlice("required-files", []string{}, "Required files that must exist in repositories")
+	rootCmd.PersistentFlags().String("default-owner", "", "Default owner for components")
+	rootCmd.PersistentFlags().String("default-kind", "Component", "Default entity kind: Component, API, Resource, System or Group")
+	rootCmd.PersistentFlags().String("default-type", "service", "Default component type")
+	rootCmd.PersistentFlags().String("default-lifecycle", "production", "Default lifecycle")
+	rootCmd.PersistentFlags().String("default-system", "", "Default system")
+	rootCmd.PersistentFlags().String("default-domain", "", "Default domain of System entities")
+	rootCmd.PersistentFlags().StringToString("default-tags", map[string]string{}, "Default tags (key=value pairs)")
+	rootCmd.PersistentFlags().StringToString("default-annotations", map[string]string{}, "Default annotations (key=value pairs)")
+	rootCmd.PersistentFlags().Bool("skip-type-inference", false, "Always use --default-type instead of inferring service, library or website per repository")
+	rootCmd.PersistentFlags().Bool("skip-lifecycle-inference", false, "Always use --default-lifecycle instead of inferring deprecated or experimental per repository")
+	rootCmd.PersistentFlags().Int("experimental-days", 30, "Repositories created within this many days are experimental (negative disables)")
+	rootCmd.PersistentFlags().StringSlice("experimental-topics", []string{"poc", "prototype"}, "Topics that mark a repository as experimental")
+	rootCmd.PersistentFlags().Bool("include-archived", false, "Process archived repositories too, with lifecycle deprecated")
+
+	rootCmd.PersistentFlags().String("harness-connector-ref", "", "Harness connector reference")
+	rootCmd.PersistentFlags().String("harness-scope", "", "Scope entities are created at: project (default), org or account, e.g. account for shared libraries")
+	rootCmd.PersistentFlags().Int("harness-breaker-threshold", 5, "Consecutive Harness API server errors or timeouts before requests pause")
+	rootCmd.PersistentFlags().Duration("harness-breaker-cooldown", time.Minute, "How long requests pause once the Harness API circuit breaker trips")
+	rootCmd.PersistentFlags().Duration("harness-timeout", 30*time.Second, "Timeout for each Harness API request")
+	rootCmd.PersistentFlags().Int("harness-max-idle-conns", 10, "Idle connections kept open to the Harness API")
+	rootCmd.PersistentFlags().Duration("harness-tls-handshake-timeout", 10*time.Second, "TLS handshake timeout for Harness API connections")
+	rootCmd.PersistentFlags().Int("harness-retries", 0, "Retries of Harness API requests that time out or get a 429 or 5xx response")
+
+	rootCmd.PersistentFlags().Duration("rate-limit", 0, "Fixed delay before processing each repository; GitHub calls are paced from its rate limit headers either way")
+	rootCmd.PersistentFlags().StringSlice("required-files", []string{}, "Skip repositories missing any of these files, e.g. Dockerfile,Makefile")
+
+	rootCmd.PersistentFlags().String("state-file", "", "State file used to skip unchanged repositories on subsequent runs (path, s3://bucket/key, gs://bucket/object or sqlite:///path.db)")
+	rootCmd.PersistentFlags().Duration("state-lock-wait", 0, "How long to wait for another run holding the state lock before giving up")
+	rootCmd.PersistentFlags().Duration("state-lock-ttl", 10*time.Minute, "Expiry of the state lock, renewed while the run goes on, after which a crashed run's lock is taken over")
+	rootCmd.Flags().Bool("daemon", false, "Run continuously, reconciling every --interval")
+	rootCmd.Flags().Duration("interval", 6*time.Hour, "Reconcile interval in daemon mode")
+	rootCmd.Flags().Bool("sync-teams", false, "Create IDP Group entities for the organization's GitHub teams before onboarding")
+	rootCmd.Flags().String("legacy-strategy", "convert", "How register mode handles Backstage-format catalog files: convert, pr, or import")
+	rootCmd.Flags().Bool("only-failed", false, "Only reprocess repositories whose last run failed, according to the state file")
+	rootCmd.Flags().Duration("retry-backoff", 15*time.Minute, "Minimum wait before retrying a failed repository, doubled on each consecutive failure")
+	rootCmd.Flags().Bool("merge-existing", false, "In yaml mode, merge generated changes into existing catalog files instead of skipping them")
+	rootCmd.Flags().Bool("patch-existing", false, "In yaml mode, only add missing identifier, scope fields and annotations to existing catalog files")
+	rootCmd.Flags().StringSlice("pr-labels", []string{}, "Labels added to onboarding PRs and used to find existing ones (e.g. harness-idp)")
+	rootCmd.Flags().StringSlice("pr-assignees", []string{}, "GitHub users assigned to onboarding PRs")
+	rootCmd.Flags().String("pr-milestone", "", "Title of the milestone set on onboarding PRs")
+	rootCmd.Flags().String("pr-title-template", "", "Go template file for onboarding PR titles")
+	rootCmd.Flags().String("pr-body-template", "", "Go template file for onboarding PR bodies")
+	rootCmd.Flags().String("branch-template", "", "Go template for onboarding branch names (default harness-onboarding-{{ .Timestamp }}); without .Timestamp the branch is reused")
+	rootCmd.Flags().String("commit-author-name", "", "Author and committer name for onboarding commits")
+	rootCmd.Flags().String("commit-author-email", "", "Author and committer email for onboarding commits")
+	rootCmd.Flags().Bool("commit-status", false, "Post a commit status with the catalog lint result on onboarding commits")
+	rootCmd.Flags().String("base-branch", "", "Branch onboarding PRs target instead of each repository's default branch (e.g. develop)")
+	rootCmd.Flags().String("catalog-repo", "", "Central repository (owner/name) to write all catalog files to in one PR, and to register them from")
+	rootCmd.Flags().String("catalog-dir", "", "Directory in the central catalog repository holding catalog files (default catalogs)")
+	rootCmd.Flags().String("batch-by", "", "With --catalog-repo, open one PR per owner (owner) instead of one for the whole run")
+	rootCmd.Flags().Int("batch-size", 0, "With --catalog-repo, maximum repositories per PR (0 for no limit)")
+	rootCmd.Flags().Bool("update-open-prs", false, "Push refreshed catalog content to open onboarding PRs instead of skipping them")
+	rootCmd.Flags().String("catalog-path", "", "Path of the catalog file written in yaml mode (default catalog-info.yaml), e.g. .harness/catalog-info.yaml")
+	rootCmd.Flags().Bool("commit-direct", false, "In yaml mode, commit catalog files straight to the default branch instead of opening a PR")
+	rootCmd.Flags().String("onboarded-topic", "", "GitHub topic added to repositories once they are registered in IDP, e.g. harness-idp-onboarded")
+	rootCmd.Flags().Bool("issue-fallback", false, "Open an issue with the generated catalog file when permissions or branch protection block the PR")
+	rootCmd.Flags().Int("api-batch-size", 0, "In api mode, create components in batches of this many pipelined requests (0 to create them one repository at a time)")
+	rootCmd.Flags().Int("register-batch-size", 0, "In register mode, import catalog files in batches of this many pipelined requests, retrying rate limits with backoff (0 to import them one repository at a time)")
+	rootCmd.Flags().Bool("code-search", false, "In register mode, find catalog files with one GitHub code search over the organization instead of probing each repository")
+	rootCmd.Flags().Bool("provision-connector", false, "Create or update the Harness GitHub connector from the GitHub App credentials before registering (default ref "+defaultConnectorRef+")")
+	rootCmd.Flags().Bool("scorecards", false, "Include the IDP scorecard scores of created and registered entities in the run summary and report")
+	rootCmd.Flags().StringSlice("policy", []string{}, "Rego policy files or directories; generated entities with data.idp.deny violations are not submitted")
+	rootCmd.Flags().Bool("skip-harness-validation", false, "Don't dry-run generated catalog files against the Harness entities API before opening PRs")
+	rootCmd.Flags().Bool("techdocs", false, "Also add an mkdocs.yml and docs/index.md skeleton to onboarding PRs in yaml mode")
+	rootCmd.Flags().Bool("pipeline-starter", false, "Also add a minimal .harness/pipeline.yaml CI pipeline to onboarding PRs in yaml mode")
+	rootCmd.Flags().String("pipeline-template", "", "Go template file for the pipeline starter, rendered against the repository and Harness scope")
+	rootCmd.Flags().Bool("readme-badge", false, "Also add a \"View in Harness IDP\" badge to README.md in onboarding PRs in yaml mode")
+	rootCmd.Flags().String("readme-badge-url", "", "Go template for the README badge link (default: the entity's IDP catalog page)")
+	rootCmd.Flags().String("report-file", "", "Write a Markdown (.md) or HTML (.html) report of the run to this file")
+	rootCmd.PersistentFlags().Float64("language-threshold", 10, "Minimum percentage of code for a language to be added as a tag")
+	rootCmd.PersistentFlags().String("identifier-template", "", "Go template for entity identifiers, e.g. '{{ .Org }}_{{ .Repo | snakecase }}'")
+	rootCmd.PersistentFlags().String("identifier-prefix", "", "Prefix added to every generated identifier")
+	rootCmd.PersistentFlags().String("identifier-suffix", "", "Suffix added to every generated identifier")
+	rootCmd.PersistentFlags().String("owners-map", "", "YAML file mapping GitHub users and teams to Harness owners (e.g. group:account/platform)")
+	rootCmd.Flags().Bool("validate-owners", false, "Check owner groups exist in Harness before creating components, falling back to the default owner")
+	rootCmd.Flags().Bool("create-owner-groups", false, "Create owner groups missing in Harness as empty user groups so ownership works immediately")
+	rootCmd.PersistentFlags().String("sort", "name", "Order repositories are processed in: name, or pushed for the most recently pushed first")
+	rootCmd.PersistentFlags().String("shard", "", "Process only this part of the repositories, as index/count (e.g. 2/5), so parallel runners split the organization")
+	rootCmd.PersistentFlags().Bool("stream", false, "Process each page of repositories as it's listed instead of discovering the whole organization first (yaml, api and register modes)")
+	rootCmd.PersistentFlags().Int("limit", 0, "Process at most this many repositories after filtering and sorting, e.g. for a pilot run (0 for all)")
+	rootCmd.PersistentFlags().String("search", "", "GitHub repository search query selecting the repositories to process, e.g. 'topic:service archived:false' (limited to the organization unless it has an org:, user: or repo: qualifier)")
+	rootCmd.PersistentFlags().String("repos-file", "", "File listing repositories to process, one name or org/repo per line (- for stdin)")
+	rootCmd.PersistentFlags().String("repos-csv", "", "CSV of repositories to process with per-repo owner, type, lifecycle, system and tags")
 
 	viper.BindPFlags(rootCmd.Flags())
+	viper.BindPFlags(rootCmd.PersistentFlags())
 }
 
 func initConfig() {
@@ -110,6 +218,12 @@ func initConfig() {
 	}
 
 	setDefaults()
+	httplog.Enabled = config.Runtime.TraceHTTP
+	httpcache.Dir = config.Runtime.CacheDir
+	if err := network.Configure(config.Network); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in network config: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 func bindEnvVariables() {
@@ -119,6 +233,20 @@ func bindEnvVariables() {
 	viper.BindEnv("github-private-key", "HARNESS_ONBOARDER_GITHUB_PRIVATE_KEY")
 	viper.BindEnv("github-private-key-b64", "HARNESS_ONBOARDER_GITHUB_PRIVATE_KEY_B64")
 	viper.BindEnv("github-install-id", "HARNESS_ONBOARDER_GITHUB_INSTALL_ID")
+	viper.BindEnv("pr-labels", "HARNESS_ONBOARDER_PR_LABELS")
+	viper.BindEnv("pr-assignees", "HARNESS_ONBOARDER_PR_ASSIGNEES")
+	viper.BindEnv("pr-milestone", "HARNESS_ONBOARDER_PR_MILESTONE")
+	viper.BindEnv("pr-title-template", "HARNESS_ONBOARDER_PR_TITLE_TEMPLATE")
+	viper.BindEnv("pr-body-template", "HARNESS_ONBOARDER_PR_BODY_TEMPLATE")
+	viper.BindEnv("branch-template", "HARNESS_ONBOARDER_BRANCH_TEMPLATE")
+	viper.BindEnv("commit-status", "HARNESS_ONBOARDER_COMMIT_STATUS")
+	viper.BindEnv("base-branch", "HARNESS_ONBOARDER_BASE_BRANCH")
+	viper.BindEnv("catalog-repo", "HARNESS_ONBOARDER_CATALOG_REPO")
+	viper.BindEnv("catalog-dir", "HARNESS_ONBOARDER_CATALOG_DIR")
+	viper.BindEnv("batch-by", "HARNESS_ONBOARDER_BATCH_BY")
+	viper.BindEnv("batch-size", "HARNESS_ONBOARDER_BATCH_SIZE")
+	viper.BindEnv("commit-author-name", "HARNESS_ONBOARDER_COMMIT_AUTHOR_NAME")
+	viper.BindEnv("commit-author-email", "HARNESS_ONBOARDER_COMMIT_AUTHOR_EMAIL")
 
 	// Harness configuration
 	viper.BindEnv("harness-api-key", "HARNESS_ONBOARDER_HARNESS_API_KEY")
@@ -127,24 +255,100 @@ func bindEnvVariables() {
 	viper.BindEnv("harness-project-id", "HARNESS_ONBOARDER_HARNESS_PROJECT_ID")
 	viper.BindEnv("harness-base-url", "HARNESS_ONBOARDER_HARNESS_BASE_URL")
 	viper.BindEnv("harness-connector-ref", "HARNESS_ONBOARDER_HARNESS_CONNECTOR_REF")
+	viper.BindEnv("harness-scope", "HARNESS_ONBOARDER_HARNESS_SCOPE")
+	viper.BindEnv("harness-breaker-threshold", "HARNESS_ONBOARDER_HARNESS_BREAKER_THRESHOLD")
+	viper.BindEnv("harness-breaker-cooldown", "HARNESS_ONBOARDER_HARNESS_BREAKER_COOLDOWN")
+	viper.BindEnv("harness-timeout", "HARNESS_ONBOARDER_HARNESS_TIMEOUT")
+	viper.BindEnv("harness-max-idle-conns", "HARNESS_ONBOARDER_HARNESS_MAX_IDLE_CONNS")
+	viper.BindEnv("harness-tls-handshake-timeout", "HARNESS_ONBOARDER_HARNESS_TLS_HANDSHAKE_TIMEOUT")
+	viper.BindEnv("harness-retries", "HARNESS_ONBOARDER_HARNESS_RETRIES")
 
 	// Defaults configuration
 	viper.BindEnv("default-owner", "HARNESS_ONBOARDER_DEFAULT_OWNER")
+	viper.BindEnv("default-kind", "HARNESS_ONBOARDER_DEFAULT_KIND")
 	viper.BindEnv("default-type", "HARNESS_ONBOARDER_DEFAULT_TYPE")
 	viper.BindEnv("default-lifecycle", "HARNESS_ONBOARDER_DEFAULT_LIFECYCLE")
 	viper.BindEnv("default-system", "HARNESS_ONBOARDER_DEFAULT_SYSTEM")
+	viper.BindEnv("default-domain", "HARNESS_ONBOARDER_DEFAULT_DOMAIN")
 	viper.BindEnv("default-tags", "HARNESS_ONBOARDER_DEFAULT_TAGS")
 	viper.BindEnv("default-annotations", "HARNESS_ONBOARDER_DEFAULT_ANNOTATIONS")
+	viper.BindEnv("skip-type-inference", "HARNESS_ONBOARDER_SKIP_TYPE_INFERENCE")
+	viper.BindEnv("skip-lifecycle-inference", "HARNESS_ONBOARDER_SKIP_LIFECYCLE_INFERENCE")
+	viper.BindEnv("experimental-days", "HARNESS_ONBOARDER_EXPERIMENTAL_DAYS")
+	viper.BindEnv("experimental-topics", "HARNESS_ONBOARDER_EXPERIMENTAL_TOPICS")
+	viper.BindEnv("include-archived", "HARNESS_ONBOARDER_INCLUDE_ARCHIVED")
 
 	// Runtime configuration
 	viper.BindEnv("mode", "HARNESS_ONBOARDER_MODE")
+	viper.BindEnv("source", "HARNESS_ONBOARDER_SOURCE")
 	viper.BindEnv("concurrency", "HARNESS_ONBOARDER_CONCURRENCY")
 	viper.BindEnv("dry-run", "HARNESS_ONBOARDER_DRY_RUN")
 	viper.BindEnv("log-level", "HARNESS_ONBOARDER_LOG_LEVEL")
+	viper.BindEnv("trace-http", "HARNESS_ONBOARDER_TRACE_HTTP")
+	viper.BindEnv("cache-dir", "HARNESS_ONBOARDER_CACHE_DIR")
+	viper.BindEnv("proxy-url", "HARNESS_ONBOARDER_PROXY_URL")
+	viper.BindEnv("ca-bundle", "HARNESS_ONBOARDER_CA_BUNDLE")
+	viper.BindEnv("client-cert", "HARNESS_ONBOARDER_CLIENT_CERT")
+	viper.BindEnv("client-key", "HARNESS_ONBOARDER_CLIENT_KEY")
 	viper.BindEnv("include-repos", "HARNESS_ONBOARDER_INCLUDE_REPOS")
 	viper.BindEnv("exclude-repos", "HARNESS_ONBOARDER_EXCLUDE_REPOS")
+	viper.BindEnv("include-topics", "HARNESS_ONBOARDER_INCLUDE_TOPICS")
+	viper.BindEnv("exclude-topics", "HARNESS_ONBOARDER_EXCLUDE_TOPICS")
+	viper.BindEnv("visibility", "HARNESS_ONBOARDER_VISIBILITY")
+	viper.BindEnv("exclude-forks", "HARNESS_ONBOARDER_EXCLUDE_FORKS")
+	viper.BindEnv("exclude-templates", "HARNESS_ONBOARDER_EXCLUDE_TEMPLATES")
+	viper.BindEnv("exclude-mirrors", "HARNESS_ONBOARDER_EXCLUDE_MIRRORS")
+	viper.BindEnv("active-within", "HARNESS_ONBOARDER_ACTIVE_WITHIN")
+	viper.BindEnv("min-stars", "HARNESS_ONBOARDER_MIN_STARS")
+	viper.BindEnv("min-size-kb", "HARNESS_ONBOARDER_MIN_SIZE_KB")
+	viper.BindEnv("max-size-kb", "HARNESS_ONBOARDER_MAX_SIZE_KB")
+	viper.BindEnv("filter", "HARNESS_ONBOARDER_FILTER")
+	viper.BindEnv("team", "HARNESS_ONBOARDER_TEAM")
 	viper.BindEnv("rate-limit", "HARNESS_ONBOARDER_RATE_LIMIT")
 	viper.BindEnv("required-files", "HARNESS_ONBOARDER_REQUIRED_FILES")
+	viper.BindEnv("state-file", "HARNESS_ONBOARDER_STATE_FILE")
+	viper.BindEnv("state-lock-wait", "HARNESS_ONBOARDER_STATE_LOCK_WAIT")
+	viper.BindEnv("state-lock-ttl", "HARNESS_ONBOARDER_STATE_LOCK_TTL")
+	viper.BindEnv("daemon", "HARNESS_ONBOARDER_DAEMON")
+	viper.BindEnv("interval", "HARNESS_ONBOARDER_INTERVAL")
+	viper.BindEnv("sync-teams", "HARNESS_ONBOARDER_SYNC_TEAMS")
+	viper.BindEnv("repos-csv", "HARNESS_ONBOARDER_REPOS_CSV")
+	viper.BindEnv("repos-file", "HARNESS_ONBOARDER_REPOS_FILE")
+	viper.BindEnv("search", "HARNESS_ONBOARDER_SEARCH")
+	viper.BindEnv("sort", "HARNESS_ONBOARDER_SORT")
+	viper.BindEnv("limit", "HARNESS_ONBOARDER_LIMIT")
+	viper.BindEnv("shard", "HARNESS_ONBOARDER_SHARD")
+	viper.BindEnv("stream", "HARNESS_ONBOARDER_STREAM")
+	viper.BindEnv("owners-map", "HARNESS_ONBOARDER_OWNERS_MAP")
+	viper.BindEnv("validate-owners", "HARNESS_ONBOARDER_VALIDATE_OWNERS")
+	viper.BindEnv("create-owner-groups", "HARNESS_ONBOARDER_CREATE_OWNER_GROUPS")
+	viper.BindEnv("report-file", "HARNESS_ONBOARDER_REPORT_FILE")
+	viper.BindEnv("techdocs", "HARNESS_ONBOARDER_TECHDOCS")
+	viper.BindEnv("api-batch-size", "HARNESS_ONBOARDER_API_BATCH_SIZE")
+	viper.BindEnv("register-batch-size", "HARNESS_ONBOARDER_REGISTER_BATCH_SIZE")
+	viper.BindEnv("code-search", "HARNESS_ONBOARDER_CODE_SEARCH")
+	viper.BindEnv("skip-harness-validation", "HARNESS_ONBOARDER_SKIP_HARNESS_VALIDATION")
+	viper.BindEnv("policy", "HARNESS_ONBOARDER_POLICY")
+	viper.BindEnv("provision-connector", "HARNESS_ONBOARDER_PROVISION_CONNECTOR")
+	viper.BindEnv("scorecards", "HARNESS_ONBOARDER_SCORECARDS")
+	viper.BindEnv("pipeline-starter", "HARNESS_ONBOARDER_PIPELINE_STARTER")
+	viper.BindEnv("pipeline-template", "HARNESS_ONBOARDER_PIPELINE_TEMPLATE")
+	viper.BindEnv("readme-badge", "HARNESS_ONBOARDER_README_BADGE")
+	viper.BindEnv("readme-badge-url", "HARNESS_ONBOARDER_README_BADGE_URL")
+	viper.BindEnv("update-open-prs", "HARNESS_ONBOARDER_UPDATE_OPEN_PRS")
+	viper.BindEnv("catalog-path", "HARNESS_ONBOARDER_CATALOG_PATH")
+	viper.BindEnv("commit-direct", "HARNESS_ONBOARDER_COMMIT_DIRECT")
+	viper.BindEnv("issue-fallback", "HARNESS_ONBOARDER_ISSUE_FALLBACK")
+	viper.BindEnv("onboarded-topic", "HARNESS_ONBOARDER_ONBOARDED_TOPIC")
+	viper.BindEnv("patch-existing", "HARNESS_ONBOARDER_PATCH_EXISTING")
+	viper.BindEnv("merge-existing", "HARNESS_ONBOARDER_MERGE_EXISTING")
+	viper.BindEnv("language-threshold", "HARNESS_ONBOARDER_LANGUAGE_THRESHOLD")
+	viper.BindEnv("identifier-template", "HARNESS_ONBOARDER_IDENTIFIER_TEMPLATE")
+	viper.BindEnv("identifier-prefix", "HARNESS_ONBOARDER_IDENTIFIER_PREFIX")
+	viper.BindEnv("identifier-suffix", "HARNESS_ONBOARDER_IDENTIFIER_SUFFIX")
+	viper.BindEnv("only-failed", "HARNESS_ONBOARDER_ONLY_FAILED")
+	viper.BindEnv("legacy-strategy", "HARNESS_ONBOARDER_LEGACY_STRATEGY")
+	viper.BindEnv("retry-backoff", "HARNESS_ONBOARDER_RETRY_BACKOFF")
 }
 
 func setDefaults() {
@@ -166,6 +370,42 @@ func setDefaults() {
 	if viper.IsSet("github-private-key") {
 		config.GitHub.PrivateKey = viper.GetString("github-private-key")
 	}
+	if viper.IsSet("pr-labels") {
+		config.GitHub.PRLabels = viper.GetStringSlice("pr-labels")
+	}
+	if viper.IsSet("pr-assignees") {
+		config.GitHub.PRAssignees = viper.GetStringSlice("pr-assignees")
+	}
+	if viper.IsSet("pr-milestone") {
+		config.GitHub.PRMilestone = viper.GetString("pr-milestone")
+	}
+	if viper.IsSet("pr-title-template") {
+		config.GitHub.PRTitleTemplate = viper.GetString("pr-title-template")
+	}
+	if viper.IsSet("pr-body-template") {
+		config.GitHub.PRBodyTemplate = viper.GetString("pr-body-template")
+	}
+	if viper.IsSet("branch-template") {
+		config.GitHub.BranchTemplate = viper.GetString("branch-template")
+	}
+	if viper.IsSet("commit-author-name") {
+		config.GitHub.CommitAuthorName = viper.GetString("commit-author-name")
+	}
+	if viper.IsSet("commit-author-email") {
+		config.GitHub.CommitAuthorEmail = viper.GetString("commit-author-email")
+	}
+	if viper.IsSet("commit-status") {
+		config.GitHub.CommitStatus = viper.GetBool("commit-status")
+	}
+	if viper.IsSet("base-branch") {
+		config.GitHub.BaseBranch = viper.GetString("base-branch")
+	}
+	if viper.IsSet("catalog-repo") {
+		config.GitHub.CatalogRepo = viper.GetString("catalog-repo")
+	}
+	if viper.IsSet("catalog-dir") {
+		config.GitHub.CatalogDir = viper.GetString("catalog-dir")
+	}
 
 	// Handle base64-encoded private key for container deployments
 	if viper.IsSet("github-private-key-b64") {
@@ -227,10 +467,34 @@ func setDefaults() {
 	if viper.IsSet("harness-connector-ref") {
 		config.Harness.ConnectorRef = viper.GetString("harness-connector-ref")
 	}
+	if viper.IsSet("harness-scope") {
+		config.Harness.Scope = viper.GetString("harness-scope")
+	}
+	if viper.IsSet("harness-breaker-threshold") {
+		config.Harness.BreakerThreshold = viper.GetInt("harness-breaker-threshold")
+	}
+	if viper.IsSet("harness-breaker-cooldown") {
+		config.Harness.BreakerCooldown = viper.GetDuration("harness-breaker-cooldown")
+	}
+	if viper.IsSet("harness-timeout") {
+		config.Harness.Timeout = viper.GetDuration("harness-timeout")
+	}
+	if viper.IsSet("harness-max-idle-conns") {
+		config.Harness.MaxIdleConns = viper.GetInt("harness-max-idle-conns")
+	}
+	if viper.IsSet("harness-tls-handshake-timeout") {
+		config.Harness.TLSHandshakeTimeout = viper.GetDuration("harness-tls-handshake-timeout")
+	}
+	if viper.IsSet("harness-retries") {
+		config.Harness.Retries = viper.GetInt("harness-retries")
+	}
 
 	if viper.IsSet("default-owner") {
 		config.Defaults.Owner = viper.GetString("default-owner")
 	}
+	if viper.IsSet("default-kind") {
+		config.Defaults.Kind = viper.GetString("default-kind")
+	}
 	if viper.IsSet("default-type") {
 		config.Defaults.Type = viper.GetString("default-type")
 	}
@@ -240,16 +504,37 @@ func setDefaults() {
 	if viper.IsSet("default-system") {
 		config.Defaults.System = viper.GetString("default-system")
 	}
+	if viper.IsSet("default-domain") {
+		config.Defaults.Domain = viper.GetString("default-domain")
+	}
 	if viper.IsSet("default-tags") {
 		config.Defaults.Tags = viper.GetStringMapString("default-tags")
 	}
 	if viper.IsSet("default-annotations") {
 		config.Defaults.Annotations = viper.GetStringMapString("default-annotations")
 	}
+	if viper.IsSet("skip-type-inference") {
+		config.Defaults.SkipTypeInference = viper.GetBool("skip-type-inference")
+	}
+	if viper.IsSet("skip-lifecycle-inference") {
+		config.Defaults.SkipLifecycleInference = viper.GetBool("skip-lifecycle-inference")
+	}
+	if viper.IsSet("experimental-days") {
+		config.Defaults.ExperimentalDays = viper.GetInt("experimental-days")
+	}
+	if viper.IsSet("experimental-topics") {
+		config.Defaults.ExperimentalTopics = viper.GetStringSlice("experimental-topics")
+	}
+	if viper.IsSet("include-archived") {
+		config.Runtime.IncludeArchived = viper.GetBool("include-archived")
+	}
 
 	if viper.IsSet("mode") {
 		config.Runtime.Mode = viper.GetString("mode")
 	}
+	if viper.IsSet("source") {
+		config.Runtime.Source = viper.GetString("source")
+	}
 	if viper.IsSet("concurrency") {
 		config.Runtime.Concurrency = viper.GetInt("concurrency")
 	}
@@ -259,32 +544,221 @@ func setDefaults() {
 	if viper.IsSet("log-level") {
 		config.Runtime.LogLevel = viper.GetString("log-level")
 	}
+	if viper.IsSet("trace-http") {
+		config.Runtime.TraceHTTP = viper.GetBool("trace-http")
+	}
+	if viper.IsSet("cache-dir") {
+		config.Runtime.CacheDir = viper.GetString("cache-dir")
+	}
+	if viper.IsSet("proxy-url") {
+		config.Network.ProxyURL = viper.GetString("proxy-url")
+	}
+	if viper.IsSet("ca-bundle") {
+		config.Network.CABundle = viper.GetString("ca-bundle")
+	}
+	if viper.IsSet("client-cert") {
+		config.Network.ClientCert = viper.GetString("client-cert")
+	}
+	if viper.IsSet("client-key") {
+		config.Network.ClientKey = viper.GetString("client-key")
+	}
 	if viper.IsSet("include-repos") {
 		config.Runtime.IncludeRepos = viper.GetStringSlice("include-repos")
 	}
 	if viper.IsSet("exclude-repos") {
 		config.Runtime.ExcludeRepos = viper.GetStringSlice("exclude-repos")
 	}
+	if viper.IsSet("include-topics") {
+		config.Runtime.IncludeTopics = viper.GetStringSlice("include-topics")
+	}
+	if viper.IsSet("exclude-topics") {
+		config.Runtime.ExcludeTopics = viper.GetStringSlice("exclude-topics")
+	}
+	if viper.IsSet("visibility") {
+		config.Runtime.Visibility = viper.GetStringSlice("visibility")
+	}
+	if viper.IsSet("exclude-forks") {
+		config.Runtime.ExcludeForks = viper.GetBool("exclude-forks")
+	}
+	if viper.IsSet("exclude-templates") {
+		config.Runtime.ExcludeTemplates = viper.GetBool("exclude-templates")
+	}
+	if viper.IsSet("exclude-mirrors") {
+		config.Runtime.ExcludeMirrors = viper.GetBool("exclude-mirrors")
+	}
+	if viper.IsSet("active-within") {
+		config.Runtime.ActiveWithin = viper.GetString("active-within")
+	}
+	if viper.IsSet("min-stars") {
+		config.Runtime.MinStars = viper.GetInt("min-stars")
+	}
+	if viper.IsSet("min-size-kb") {
+		config.Runtime.MinSizeKB = viper.GetInt("min-size-kb")
+	}
+	if viper.IsSet("max-size-kb") {
+		config.Runtime.MaxSizeKB = viper.GetInt("max-size-kb")
+	}
+	if viper.IsSet("filter") {
+		config.Runtime.Filter = viper.GetString("filter")
+	}
+	if viper.IsSet("team") {
+		config.Runtime.Team = viper.GetString("team")
+	}
 	if viper.IsSet("rate-limit") {
 		config.Runtime.RateLimit = viper.GetDuration("rate-limit")
 	}
 	if viper.IsSet("required-files") {
 		config.Runtime.RequiredFiles = viper.GetStringSlice("required-files")
 	}
+	if viper.IsSet("state-file") {
+		config.Runtime.StateFile = viper.GetString("state-file")
+	}
+	if viper.IsSet("state-lock-wait") {
+		config.Runtime.StateLockWait = viper.GetDuration("state-lock-wait")
+	}
+	if viper.IsSet("state-lock-ttl") {
+		config.Runtime.StateLockTTL = viper.GetDuration("state-lock-ttl")
+	}
+	if viper.IsSet("daemon") {
+		config.Runtime.Daemon = viper.GetBool("daemon")
+	}
+	if viper.IsSet("interval") {
+		config.Runtime.Interval = viper.GetDuration("interval")
+	}
+	if viper.IsSet("legacy-strategy") {
+		config.Runtime.LegacyStrategy = viper.GetString("legacy-strategy")
+	}
+	if viper.IsSet("only-failed") {
+		config.Runtime.OnlyFailed = viper.GetBool("only-failed")
+	}
+	if viper.IsSet("retry-backoff") {
+		config.Runtime.RetryBackoff = viper.GetDuration("retry-backoff")
+	}
+	if viper.IsSet("report-file") {
+		config.Runtime.ReportFile = viper.GetString("report-file")
+	}
+	if viper.IsSet("merge-existing") {
+		config.Runtime.MergeExisting = viper.GetBool("merge-existing")
+	}
+	if viper.IsSet("patch-existing") {
+		config.Runtime.PatchExisting = viper.GetBool("patch-existing")
+	}
+	if viper.IsSet("update-open-prs") {
+		config.Runtime.UpdateOpenPRs = viper.GetBool("update-open-prs")
+	}
+	if viper.IsSet("catalog-path") {
+		config.Runtime.CatalogPath = viper.GetString("catalog-path")
+	}
+	if viper.IsSet("commit-direct") {
+		config.Runtime.CommitDirect = viper.GetBool("commit-direct")
+	}
+	if viper.IsSet("issue-fallback") {
+		config.Runtime.IssueFallback = viper.GetBool("issue-fallback")
+	}
+	if viper.IsSet("onboarded-topic") {
+		config.Runtime.OnboardedTopic = viper.GetString("onboarded-topic")
+	}
+	if viper.IsSet("batch-by") {
+		config.Runtime.BatchBy = viper.GetString("batch-by")
+	}
+	if viper.IsSet("batch-size") {
+		config.Runtime.BatchSize = viper.GetInt("batch-size")
+	}
+	if viper.IsSet("techdocs") {
+		config.Runtime.TechDocs = viper.GetBool("techdocs")
+	}
+	if viper.IsSet("api-batch-size") {
+		config.Runtime.APIBatchSize = viper.GetInt("api-batch-size")
+	}
+	if viper.IsSet("register-batch-size") {
+		config.Runtime.RegisterBatchSize = viper.GetInt("register-batch-size")
+	}
+	if viper.IsSet("code-search") {
+		config.Runtime.CodeSearch = viper.GetBool("code-search")
+	}
+	if viper.IsSet("skip-harness-validation") {
+		config.Runtime.SkipHarnessValidation = viper.GetBool("skip-harness-validation")
+	}
+	if viper.IsSet("policy") {
+		config.Runtime.Policies = viper.GetStringSlice("policy")
+	}
+	if viper.IsSet("provision-connector") {
+		config.Runtime.ProvisionConnector = viper.GetBool("provision-connector")
+	}
+	if viper.IsSet("scorecards") {
+		config.Runtime.Scorecards = viper.GetBool("scorecards")
+	}
+	if viper.IsSet("pipeline-starter") {
+		config.Runtime.PipelineStarter = viper.GetBool("pipeline-starter")
+	}
+	if viper.IsSet("pipeline-template") {
+		config.Runtime.PipelineTemplate = viper.GetString("pipeline-template")
+	}
+	if viper.IsSet("readme-badge") {
+		config.Runtime.ReadmeBadge = viper.GetBool("readme-badge")
+	}
+	if viper.IsSet("readme-badge-url") {
+		config.Runtime.ReadmeBadgeURL = viper.GetString("readme-badge-url")
+	}
+	if viper.IsSet("language-threshold") {
+		config.Runtime.LanguageThreshold = viper.GetFloat64("language-threshold")
+	}
+	if viper.IsSet("identifier-template") {
+		config.Runtime.IdentifierTemplate = viper.GetString("identifier-template")
+	}
+	if viper.IsSet("identifier-prefix") {
+		config.Runtime.IdentifierPrefix = viper.GetString("identifier-prefix")
+	}
+	if viper.IsSet("identifier-suffix") {
+		config.Runtime.IdentifierSuffix = viper.GetString("identifier-suffix")
+	}
+	if viper.IsSet("sync-teams") {
+		config.Runtime.SyncTeams = viper.GetBool("sync-teams")
+	}
+	if viper.IsSet("owners-map") {
+		config.Runtime.OwnersMap = viper.GetString("owners-map")
+	}
+	if viper.IsSet("validate-owners") {
+		config.Runtime.ValidateOwners = viper.GetBool("validate-owners")
+	}
+	if viper.IsSet("create-owner-groups") {
+		config.Runtime.CreateOwnerGroups = viper.GetBool("create-owner-groups")
+	}
+	if viper.IsSet("repos-csv") {
+		config.Runtime.ReposCSV = viper.GetString("repos-csv")
+	}
+	if viper.IsSet("repos-file") {
+		config.Runtime.ReposFile = viper.GetString("repos-file")
+	}
+	if viper.IsSet("search") {
+		config.Runtime.Search = viper.GetString("search")
+	}
+	if viper.IsSet("sort") {
+		config.Runtime.Sort = viper.GetString("sort")
+	}
+	if viper.IsSet("limit") {
+		config.Runtime.Limit = viper.GetInt("limit")
+	}
+	if viper.IsSet("shard") {
+		config.Runtime.Shard = viper.GetString("shard")
+	}
+	if viper.IsSet("stream") {
+		config.Runtime.Stream = viper.GetBool("stream")
+	}
 
 	// Set defaults for unset values
 	if config.Runtime.Concurrency == 0 {
 		config.Runtime.Concurrency = 5
 	}
-	if config.Runtime.RateLimit == 0 {
-		config.Runtime.RateLimit = time.Millisecond * 100
-	}
 	if config.Runtime.LogLevel == "" {
 		config.Runtime.LogLevel = "info"
 	}
 	if config.Runtime.Mode == "" {
 		config.Runtime.Mode = "yaml"
 	}
+	if config.Runtime.Source == "" {
+		config.Runtime.Source = "github"
+	}
 	if config.Defaults.Type == "" {
 		config.Defaults.Type = "service"
 	}
@@ -294,6 +768,40 @@ func setDefaults() {
 	if config.Harness.BaseURL == "" {
 		config.Harness.BaseURL = "https://app.harness.io"
 	}
+	if config.Runtime.Interval == 0 {
+		config.Runtime.Interval = 6 * time.Hour
+	}
+	if config.Runtime.LegacyStrategy == "" {
+		config.Runtime.LegacyStrategy = "convert"
+	}
+	if config.Runtime.RetryBackoff == 0 {
+		config.Runtime.RetryBackoff = 15 * time.Minute
+	}
+	if config.Runtime.StateLockTTL == 0 {
+		config.Runtime.StateLockTTL = 10 * time.Minute
+	}
+	if config.Defaults.ExperimentalDays == 0 {
+		config.Defaults.ExperimentalDays = 30
+	}
+	if config.Defaults.ExperimentalTopics == nil {
+		config.Defaults.ExperimentalTopics = []string{"poc", "prototype"}
+	}
+	if config.Runtime.LanguageThreshold == 0 {
+		config.Runtime.LanguageThreshold = 10
+	}
+	if config.Runtime.CatalogPath == "" {
+		config.Runtime.CatalogPath = "catalog-info.yaml"
+	}
+	if config.GitHub.CatalogDir == "" {
+		config.GitHub.CatalogDir = "catalogs"
+	}
+	// Daemon mode relies on state to skip repositories that haven't changed
+	if config.Runtime.Daemon && config.Runtime.StateFile == "" {
+		config.Runtime.StateFile = defaultStateFile
+	}
+	if config.Runtime.ProvisionConnector && config.Harness.ConnectorRef == "" {
+		config.Harness.ConnectorRef = defaultConnectorRef
+	}
 }
 
 func runOnboarder(cmd *cobra.Command, args []string) error {
@@ -307,10 +815,89 @@ func runOnboarder(cmd *cobra.Command, args []string) error {
 		log.Println("Running in dry-run mode - no changes will be made")
 	}
 
+	if err := loadRepoOverrides(); err != nil {
+		return err
+	}
+
+	if err := loadReposFile(); err != nil {
+		return err
+	}
+
+	if err := loadRules(); err != nil {
+		return err
+	}
+
+	if err := loadProjectRoutes(); err != nil {
+		return err
+	}
+
+	if err := loadIdentifierTemplate(); err != nil {
+		return err
+	}
+
+	if err := loadPipelineTemplate(); err != nil {
+		return err
+	}
+
+	if err := loadBadgeTemplate(); err != nil {
+		return err
+	}
+
+	if err := loadOwnersMap(); err != nil {
+		return err
+	}
+
+	if err := loadSystems(); err != nil {
+		return err
+	}
+
+	if err := loadPolicies(ctx); err != nil {
+		return err
+	}
+
+	if err := initClients(); err != nil {
+		return err
+	}
+
+	if config.Runtime.StateFile != "" {
+		var err error
+		stateManager, err = state.NewManager(shardStateFile(config.Runtime.StateFile))
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+		log.Printf("Using state file: %s", stateManager.Path())
+
+		// Overlapping runs would process the same repositories and overwrite each
+		// other's state
+		if err := stateManager.Lock(ctx, config.Runtime.StateLockTTL, config.Runtime.StateLockWait); err != nil {
+			return fmt.Errorf("failed to lock state: %w", err)
+		}
+		defer unlockState(stateManager)
+	}
+
+	if config.Runtime.Daemon {
+		return runDaemon(ctx)
+	}
+
+	return runOnce(ctx)
+}
+
+// unlockState releases the state lock at the end of a run
+func unlockState(manager *state.Manager) {
+	if err := manager.Unlock(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// initClients creates the GitHub and Harness API clients from the loaded config
+func initClients() error {
 	var err error
-	githubClient, err = github.NewClient(config.GitHub)
-	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+	// Harness Code repositories are read through the Harness API
+	if !harnessCodeSource() {
+		githubClient, err = newGitHubClient()
+		if err != nil {
+			return err
+		}
 	}
 
 	harnessClient, err = harness.NewClient(config.Harness)
@@ -318,30 +905,122 @@ func runOnboarder(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create Harness client: %w", err)
 	}
 
+	return nil
+}
+
+func newGitHubClient() (*github.Client, error) {
+	client, err := github.NewClient(config.GitHub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	client.SetCatalogPaths(catalogSearchPaths())
+	return client, nil
+}
+
+// runDaemon reconciles repeatedly until the process is interrupted
+func runDaemon(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Starting daemon mode, reconciling every %s", config.Runtime.Interval)
+
+	ticker := time.NewTicker(config.Runtime.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := runOnce(ctx); err != nil {
+			// Keep running - the next cycle retries anything that failed
+			log.Printf("Reconcile cycle finished with errors: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Daemon shutting down")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce performs a single discovery and onboarding pass
+func runOnce(ctx context.Context) error {
+	var err error
 
 	log.Printf("Starting onboarding process for organization: %s", config.GitHub.Organization)
 	log.Printf("Mode: %s, Concurrency: %d, Dry Run: %t", 
 		config.Runtime.Mode, config.Runtime.Concurrency, config.Runtime.DryRun)
 
+	// Offboarding works from the state file rather than discovery
+	if config.Runtime.Mode == "offboard" {
+		err = processOffboardMode(ctx)
+		if stateManager != nil && !config.Runtime.DryRun {
+			if saveErr := stateManager.Save(); saveErr != nil {
+				log.Printf("Warning: failed to save state: %v", saveErr)
+			}
+		}
+		return err
+	}
+
+	// Groups, domains and systems must exist before components reference them
+	if config.Runtime.Mode != "audit" && config.Runtime.Mode != "migrate" {
+		if config.Runtime.SyncTeams {
+			if err := syncTeams(ctx); err != nil {
+				return fmt.Errorf("failed to sync teams: %w", err)
+			}
+		}
+		if err := syncSystems(ctx); err != nil {
+			return fmt.Errorf("failed to sync systems: %w", err)
+		}
+	}
+
+	if config.Runtime.Mode == "register" && !harnessCodeSource() {
+		if err := provisionConnectors(ctx); err != nil {
+			return err
+		}
+		// A dry run doesn't provision, so the connector may not exist yet
+		if !config.Runtime.DryRun || !config.Runtime.ProvisionConnector {
+			if err := checkConnectors(ctx); err != nil {
+				return fmt.Errorf("connector preflight failed: %w", err)
+			}
+		}
+	}
+
 	// Skip enrichment for register and api modes since we only need basic repo info
-	// Only yaml mode needs full enrichment for PR creation
-	enrich := config.Runtime.Mode == "yaml"
+	// Only yaml mode needs full enrichment for PR creation, and sync mode for current owners
+	enrich := config.Runtime.Mode == "yaml" || config.Runtime.Mode == "sync"
+
+	if config.Runtime.Stream {
+		err = processStreamMode(ctx, enrich)
+		if stateManager != nil && !config.Runtime.DryRun {
+			if saveErr := stateManager.Save(); saveErr != nil {
+				log.Printf("Warning: failed to save state: %v", saveErr)
+			}
+		}
+		return err
+	}
 	
-	// Use optimized discovery when specific repositories are requested
-	var repos []models.Repository
-	if len(config.Runtime.IncludeRepos) > 0 {
-		log.Printf("Using optimized discovery for %d specific repositories", len(config.Runtime.IncludeRepos))
-		repos, err = githubClient.DiscoverRepositoriesWithOptions(ctx, config.GitHub.Organization, enrich, config.Runtime.IncludeRepos)
+	var filteredRepos []models.Repository
+	if config.Runtime.OnlyFailed {
+		filteredRepos, err = discoverFailedRepositories(ctx, enrich)
 	} else {
-		repos, err = githubClient.DiscoverRepositoriesWithEnrichment(ctx, config.GitHub.Organization, enrich)
+		filteredRepos, err = discoverRepositories(ctx, enrich)
 	}
 	if err != nil {
-		return fmt.Errorf("failed to discover repositories: %w", err)
+		return err
 	}
+	filteredRepos = applyShard(filteredRepos)
 
-	// Apply filtering - when using optimized discovery, most filtering is already done
-	filteredRepos := filterRepositories(repos, len(config.Runtime.IncludeRepos) > 0)
-	log.Printf("Found %d repositories, %d after filtering", len(repos), len(filteredRepos))
+	if enrich {
+		indexModules(filteredRepos)
+	}
+
+	// Sync mode compares generated content itself, since metadata edits don't bump PushedAt,
+	// and audit mode must always look at every repository
+	if stateManager != nil && !config.Runtime.OnlyFailed && config.Runtime.Mode != "sync" && config.Runtime.Mode != "audit" {
+		filteredRepos = skipUnchangedRepositories(filteredRepos)
+	}
+	filteredRepos = applyRequiredFiles(ctx, filteredRepos)
+	filteredRepos = sortAndLimit(filteredRepos)
 
 	if config.Runtime.DryRun {
 		log.Printf("Would process %d repositories:", len(filteredRepos))
@@ -351,20 +1030,292 @@ func runOnboarder(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if config.Runtime.Mode == "yaml" || config.Runtime.Mode == "api" || config.Runtime.Mode == "sync" {
+		checkOwners(ctx, filteredRepos)
+	}
+
 	switch config.Runtime.Mode {
 	case "yaml":
-		return processYAMLMode(ctx, filteredRepos)
+		if config.GitHub.CatalogRepo != "" {
+			err = processCatalogRepoYAML(ctx, filteredRepos)
+		} else {
+			err = processYAMLMode(ctx, filteredRepos)
+		}
 	case "api":
-		return processAPIMode(ctx, filteredRepos)
+		err = processAPIMode(ctx, filteredRepos)
 	case "register":
 		log.Printf("DEBUG: About to process %d filtered repositories in register mode", len(filteredRepos))
-		return processRegisterMode(ctx, filteredRepos)
+		if config.GitHub.CatalogRepo != "" {
+			err = processCatalogRepoRegister(ctx, filteredRepos)
+		} else {
+			err = processRegisterMode(ctx, filteredRepos)
+		}
+	case "sync":
+		err = processSyncMode(ctx, filteredRepos)
+	case "migrate":
+		err = processMigrateMode(ctx, filteredRepos)
+	case "audit":
+		// Audit never mutates anything, including the state file
+		return processAuditMode(ctx, filteredRepos)
 	default:
-		return fmt.Errorf("unsupported mode: %s (supported: yaml, api, register)", config.Runtime.Mode)
+		return fmt.Errorf("unsupported mode: %s (supported: yaml, api, register, sync, offboard, audit, migrate)", config.Runtime.Mode)
+	}
+
+	if stateManager != nil {
+		if saveErr := stateManager.Save(); saveErr != nil {
+			log.Printf("Warning: failed to save state: %v", saveErr)
+		}
 	}
+
+	return err
+}
+
+// discoverRepositories lists the organization's repositories and applies the configured filters
+func discoverRepositories(ctx context.Context, enrich bool) ([]models.Repository, error) {
+	if harnessCodeSource() {
+		return discoverHarnessCodeRepositories(ctx)
+	}
+
+	if err := loadTeamRepositories(ctx); err != nil {
+		return nil, err
+	}
+
+	var repos []models.Repository
+	var err error
+
+	// Use optimized discovery when specific repositories are requested by name; globs
+	// and regular expressions need the full list
+	if config.Runtime.Search != "" {
+		repos, err = githubClient.SearchRepositories(ctx, config.GitHub.Organization, config.Runtime.Search, enrich)
+	} else if len(config.Runtime.IncludeRepos) > 0 && !hasRepoPatterns(config.Runtime.IncludeRepos) {
+		log.Printf("Using optimized discovery for %d specific repositories", len(config.Runtime.IncludeRepos))
+		repos, err = githubClient.DiscoverRepositoriesWithOptions(ctx, config.GitHub.Organization, enrich, config.Runtime.IncludeRepos)
+	} else {
+		repos, err = githubClient.DiscoverRepositoriesWithEnrichment(ctx, config.GitHub.Organization, enrich)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover repositories: %w", err)
+	}
+
+	filteredRepos := filterRepositories(repos)
+	applyBaseBranches(filteredRepos)
+	log.Printf("Found %d repositories, %d after filtering", len(repos), len(filteredRepos))
+
+	return filteredRepos, nil
+}
+
+// discoverFailedRepositories fetches only the repositories whose last run in the
+// current mode failed and whose retry backoff has elapsed
+func discoverFailedRepositories(ctx context.Context, enrich bool) ([]models.Repository, error) {
+	due, waiting := stateManager.DueForRetry(config.Runtime.Mode, config.Runtime.RetryBackoff, time.Now())
+	log.Printf("%d failed repositories due for retry, %d still in backoff", len(due), waiting)
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(due))
+	for _, s := range due {
+		name := s.Repository
+		if idx := strings.LastIndex(name, "/"); idx >= 0 {
+			name = name[idx+1:]
+		}
+		if !repoIncluded(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if err := loadTeamRepositories(ctx); err != nil {
+		return nil, err
+	}
+
+	repos, err := githubClient.DiscoverRepositoriesWithOptions(ctx, config.GitHub.Organization, enrich, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover repositories: %w", err)
+	}
+
+	filteredRepos := filterRepositories(repos)
+	applyBaseBranches(filteredRepos)
+	return filteredRepos, nil
+}
+
+// skipUnchangedRepositories drops repositories that were already processed in the
+// current mode and haven't been pushed to since
+func skipUnchangedRepositories(repos []models.Repository) []models.Repository {
+	var pending []models.Repository
+	for _, repo := range repos {
+		if stateManager.IsUnchanged(repo, config.Runtime.Mode) {
+			log.Printf("DEBUG: Skipping unchanged repository %s", repo.FullName)
+			continue
+		}
+		pending = append(pending, repo)
+	}
+
+	log.Printf("%d repositories changed since last run, %d unchanged", len(pending), len(repos)-len(pending))
+	return pending
+}
+
+// throttle waits --rate-limit before processing a repository, if set. GitHub calls
+// don't need it: the client paces them from the rate limit headers GitHub returns.
+func throttle(ctx context.Context) {
+	if config.Runtime.RateLimit <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(config.Runtime.RateLimit):
+	}
+}
+
+// maxRetries is how often a repository blocked by GitHub's secondary rate limit is
+// processed again before it's recorded as failed
+const maxRetries = 3
+
+// secondaryLimitWait is the wait GitHub recommends when a secondary rate limit
+// response has no Retry-After
+const secondaryLimitWait = time.Minute
+
+// retryOnSecondaryLimit processes a repository again once GitHub's secondary rate
+// limit has passed, instead of recording the block as a hard failure. The wait keeps
+// the worker's concurrency slot, so the run slows down rather than sending the next
+// burst. A PR that couldn't be opened has its timestamped branch deleted, so the retry
+// starts over without leaving it behind.
+func retryOnSecondaryLimit(ctx context.Context, repo models.Repository, process func(context.Context, models.Repository) errors.ProcessingResult) errors.ProcessingResult {
+	result := process(ctx, repo)
+	for attempt := 1; attempt <= maxRetries && secondaryLimited(result); attempt++ {
+		wait := result.Error.RetryAfter
+		if wait <= 0 {
+			wait = secondaryLimitWait
+		}
+		log.Printf("GitHub secondary rate limit blocked %s, retrying it in %s (%d of %d)", repo.FullName, wait, attempt, maxRetries)
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(wait):
+		}
+		result = process(ctx, repo)
+	}
+	return result
+}
+
+func secondaryLimited(result errors.ProcessingResult) bool {
+	return result.Error != nil && result.Error.Type == errors.ErrorTypeSecondaryRateLimit
+}
+
+// recordState stores a processing result in the state file, if one is configured
+func recordState(repo models.Repository, result errors.ProcessingResult) {
+	if stateManager == nil {
+		return
+	}
+	identifier := result.Identifier
+	if identifier == "" {
+		identifier = repoIdentifier(repo)
+	}
+	stateManager.Record(repo, config.Runtime.Mode, identifier, result)
 }
 
 func validateConfig() error {
+	if err := validateSource(); err != nil {
+		return err
+	}
+
+	// Harness Code repositories don't need GitHub credentials
+	if !harnessCodeSource() {
+		if err := validateGitHubConfig(); err != nil {
+			return err
+		}
+	}
+	if err := validateHarnessConfig(); err != nil {
+		return err
+	}
+	
+	if config.Defaults.Owner == "" {
+		return fmt.Errorf("default owner is required")
+	}
+	if err := validateKind(config.Defaults.Kind); err != nil {
+		return err
+	}
+	if err := validateFilters(); err != nil {
+		return err
+	}
+
+	switch config.Runtime.LegacyStrategy {
+	case "convert", "pr", "import":
+	default:
+		return fmt.Errorf("unsupported legacy strategy: %s (supported: convert, pr, import)", config.Runtime.LegacyStrategy)
+	}
+
+	if (config.GitHub.CommitAuthorName == "") != (config.GitHub.CommitAuthorEmail == "") {
+		return fmt.Errorf("--commit-author-name and --commit-author-email must be set together")
+	}
+
+	if config.Runtime.MergeExisting && config.Runtime.PatchExisting {
+		return fmt.Errorf("--merge-existing and --patch-existing cannot be used together")
+	}
+
+	if p := config.Runtime.CatalogPath; path.IsAbs(p) || path.Clean(p) != p || strings.HasPrefix(p, "../") || (path.Ext(p) != ".yaml" && path.Ext(p) != ".yml") {
+		return fmt.Errorf("--catalog-path must be a relative .yaml or .yml path inside the repository, got %q", p)
+	}
+
+	if config.GitHub.CatalogRepo != "" {
+		if strings.Count(config.GitHub.CatalogRepo, "/") != 1 {
+			return fmt.Errorf("--catalog-repo must be owner/name, got %q", config.GitHub.CatalogRepo)
+		}
+		if config.Runtime.CommitDirect {
+			return fmt.Errorf("--catalog-repo and --commit-direct cannot be used together")
+		}
+	}
+
+	if config.Runtime.PipelineTemplate != "" && !config.Runtime.PipelineStarter {
+		return fmt.Errorf("--pipeline-template requires --pipeline-starter")
+	}
+	if config.Runtime.APIBatchSize < 0 {
+		return fmt.Errorf("--api-batch-size must not be negative")
+	}
+	if config.Runtime.RegisterBatchSize < 0 {
+		return fmt.Errorf("--register-batch-size must not be negative")
+	}
+	if config.Runtime.CodeSearch && config.Runtime.Mode != "register" {
+		return fmt.Errorf("--code-search only applies to register mode")
+	}
+	if config.Runtime.ReadmeBadgeURL != "" && !config.Runtime.ReadmeBadge {
+		return fmt.Errorf("--readme-badge-url requires --readme-badge")
+	}
+
+	if config.Runtime.OnboardedTopic != "" && !topicPattern.MatchString(config.Runtime.OnboardedTopic) {
+		return fmt.Errorf("--onboarded-topic %q is not a valid GitHub topic (lowercase letters, numbers and hyphens, at most 50 characters)", config.Runtime.OnboardedTopic)
+	}
+
+	switch config.Runtime.BatchBy {
+	case "", "owner":
+	default:
+		return fmt.Errorf("unsupported batch grouping: %s (supported: owner)", config.Runtime.BatchBy)
+	}
+	if (config.Runtime.BatchBy != "" || config.Runtime.BatchSize > 0) && config.GitHub.CatalogRepo == "" {
+		return fmt.Errorf("--batch-by and --batch-size require --catalog-repo, since one PR can only change one repository")
+	}
+
+	if err := validateStream(); err != nil {
+		return err
+	}
+
+	if config.Runtime.OnlyFailed {
+		if config.Runtime.StateFile == "" {
+			return fmt.Errorf("--only-failed requires a state file")
+		}
+		if config.Runtime.Mode == "offboard" || config.Runtime.Mode == "audit" {
+			return fmt.Errorf("--only-failed is not supported in %s mode", config.Runtime.Mode)
+		}
+	}
+	
+	return nil
+}
+
+// validateGitHubConfig checks the GitHub organization and app credentials
+func validateGitHubConfig() error {
 	if config.GitHub.Organization == "" {
 		return fmt.Errorf("GitHub organization is required")
 	}
@@ -377,80 +1328,54 @@ func validateConfig() error {
 	if config.GitHub.InstallID == 0 {
 		return fmt.Errorf("GitHub installation ID is required")
 	}
-	
+	return nil
+}
+
+// validateConnectionConfig checks only the settings needed to talk to GitHub and Harness
+func validateConnectionConfig() error {
+	if err := validateGitHubConfig(); err != nil {
+		return err
+	}
+	return validateHarnessConfig()
+}
+
+// validateHarnessConfig checks the Harness credentials and scope
+func validateHarnessConfig() error {
 	if config.Harness.APIKey == "" {
 		return fmt.Errorf("Harness API key is required")
 	}
 	if config.Harness.AccountID == "" {
 		return fmt.Errorf("Harness account ID is required")
 	}
-	if config.Harness.OrgID == "" {
-		return fmt.Errorf("Harness organization ID is required")
+	switch config.Harness.Scope {
+	case "", "project", "org", "account":
+	default:
+		return fmt.Errorf("invalid Harness scope %q: must be project, org or account", config.Harness.Scope)
 	}
-	if config.Harness.ProjectID == "" {
-		return fmt.Errorf("Harness project ID is required")
+	if config.Harness.OrgID == "" && config.Harness.Scope != "account" {
+		return fmt.Errorf("Harness organization ID is required")
 	}
-	
-	if config.Defaults.Owner == "" {
-		return fmt.Errorf("default owner is required")
+	if config.Harness.ProjectID == "" && (config.Harness.Scope == "" || config.Harness.Scope == "project") {
+		return fmt.Errorf("Harness project ID is required")
 	}
 	
 	return nil
 }
 
-func filterRepositories(repos []models.Repository, optimizedDiscovery bool) []models.Repository {
+// filterRepositories applies the include/exclude lists, the archived check and the
+// attribute filters. Repositories fetched by name were already limited to the include
+// list, but are checked the same way.
+func filterRepositories(repos []models.Repository) []models.Repository {
 	var filtered []models.Repository
-	
-	// If we used optimized discovery, we already have the specific repos we want
-	// Only need to check for archived repos and exclude list
-	if optimizedDiscovery {
-		excludeMap := make(map[string]bool)
-		for _, repo := range config.Runtime.ExcludeRepos {
-			excludeMap[repo] = true
-		}
-		
-		for _, repo := range repos {
-			if repo.Archived {
-				continue
-			}
-			
-			if excludeMap[repo.Name] {
-				continue
-			}
-			
-			filtered = append(filtered, repo)
-		}
-		
-		return filtered
-	}
-	
-	// Original filtering logic for full discovery
-	includeMap := make(map[string]bool)
-	for _, repo := range config.Runtime.IncludeRepos {
-		includeMap[repo] = true
-	}
-	
-	excludeMap := make(map[string]bool)
-	for _, repo := range config.Runtime.ExcludeRepos {
-		excludeMap[repo] = true
-	}
-	
 	for _, repo := range repos {
-		if repo.Archived {
+		if repo.Archived && !config.Runtime.IncludeArchived {
 			continue
 		}
-		
-		if len(includeMap) > 0 && !includeMap[repo.Name] {
-			continue
-		}
-		
-		if excludeMap[repo.Name] {
+		if !repoIncluded(repo.Name) || !repoSelected(repo) {
 			continue
 		}
-		
 		filtered = append(filtered, repo)
 	}
-	
 	return filtered
 }
 
@@ -465,14 +1390,16 @@ func processYAMLMode(ctx context.Context, repos []models.Repository) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 			
-			time.Sleep(config.Runtime.RateLimit)
-			result := processRepositoryYAMLWithResult(ctx, r)
+			throttle(ctx)
+			result := retryOnSecondaryLimit(ctx, r, processRepositoryYAMLWithResult)
+			recordState(r, result)
+			markOnboarded(ctx, r, result)
 			results <- result
 		}(repo)
 	}
 	
 	// Collect results and build summary
-	summary := errors.NewErrorSummary()
+	summary := newRunSummary()
 	for i := 0; i < len(repos); i++ {
 		result := <-results
 		summary.AddResult(result)
@@ -480,6 +1407,7 @@ func processYAMLMode(ctx context.Context, repos []models.Repository) error {
 	
 	// Print detailed summary
 	summary.PrintSummary()
+	writeRunReport(summary)
 	
 	if summary.Total > 0 {
 		return fmt.Errorf("encountered %d errors during YAML processing", summary.Total)
@@ -489,6 +1417,9 @@ func processYAMLMode(ctx context.Context, repos []models.Repository) error {
 }
 
 func processAPIMode(ctx context.Context, repos []models.Repository) error {
+	if config.Runtime.APIBatchSize > 0 {
+		return processAPIModeBatched(ctx, repos)
+	}
 	log.Printf("Processing %d repositories in API mode", len(repos))
 	
 	semaphore := make(chan struct{}, config.Runtime.Concurrency)
@@ -499,14 +1430,16 @@ func processAPIMode(ctx context.Context, repos []models.Repository) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 			
-			time.Sleep(config.Runtime.RateLimit)
-			result := processRepositoryAPIWithResult(ctx, r)
+			throttle(ctx)
+			result := withScores(ctx, r, retryOnSecondaryLimit(ctx, r, processRepositoryAPIWithResult))
+			recordState(r, result)
+			markOnboarded(ctx, r, result)
 			results <- result
 		}(repo)
 	}
 	
 	// Collect results and build summary
-	summary := errors.NewErrorSummary()
+	summary := newRunSummary()
 	for i := 0; i < len(repos); i++ {
 		result := <-results
 		summary.AddResult(result)
@@ -514,6 +1447,7 @@ func processAPIMode(ctx context.Context, repos []models.Repository) error {
 	
 	// Print detailed summary
 	summary.PrintSummary()
+	writeRunReport(summary)
 	
 	if summary.Total > 0 {
 		return fmt.Errorf("encountered %d errors during API processing", summary.Total)
@@ -527,6 +1461,49 @@ func processRepositoryYAML(ctx context.Context, repo models.Repository) error {
 	return result.Error
 }
 
+// refreshOnboardingPR pushes freshly generated catalog content to an open onboarding
+// PR, built the same way as the content of a new PR
+func refreshOnboardingPR(ctx context.Context, repo models.Repository, pr *gogithub.PullRequest) errors.ProcessingResult {
+	change, result := prepareCatalogChange(ctx, repo)
+	if result != nil {
+		return *result
+	}
+
+	updated, err := githubClient.UpdatePR(ctx, repo, pr, change.path, change.content, onboardingFiles(repo))
+	if err != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    fmt.Sprintf("Failed to refresh PR #%d", pr.GetNumber()),
+			Action:     "failed",
+		}
+	}
+	if !updated {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    fmt.Sprintf("Open PR #%d is already up to date", pr.GetNumber()),
+			Skipped:    true,
+			Action:     "skipped",
+			URL:        pr.GetHTMLURL(),
+		}
+	}
+
+	// A patched file only gained missing fields, so it can't serve as a merge base
+	if stateManager != nil && !(change.hasCatalog && config.Runtime.PatchExisting) {
+		stateManager.SetGenerated(repo.FullName, change.generated)
+	}
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Message:    fmt.Sprintf("Refreshed open PR #%d", pr.GetNumber()),
+		Action:     "updated",
+		Identifier: repoIdentifier(repo),
+		URL:        pr.GetHTMLURL(),
+	}
+}
+
 func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository) errors.ProcessingResult {
 	log.Printf("Processing repository %s in YAML mode", repo.FullName)
 	
@@ -536,6 +1513,9 @@ func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository
 	if err != nil {
 		log.Printf("DEBUG: Error checking for existing PRs in %s: %v", repo.FullName, err)
 	}
+	if existingPR != nil && config.Runtime.UpdateOpenPRs {
+		return refreshOnboardingPR(ctx, repo, existingPR)
+	}
 	if existingPR != nil {
 		log.Printf("Repository %s already has an open Harness onboarding PR #%d", repo.FullName, existingPR.GetNumber())
 		return errors.ProcessingResult{
@@ -548,31 +1528,106 @@ func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository
 		}
 	}
 	
+	change, result := prepareCatalogChange(ctx, repo)
+	if result != nil {
+		return *result
+	}
+	catalogPath, yamlContent, generated, hasCatalog := change.path, change.content, change.generated, change.hasCatalog
+	
+	if err := githubClient.PreflightPR(ctx, repo, config.Runtime.CommitDirect); err != nil {
+		return blockedResult(ctx, repo, catalogPath, yamlContent, errors.CategorizeError(err, repo.FullName), "Preflight check failed")
+	}
+	
+	if config.Runtime.CommitDirect {
+		return commitCatalogDirect(ctx, repo, catalogPath, yamlContent, generated, hasCatalog)
+	}
+	
+	prURL, err := githubClient.CreatePR(ctx, repo, catalogPath, yamlContent, onboardingFiles(repo))
+	if err != nil {
+		procErr := errors.CategorizeError(err, repo.FullName)
+		
+		// Handle specific PR-related scenarios
+		if procErr.Type == errors.ErrorTypePRExists {
+			return errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    false,
+				Error:      procErr,
+				Message:    "PR already exists",
+				Skipped:    true,
+				Action:     "skipped",
+			}
+		}
+		
+		return blockedResult(ctx, repo, catalogPath, yamlContent, procErr, "PR creation failed")
+	}
+	
+	// A patched file only gained missing fields, so it can't serve as a merge base
+	if stateManager != nil && !(hasCatalog && config.Runtime.PatchExisting) {
+		stateManager.SetGenerated(repo.FullName, generated)
+	}
+	
+	if prURL == "" {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Error:      nil,
+			Message:    "Catalog file already up to date",
+			Skipped:    true,
+			Action:     "skipped",
+			Identifier: repoIdentifier(repo),
+		}
+	}
+	
+	log.Printf("Successfully created PR for repository: %s", repo.FullName)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Error:      nil,
+		Message:    "PR created successfully",
+		Action:     "created",
+		Identifier: repoIdentifier(repo),
+		URL:        prURL,
+	}
+}
+
+// catalogChange is the catalog content a yaml mode run proposes for a repository
+type catalogChange struct {
+	path       string
+	content    string // committed content, merged into or patching an existing file
+	generated  string // generated content, the merge base for later runs
+	hasCatalog bool
+}
+
+// prepareCatalogChange generates the catalog content for a repository, folds it into
+// an existing catalog file with --merge-existing or --patch-existing, and checks it
+// against policies and Harness. A non-nil result ends processing of the repository.
+func prepareCatalogChange(ctx context.Context, repo models.Repository) (catalogChange, *errors.ProcessingResult) {
 	// Check if catalog-info.yaml already exists in the repository
 	log.Printf("DEBUG: Checking for existing catalog-info.yaml in %s", repo.FullName)
-	existingCatalog, err := githubClient.GetCatalogInfo(ctx, repo)
+	existingPath, existingCatalog, err := getCatalogInfoPathAndContent(ctx, repo)
 	if err != nil {
 		log.Printf("DEBUG: No existing catalog file found in %s: %v", repo.FullName, err)
 	}
-	if err == nil && existingCatalog != "" {
+	hasCatalog := err == nil && existingCatalog != ""
+	if hasCatalog && !config.Runtime.MergeExisting && !config.Runtime.PatchExisting {
 		log.Printf("Repository %s already has catalog-info.yaml file", repo.FullName)
 		
 		// Check if the component is already registered in Harness IDP
 		catalogInfo := buildCatalogInfo(repo)
-		component, err := harnessClient.GetComponent(ctx, catalogInfo.Identifier)
+		component, err := harnessFor(repo).GetEntity(ctx, repoKind(repo), catalogInfo.Identifier)
 		if err == nil && component != nil {
 			log.Printf("Component %s already exists in Harness IDP and has catalog-info.yaml file", catalogInfo.Identifier)
-			return errors.ProcessingResult{
+			return catalogChange{}, &errors.ProcessingResult{
 				Repository: repo.FullName,
 				Success:    true,
 				Error:      nil,
-				Message:    "Already onboarded (file exists in repo, component exists in IDP)",
+				Message:    alreadyOnboardedMessage,
 				Skipped:    true,
 				Action:     "skipped",
 			}
 		} else {
 			log.Printf("Catalog file exists but component not found in IDP - may need registration")
-			return errors.ProcessingResult{
+			return catalogChange{}, &errors.ProcessingResult{
 				Repository: repo.FullName,
 				Success:    true,
 				Error:      nil,
@@ -584,8 +1639,7 @@ func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository
 	}
 	
 	// Generate the catalog info and YAML content
-	catalogInfo := buildCatalogInfo(repo)
-	yamlContent, err := yaml.Marshal(catalogInfo)
+	yamlContent, err := generateCatalogYAML(repo)
 	if err != nil {
 		procErr := &errors.ProcessingError{
 			Category:     errors.ErrorCategoryValidation,
@@ -596,7 +1650,7 @@ func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository
 			Recoverable:  false,
 			UserFriendly: fmt.Sprintf("Failed to generate catalog-info.yaml for '%s'. This might be due to invalid repository metadata.", repo.FullName),
 		}
-		return errors.ProcessingResult{
+		return catalogChange{}, &errors.ProcessingResult{
 			Repository: repo.FullName,
 			Success:    false,
 			Error:      procErr,
@@ -605,38 +1659,171 @@ func processRepositoryYAMLWithResult(ctx context.Context, repo models.Repository
 		}
 	}
 	
-	err = githubClient.CreatePR(ctx, repo, string(yamlContent))
-	if err != nil {
-		procErr := errors.CategorizeError(err, repo.FullName)
-		
-		// Handle specific PR-related scenarios
-		if procErr.Type == errors.ErrorTypePRExists {
-			return errors.ProcessingResult{
+	// Fold the generated changes into the user's file rather than replacing it
+	generated := yamlContent
+	catalogPath := config.Runtime.CatalogPath
+	if hasCatalog {
+		if config.Runtime.PatchExisting {
+			yamlContent, err = catalog.Patch(existingCatalog, generated)
+		} else {
+			var base string
+			if stateManager != nil {
+				if s, ok := stateManager.Get(repo.FullName); ok {
+					base = s.Generated
+				}
+			}
+			yamlContent, err = catalog.Merge(base, existingCatalog, generated)
+		}
+		if err != nil {
+			return catalogChange{}, &errors.ProcessingResult{
 				Repository: repo.FullName,
 				Success:    false,
-				Error:      procErr,
-				Message:    "PR already exists",
-				Skipped:    true,
-				Action:     "skipped",
+				Error: &errors.ProcessingError{
+					Category:     errors.ErrorCategoryValidation,
+					Type:         errors.ErrorTypeCatalogFileInvalid,
+					Message:      fmt.Sprintf("failed to merge into %s: %s", existingPath, err.Error()),
+					Repository:   repo.FullName,
+					Cause:        err,
+					Recoverable:  false,
+					UserFriendly: fmt.Sprintf("Could not update %s in '%s'; fix the YAML or run without --merge-existing/--patch-existing.", existingPath, repo.FullName),
+				},
+				Message: "Merge failed",
+				Action:  "failed",
 			}
 		}
-		
+		catalogPath = existingPath
+	}
+	
+	if procErr := checkPolicies(ctx, repo, yamlContent); procErr != nil {
+		result := policyResult(repo, procErr)
+		return catalogChange{}, &result
+	}
+	
+	if procErr := validateWithHarness(ctx, repo, yamlContent); procErr != nil {
+		return catalogChange{}, &errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      procErr,
+			Message:    "Harness rejected the generated catalog file",
+			Action:     "failed",
+		}
+	}
+
+	return catalogChange{path: catalogPath, content: yamlContent, generated: generated, hasCatalog: hasCatalog}, nil
+}
+
+// validateWithHarness dry-runs the catalog content, as it would be registered, against
+// the Harness entities API so files Harness would reject never reach a PR. Only a
+// rejection is returned; when Harness can't be reached the check is skipped.
+func validateWithHarness(ctx context.Context, repo models.Repository, yamlContent string) *errors.ProcessingError {
+	if config.Runtime.SkipHarnessValidation {
+		return nil
+	}
+
+	sanitized, err := catalog.Sanitize(yamlContent, convertOptions(repo))
+	if err != nil {
+		sanitized = yamlContent
+	}
+
+	err = harnessFor(repo).ValidateEntityYAML(ctx, sanitized)
+	if err == nil {
+		return nil
+	}
+	procErr := errors.CategorizeError(err, repo.FullName)
+	if procErr.Type == errors.ErrorTypeEntityValidationFailed {
+		log.Printf("Harness rejected the catalog file generated for %s: %s", repo.FullName, procErr.Message)
+		return procErr
+	}
+	log.Printf("Warning: could not validate the catalog file for %s with Harness: %v", repo.FullName, err)
+	return nil
+}
+
+// commitCatalogDirect commits generated catalog content to the default branch for
+// --commit-direct, recording the result like a created PR
+func commitCatalogDirect(ctx context.Context, repo models.Repository, catalogPath, yamlContent, generated string, hasCatalog bool) errors.ProcessingResult {
+	commitURL, err := githubClient.CommitDirect(ctx, repo, catalogPath, yamlContent, onboardingFiles(repo))
+	if err != nil {
+		return blockedResult(ctx, repo, catalogPath, yamlContent, errors.CategorizeError(err, repo.FullName), "Direct commit failed")
+	}
+
+	if stateManager != nil && !(hasCatalog && config.Runtime.PatchExisting) {
+		stateManager.SetGenerated(repo.FullName, generated)
+	}
+
+	if commitURL == "" {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    "Catalog file already up to date",
+			Skipped:    true,
+			Action:     "skipped",
+			Identifier: repoIdentifier(repo),
+		}
+	}
+
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Message:    fmt.Sprintf("Committed %s to %s", catalogPath, repo.DefaultBranch),
+		Action:     "committed",
+		Identifier: repoIdentifier(repo),
+		URL:        commitURL,
+	}
+}
+
+// prBlocked reports whether a PR or direct commit failed because the app isn't allowed
+// to make it, rather than a transient or content problem
+func prBlocked(procErr *errors.ProcessingError) bool {
+	return procErr.Type == errors.ErrorTypeForbidden || procErr.Type == errors.ErrorTypeBranchProtected || procErr.Type == errors.ErrorTypePRBlocked
+}
+
+// blockedResult reports a failed PR or direct commit. Repositories the app isn't
+// allowed to change get the "blocked" action, or an onboarding issue with
+// --issue-fallback; anything else is an ordinary failure.
+func blockedResult(ctx context.Context, repo models.Repository, catalogPath, yamlContent string, procErr *errors.ProcessingError, message string) errors.ProcessingResult {
+	if !prBlocked(procErr) {
 		return errors.ProcessingResult{
 			Repository: repo.FullName,
 			Success:    false,
 			Error:      procErr,
-			Message:    "PR creation failed",
+			Message:    message,
+			Action:     "failed",
+		}
+	}
+	if config.Runtime.IssueFallback {
+		return openOnboardingIssue(ctx, repo, catalogPath, yamlContent, procErr)
+	}
+	log.Printf("Repository %s is blocked: %s", repo.FullName, procErr.Message)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    false,
+		Error:      procErr,
+		Message:    "Blocked: " + procErr.Message,
+		Action:     "blocked",
+	}
+}
+
+// openOnboardingIssue falls back to an issue carrying the generated catalog file so the
+// repository's owners can commit it themselves
+func openOnboardingIssue(ctx context.Context, repo models.Repository, catalogPath, yamlContent string, cause *errors.ProcessingError) errors.ProcessingResult {
+	log.Printf("Could not open a PR in %s (%s), opening an issue instead", repo.FullName, cause.Message)
+	issueURL, err := githubClient.CreateOnboardingIssue(ctx, repo, catalogPath, yamlContent, cause.Message)
+	if err != nil {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      errors.CategorizeError(err, repo.FullName),
+			Message:    "PR blocked and issue creation failed",
 			Action:     "failed",
 		}
 	}
-	
-	log.Printf("Successfully created PR for repository: %s", repo.FullName)
 	return errors.ProcessingResult{
 		Repository: repo.FullName,
 		Success:    true,
-		Error:      nil,
-		Message:    "PR created successfully",
-		Action:     "created",
+		Message:    "PR blocked, opened issue with catalog file",
+		Action:     "issue",
+		Identifier: repoIdentifier(repo),
+		URL:        issueURL,
 	}
 }
 
@@ -648,10 +1835,29 @@ func processRepositoryAPI(ctx context.Context, repo models.Repository) error {
 func processRepositoryAPIWithResult(ctx context.Context, repo models.Repository) errors.ProcessingResult {
 	log.Printf("Processing repository %s in API mode", repo.FullName)
 	
-	component := buildHarnessComponent(repo)
-	
-	err := harnessClient.CreateComponent(ctx, component)
-	if err != nil {
+	component := prepareAPIComponent(ctx, &repo)
+	if procErr := checkComponentPolicies(ctx, repo); procErr != nil {
+		return policyResult(repo, procErr)
+	}
+	return apiResult(ctx, repo, component, harnessFor(repo).CreateComponent(ctx, component))
+}
+
+// prepareAPIComponent builds the component for a repository in API mode. API mode skips
+// enrichment, so this looks for API definitions and IaC files first, recording them on repo.
+func prepareAPIComponent(ctx context.Context, repo *models.Repository) models.HarnessComponent {
+	if err := githubClient.DetectRootSignals(ctx, repo); err != nil {
+		log.Printf("Warning: failed to inspect root of %s: %v", repo.FullName, err)
+	}
+	return buildHarnessComponent(*repo)
+}
+
+// apiResult turns the outcome of creating a repository's component into its result,
+// creating the API entity for its OpenAPI definition once the component exists. An
+// unchanged component still gets its API entity, in case an earlier run failed to
+// create it, and the fingerprint is only stored once both exist.
+func apiResult(ctx context.Context, repo models.Repository, component models.HarnessComponent, err error) errors.ProcessingResult {
+	unchanged := err == harness.ErrUnchanged
+	if err != nil && !unchanged {
 		procErr := errors.CategorizeError(err, repo.FullName)
 		
 		// Handle specific entity-related scenarios
@@ -675,6 +1881,35 @@ func processRepositoryAPIWithResult(ctx context.Context, repo models.Repository)
 		}
 	}
 	
+	if apiEntity := buildAPIEntity(repo); apiEntity != nil {
+		if err := harnessFor(repo).CreateEntity(ctx, *apiEntity); err != nil {
+			procErr := errors.CategorizeError(err, repo.FullName)
+			if procErr.Type != errors.ErrorTypeEntityExists {
+				return errors.ProcessingResult{
+					Repository: repo.FullName,
+					Success:    false,
+					Error:      procErr,
+					Message:    "API entity creation failed",
+					Action:     "failed",
+				}
+			}
+		}
+	}
+	
+	if stateManager != nil {
+		stateManager.SetFingerprint(repo.FullName, componentFingerprint(component))
+	}
+	
+	if unchanged {
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    "Component unchanged",
+			Skipped:    true,
+			Action:     "unchanged",
+		}
+	}
+	
 	log.Printf("Successfully created component for repository: %s", repo.FullName)
 	return errors.ProcessingResult{
 		Repository: repo.FullName,
@@ -686,6 +1921,10 @@ func processRepositoryAPIWithResult(ctx context.Context, repo models.Repository)
 }
 
 func processRegisterMode(ctx context.Context, repos []models.Repository) error {
+	indexCatalogFiles(ctx)
+	if config.Runtime.RegisterBatchSize > 0 {
+		return processRegisterModeBatched(ctx, repos)
+	}
 	log.Printf("Processing %d repositories in REGISTER mode", len(repos))
 	
 	semaphore := make(chan struct{}, config.Runtime.Concurrency)
@@ -696,14 +1935,16 @@ func processRegisterMode(ctx context.Context, repos []models.Repository) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 			
-			time.Sleep(config.Runtime.RateLimit)
-			result := processRepositoryRegisterWithResult(ctx, r)
+			throttle(ctx)
+			result := withScores(ctx, r, retryOnSecondaryLimit(ctx, r, processRepositoryRegisterWithResult))
+			recordState(r, result)
+			markOnboarded(ctx, r, result)
 			results <- result
 		}(repo)
 	}
 	
 	// Collect results and build summary
-	summary := errors.NewErrorSummary()
+	summary := newRunSummary()
 	for i := 0; i < len(repos); i++ {
 		result := <-results
 		summary.AddResult(result)
@@ -711,6 +1952,7 @@ func processRegisterMode(ctx context.Context, repos []models.Repository) error {
 	
 	// Print detailed summary
 	summary.PrintSummary()
+	writeRunReport(summary)
 	
 	if summary.Total > 0 {
 		return fmt.Errorf("encountered %d errors during REGISTER processing", summary.Total)
@@ -727,12 +1969,26 @@ func processRepositoryRegister(ctx context.Context, repo models.Repository) erro
 func processRepositoryRegisterWithResult(ctx context.Context, repo models.Repository) errors.ProcessingResult {
 	log.Printf("Processing repository %s in REGISTER mode", repo.FullName)
 	
+	location, result := prepareRegistration(ctx, repo)
+	if result != nil {
+		return *result
+	}
+	
+	// Register the repository for entity import with Harness IDP
+	err := harnessFor(repo).ImportCatalogLocation(ctx, location)
+	return registerResult(repo, location, err)
+}
+
+// prepareRegistration finds a repository's catalog file and sanitizes it for import.
+// Repositories without one, or with a legacy file handled another way, get their
+// result instead.
+func prepareRegistration(ctx context.Context, repo models.Repository) (harness.CatalogLocation, *errors.ProcessingResult) {
 	// Check if catalog-info.yaml exists in the repository and get the path and content
 	catalogPath, catalogContent, err := getCatalogInfoPathAndContent(ctx, repo)
 	if err != nil {
 		// Missing catalog files are expected - skip gracefully
 		log.Printf("Skipping %s: %v", repo.FullName, err)
-		return errors.ProcessingResult{
+		return harness.CatalogLocation{}, &errors.ProcessingResult{
 			Repository: repo.FullName,
 			Success:    true,
 			Error:      nil,
@@ -742,13 +1998,31 @@ func processRepositoryRegisterWithResult(ctx context.Context, repo models.Reposi
 		}
 	}
 	
+	if config.Runtime.LegacyStrategy != "import" && catalog.IsLegacy(catalogContent) {
+		result := registerLegacyCatalog(ctx, repo, catalogPath, catalogContent)
+		return harness.CatalogLocation{}, &result
+	}
+	
 	log.Printf("Registering repository for entity import: %s (branch: %s, file: %s)", repo.FullName, repo.DefaultBranch, catalogPath)
 	
-	// Sanitize the catalog content to ensure identifiers don't have hyphens
-	sanitizedContent := sanitizeYAMLIdentifiers(catalogContent)
+	// Make identifiers valid and fill in the scope without losing comments or documents
+	sanitizedContent, err := catalog.Sanitize(catalogContent, convertOptions(repo))
+	if err != nil {
+		log.Printf("Warning: could not sanitize %s in %s, registering as-is: %v", catalogPath, repo.FullName, err)
+		sanitizedContent = catalogContent
+	}
 	
-	// Register the repository for entity import with Harness IDP
-	err = harnessClient.RegisterCatalogLocation(ctx, repo.FullName, repo.DefaultBranch, catalogPath, sanitizedContent)
+	return harness.CatalogLocation{
+		Repository:  repo.FullName,
+		Branch:      repo.DefaultBranch,
+		Path:        catalogPath,
+		Content:     sanitizedContent,
+		HarnessCode: repo.Source == harness.SourceHarnessCode,
+	}, nil
+}
+
+// registerResult turns the outcome of importing a repository's catalog file into its result
+func registerResult(repo models.Repository, location harness.CatalogLocation, err error) errors.ProcessingResult {
 	if err != nil {
 		procErr := errors.CategorizeError(err, repo.FullName)
 		
@@ -774,23 +2048,40 @@ func processRepositoryRegisterWithResult(ctx context.Context, repo models.Reposi
 	}
 	
 	log.Printf("Successfully registered entity for repository: %s", repo.FullName)
+	// Registration imports hyphenated identifiers with underscores
+	identifier, _ := harness.ExtractEntityIdentifier(location.Content)
 	return errors.ProcessingResult{
 		Repository: repo.FullName,
 		Success:    true,
 		Error:      nil,
 		Message:    "Entity registered successfully",
 		Action:     "registered",
+		Identifier: strings.ReplaceAll(identifier, "-", "_"),
+	}
+}
+
+// defaultCatalogPaths are the locations searched for an existing catalog file
+var defaultCatalogPaths = []string{
+	"catalog-info.yaml",
+	"catalog-info.yml",
+	".harness/catalog-info.yaml",
+	".harness/catalog-info.yml",
+}
+
+// catalogSearchPaths returns the configured catalog path followed by the default locations
+func catalogSearchPaths() []string {
+	paths := []string{config.Runtime.CatalogPath}
+	for _, p := range defaultCatalogPaths {
+		if p != config.Runtime.CatalogPath {
+			paths = append(paths, p)
+		}
 	}
+	return paths
 }
 
 // getCatalogInfoPath checks if catalog-info.yaml exists and returns the path
 func getCatalogInfoPath(ctx context.Context, repo models.Repository) (string, error) {
-	catalogPaths := []string{
-		"catalog-info.yaml",
-		"catalog-info.yml",
-		".harness/catalog-info.yaml", 
-		".harness/catalog-info.yml",
-	}
+	catalogPaths := catalogSearchPaths()
 	
 	owner := strings.Split(repo.FullName, "/")[0]
 	repoName := strings.Split(repo.FullName, "/")[1]
@@ -820,11 +2111,13 @@ func getCatalogInfoPath(ctx context.Context, repo models.Repository) (string, er
 
 // getCatalogInfoPathAndContent checks if catalog-info.yaml exists and returns both the path and content
 func getCatalogInfoPathAndContent(ctx context.Context, repo models.Repository) (string, string, error) {
-	catalogPaths := []string{
-		"catalog-info.yaml",
-		"catalog-info.yml",
-		".harness/catalog-info.yaml", 
-		".harness/catalog-info.yml",
+	if repo.Source == harness.SourceHarnessCode {
+		return harnessCodeCatalog(ctx, repo)
+	}
+
+	catalogPaths := catalogSearchPaths()
+	if indexed, ok := indexedCatalogPaths(repo); ok {
+		catalogPaths = indexed
 	}
 	
 	owner := strings.Split(repo.FullName, "/")[0]
@@ -862,31 +2155,36 @@ func getCatalogInfoPathAndContent(ctx context.Context, repo models.Repository) (
 	return "", "", fmt.Errorf("no catalog-info.yaml file found in %s", repo.FullName)
 }
 
-// sanitizeYAMLIdentifiers replaces hyphens with underscores in YAML identifier fields
-// This ensures compatibility with Harness IDP API requirements
-func sanitizeYAMLIdentifiers(yamlContent string) string {
-	lines := strings.Split(yamlContent, "\n")
-	for i, line := range lines {
-		// Look for identifier field and replace hyphens with underscores in the value
-		if strings.HasPrefix(strings.TrimSpace(line), "identifier:") {
-			// Split on ":" to separate field and value
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				fieldPart := parts[0]
-				valuePart := strings.TrimSpace(parts[1])
-				// Replace hyphens with underscores in the identifier value
-				sanitizedValue := strings.ReplaceAll(valuePart, "-", "_")
-				lines[i] = fieldPart + ": " + sanitizedValue
-			}
-		}
+// repoIdentifier returns the IDP entity identifier generated for a repository
+func repoIdentifier(repo models.Repository) string {
+	if identifierTemplate != nil || config.Runtime.IdentifierPrefix != "" || config.Runtime.IdentifierSuffix != "" {
+		return templatedIdentifier(repo)
+	}
+	name := sanitizeName(repo.Name)
+	// Normalize identifier by replacing hyphens with underscores
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// generateCatalogYAML renders the catalog-info.yaml content generated for a repository,
+// including an API entity when the repository has an OpenAPI definition
+func generateCatalogYAML(repo models.Repository) (string, error) {
+	yamlContent, err := yaml.Marshal(buildCatalogInfo(repo))
+	if err != nil {
+		return "", err
+	}
+
+	apiYAML, err := apiEntityYAML(repo)
+	if err != nil {
+		return "", err
+	}
+	if apiYAML != "" {
+		return string(yamlContent) + "---\n" + apiYAML, nil
 	}
-	return strings.Join(lines, "\n")
+	return string(yamlContent), nil
 }
 
 func buildCatalogInfo(repo models.Repository) models.CatalogInfo {
-	name := sanitizeName(repo.Name)
-	// Normalize identifier by replacing hyphens with underscores
-	identifier := strings.ReplaceAll(name, "-", "_")
+	identifier := repoIdentifier(repo)
 	
 	annotations := make(map[string]string)
 	for k, v := range config.Defaults.Annotations {
@@ -903,11 +2201,22 @@ func buildCatalogInfo(repo models.Repository) models.CatalogInfo {
 	if repo.Language != "" {
 		annotations["harness.io/language"] = repo.Language
 	}
+	if repo.HasDocs || config.Runtime.TechDocs {
+		annotations["harness.io/techdocs-ref"] = techDocsRef
+	}
+	
+	languages := significantLanguages(repo)
+	if len(languages) > 1 {
+		annotations["harness.io/languages"] = strings.Join(languages, ",")
+	}
 	
 	tags := repo.Topics
-	if repo.Language != "" && !contains(tags, strings.ToLower(repo.Language)) {
-		tags = append(tags, strings.ToLower(repo.Language))
+	for _, language := range languages {
+		if !contains(tags, strings.ToLower(language)) {
+			tags = append(tags, strings.ToLower(language))
+		}
 	}
+	tags = overrideTags(repo, tags)
 	
 	// Build links for IDP 2.0 format
 	links := []models.ComponentLink{
@@ -919,14 +2228,38 @@ func buildCatalogInfo(repo models.Repository) models.CatalogInfo {
 		},
 	}
 	
+	defaults := repoDefaults(repo)
+	applyRuleAnnotations(repo, annotations)
+	applyPropertyAnnotations(repo, annotations)
+	links = applyCIMetadata(repo, annotations, links)
+	applyKubernetesAnnotations(repo, annotations)
+	tags, links = applyTemplate(repo, defaults.Type, annotations, tags, links)
+	tags = normalizeTags(tags)
+	orgID, projectID := repoScope(repo)
+	
+	// The spec fields depend on the kind, as in API mode
+	spec := models.HarnessComponent{
+		Kind:         repoKind(repo),
+		Type:         defaults.Type,
+		Lifecycle:    defaults.Lifecycle,
+		System:       defaults.System,
+		DependsOn:    repoDependsOn(repo),
+		ProvidesAPIs: providedAPIs(repo),
+	}
+	applyKindSpec(repo, defaults, &spec)
+	var definition map[string]string
+	if spec.Definition != "" {
+		definition = map[string]string{"$text": spec.Definition}
+	}
+	
 	return models.CatalogInfo{
 		APIVersion:        "harness.io/v1",
 		Identifier:        identifier,
 		Name:              repo.Name,
-		Kind:              "Component",
-		Type:              config.Defaults.Type,
-		ProjectIdentifier: config.Harness.ProjectID,
-		OrgIdentifier:     config.Harness.OrgID,
+		Kind:              spec.Kind,
+		Type:              spec.Type,
+		ProjectIdentifier: projectID,
+		OrgIdentifier:     orgID,
 		Owner:             getOwner(repo),
 		Metadata: models.CatalogMetadata{
 			Description: repo.Description,
@@ -935,15 +2268,19 @@ func buildCatalogInfo(repo models.Repository) models.CatalogInfo {
 			Links:       links,
 		},
 		Spec: models.CatalogSpec{
-			Lifecycle: config.Defaults.Lifecycle,
+			Lifecycle:    spec.Lifecycle,
+			System:       spec.System,
+			Domain:       spec.Domain,
+			DependsOn:    spec.DependsOn,
+			ProvidesAPIs: spec.ProvidesAPIs,
+			Definition:   definition,
+			Members:      spec.Members,
 		},
 	}
 }
 
 func buildHarnessComponent(repo models.Repository) models.HarnessComponent {
-	name := sanitizeName(repo.Name)
-	// Normalize identifier by replacing hyphens with underscores
-	identifier := strings.ReplaceAll(name, "-", "_")
+	identifier := repoIdentifier(repo)
 	
 	annotations := make(map[string]string)
 	for k, v := range config.Defaults.Annotations {
@@ -960,11 +2297,22 @@ func buildHarnessComponent(repo models.Repository) models.HarnessComponent {
 	if repo.Language != "" {
 		annotations["harness.io/language"] = repo.Language
 	}
+	if repo.HasDocs {
+		annotations["harness.io/techdocs-ref"] = techDocsRef
+	}
+	
+	languages := significantLanguages(repo)
+	if len(languages) > 1 {
+		annotations["harness.io/languages"] = strings.Join(languages, ",")
+	}
 	
 	tags := repo.Topics
-	if repo.Language != "" && !contains(tags, strings.ToLower(repo.Language)) {
-		tags = append(tags, strings.ToLower(repo.Language))
+	for _, language := range languages {
+		if !contains(tags, strings.ToLower(language)) {
+			tags = append(tags, strings.ToLower(language))
+		}
 	}
+	tags = overrideTags(repo, tags)
 	
 	links := []models.ComponentLink{
 		{
@@ -978,25 +2326,72 @@ func buildHarnessComponent(repo models.Repository) models.HarnessComponent {
 	metadata["stars"] = repo.Stars
 	metadata["forks"] = repo.Forks
 	metadata["language"] = repo.Language
+	metadata["languages"] = languages
 	metadata["created_at"] = repo.CreatedAt
 	metadata["updated_at"] = repo.UpdatedAt
 	
-	return models.HarnessComponent{
-		Identifier:  identifier,  // IDP 2.0 requires identifier field
-		Name:        repo.Name,     // Keep original repo name with hyphens
-		Type:        config.Defaults.Type,
-		Lifecycle:   config.Defaults.Lifecycle,
-		Owner:       getOwner(repo),
-		System:      config.Defaults.System,
-		Description: repo.Description,
-		Tags:        tags,
-		Annotations: annotations,
-		Links:       links,
-		Metadata:    metadata,
-	}
+	defaults := repoDefaults(repo)
+	applyRuleAnnotations(repo, annotations)
+	applyPropertyAnnotations(repo, annotations)
+	links = applyCIMetadata(repo, annotations, links)
+	applyKubernetesAnnotations(repo, annotations)
+	tags, links = applyTemplate(repo, defaults.Type, annotations, tags, links)
+	tags = normalizeTags(tags)
+	
+	component := models.HarnessComponent{
+		Kind:         repoKind(repo),
+		Identifier:   identifier, // IDP 2.0 requires identifier field
+		Name:         repo.Name,  // Keep original repo name with hyphens
+		Type:         defaults.Type,
+		Lifecycle:    defaults.Lifecycle,
+		Owner:        getOwner(repo),
+		System:       defaults.System,
+		DependsOn:    repoDependsOn(repo),
+		ProvidesAPIs: providedAPIs(repo),
+		Description:  repo.Description,
+		Tags:         tags,
+		Annotations:  annotations,
+		Links:        links,
+		Metadata:     metadata,
+	}
+	applyKindSpec(repo, defaults, &component)
+	return component
 }
 
 func getOwner(repo models.Repository) string {
+	owner := resolveOwner(repo)
+	if ownerInvalid(repo, owner) {
+		return config.Defaults.Owner
+	}
+	return owner
+}
+
+// resolveOwner picks the repository's owner from overrides, custom properties, rules
+// and CODEOWNERS, falling back to the default owner
+func resolveOwner(repo models.Repository) string {
+	// An explicit per-repo owner wins over CODEOWNERS
+	if owner := repoOverrides[repo.Name].Owner; owner != "" {
+		return owner
+	}
+	// Custom properties and rules are explicit configuration, so they also win over CODEOWNERS
+	if owner := propertyOwner(repo); owner != "" {
+		return owner
+	}
+	if owner := ruleActions(repo).Owner; owner != "" {
+		return owner
+	}
+	// Prefer the first CODEOWNERS entry that maps to a Harness owner, then the
+	// first team with a generated Group entity
+	for _, handle := range repo.CodeOwners {
+		if owner, ok := mapOwner(handle); ok {
+			return owner
+		}
+	}
+	for _, handle := range repo.CodeOwners {
+		if owner, ok := teamOwner(handle); ok {
+			return owner
+		}
+	}
 	if len(repo.CodeOwners) > 0 {
 		return repo.CodeOwners[0]
 	}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// compiledRule is a rule from the config file with its name pattern compiled
+type compiledRule struct {
+	models.Rule
+	namePattern *regexp.Regexp
+}
+
+var rules []compiledRule
+
+// loadRules compiles the rules from the config file
+func loadRules() error {
+	rules = nil
+	for i, rule := range config.Rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", ruleLabel(i, rule), err)
+		}
+		rules = append(rules, compiled)
+	}
+
+	if len(rules) > 0 {
+		log.Printf("Loaded %d rules", len(rules))
+	}
+	return nil
+}
+
+// ruleActions merges the actions of every rule matching the repository, in order
+func ruleActions(repo models.Repository) models.RuleActions {
+	var actions models.RuleActions
+	for _, rule := range rules {
+		if !rule.matches(repo) {
+			continue
+		}
+
+		if rule.Set.Kind != "" {
+			actions.Kind = rule.Set.Kind
+		}
+		if rule.Set.Type != "" {
+			actions.Type = rule.Set.Type
+		}
+		if rule.Set.Lifecycle != "" {
+			actions.Lifecycle = rule.Set.Lifecycle
+		}
+		if rule.Set.System != "" {
+			actions.System = rule.Set.System
+		}
+		if rule.Set.Domain != "" {
+			actions.Domain = rule.Set.Domain
+		}
+		if rule.Set.Owner != "" {
+			actions.Owner = rule.Set.Owner
+		}
+		for _, tag := range rule.Set.Tags {
+			if !contains(actions.Tags, tag) {
+				actions.Tags = append(actions.Tags, tag)
+			}
+		}
+		for key, value := range rule.Set.Annotations {
+			if actions.Annotations == nil {
+				actions.Annotations = make(map[string]string)
+			}
+			actions.Annotations[key] = value
+		}
+	}
+	return actions
+}
+
+// applyRuleAnnotations renders the annotations of matching rules into annotations,
+// replacing defaults so integrations like PagerDuty or SonarQube can be set per repository
+func applyRuleAnnotations(repo models.Repository, annotations map[string]string) {
+	for key, value := range ruleActions(repo).Annotations {
+		if rendered := renderTemplateValue(repo, value); rendered != "" {
+			annotations[key] = rendered
+		}
+	}
+}
+
+func compileRule(rule models.Rule) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule}
+	if rule.Match.Name != "" {
+		pattern, err := regexp.Compile(rule.Match.Name)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid name pattern: %w", err)
+		}
+		compiled.namePattern = pattern
+	}
+	if err := validateKind(rule.Set.Kind); err != nil {
+		return compiled, err
+	}
+	return compiled, nil
+}
+
+// isEmpty reports whether the rule has no conditions
+func (r compiledRule) isEmpty() bool {
+	m := r.Match
+	return len(m.Languages) == 0 && len(m.Topics) == 0 && m.Name == "" && m.Archived == nil && len(m.Teams) == 0
+}
+
+func (r compiledRule) matches(repo models.Repository) bool {
+	match := r.Match
+
+	if len(match.Languages) > 0 {
+		found := false
+		for _, language := range match.Languages {
+			if strings.EqualFold(language, repo.Language) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(match.Topics) > 0 {
+		found := false
+		for _, topic := range match.Topics {
+			if contains(repo.Topics, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.namePattern != nil && !r.namePattern.MatchString(repo.Name) {
+		return false
+	}
+
+	if match.Archived != nil && *match.Archived != repo.Archived {
+		return false
+	}
+
+	if len(match.Teams) > 0 && !ownedByTeam(repo, match.Teams) {
+		return false
+	}
+
+	return true
+}
+
+// ownedByTeam reports whether any CODEOWNERS entry of the repository is one of teams.
+// Teams match on either org/team or the bare team name, case-insensitively.
+func ownedByTeam(repo models.Repository, teams []string) bool {
+	for _, owner := range repo.CodeOwners {
+		owner = strings.ToLower(strings.TrimPrefix(owner, "@"))
+		slug := owner[strings.LastIndex(owner, "/")+1:]
+		if !strings.Contains(owner, "/") {
+			continue // a user, not a team
+		}
+		for _, team := range teams {
+			team = strings.ToLower(strings.TrimPrefix(team, "@"))
+			if team == owner || team == slug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ruleLabel(index int, rule models.Rule) string {
+	if rule.Name != "" {
+		return fmt.Sprintf("%q", rule.Name)
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// withScores adds the entity's scorecard scores to a successful result when
+// --scorecards is set, so the summary and report show the quality posture of freshly
+// onboarded components. Failing to fetch them only logs a warning.
+func withScores(ctx context.Context, repo models.Repository, result errors.ProcessingResult) errors.ProcessingResult {
+	if !config.Runtime.Scorecards || result.Error != nil || result.Action == "skipped" {
+		return result
+	}
+
+	identifier := result.Identifier
+	if identifier == "" {
+		identifier = repoIdentifier(repo)
+	}
+	scores, err := harnessFor(repo).GetScores(ctx, repoKind(repo), identifier)
+	if err != nil {
+		log.Printf("Warning: failed to get scorecard scores for %s: %v", repo.FullName, err)
+		return result
+	}
+
+	result.Scores = make(map[string]float64, len(scores))
+	for _, score := range scores {
+		result.Scores[score.Scorecard] = score.Score
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// shard is the 1-based part of the repositories a --shard run processes
+type shard struct {
+	Index int
+	Count int
+}
+
+// runShard is the parsed --shard, zero when the run isn't sharded
+var runShard shard
+
+// parseShard parses an index/count value such as 2/5
+func parseShard(value string) (shard, error) {
+	index, count, ok := strings.Cut(value, "/")
+	if !ok {
+		return shard{}, fmt.Errorf("invalid --shard %q (expected index/count, e.g. 2/5)", value)
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(index))
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid --shard %q: %w", value, err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid --shard %q: %w", value, err)
+	}
+	if n < 1 || i < 1 || i > n {
+		return shard{}, fmt.Errorf("invalid --shard %q: index must be between 1 and the shard count", value)
+	}
+	return shard{Index: i, Count: n}, nil
+}
+
+// applyShard keeps the repositories that hash to this run's shard. A repository's
+// shard depends only on its full name, so runners never overlap and it stays on the
+// same runner as the organization grows.
+func applyShard(repos []models.Repository) []models.Repository {
+	if runShard.Count <= 1 {
+		return repos
+	}
+
+	var kept []models.Repository
+	for _, repo := range repos {
+		if shardOf(repo.FullName, runShard.Count) == runShard.Index {
+			kept = append(kept, repo)
+		}
+	}
+	log.Printf("Shard %d/%d: processing %d of %d repositories", runShard.Index, runShard.Count, len(kept), len(repos))
+	return kept
+}
+
+// shardOf returns the 1-based shard of a repository
+func shardOf(fullName string, count int) int {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(fullName)))
+	return int(h.Sum32()%uint32(count)) + 1
+}
+
+// shardStateFile gives each shard its own state file, e.g. state.shard-2-of-5.json,
+// so runners sharing a workspace or bucket don't overwrite each other's state
+func shardStateFile(path string) string {
+	if runShard.Count <= 1 {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.shard-%d-of-%d%s", strings.TrimSuffix(path, ext), runShard.Index, runShard.Count, ext)
+}
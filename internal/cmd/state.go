@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/state"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and manage the state file",
+	Long: `Operates on the state file used to skip unchanged repositories, retry
+failures and offboard removed repositories. Uses --state-file, or the default
+state file when none is configured. With --shard, the shard's own state file is
+used, as in a sharded run.`,
+}
+
+var stateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded repositories and their last outcome",
+	Args:  cobra.NoArgs,
+	RunE:  runStateList,
+}
+
+var stateResetCmd = &cobra.Command{
+	Use:   "reset <repo>...",
+	Short: "Forget the recorded state of repositories so they are reprocessed",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runStateReset,
+}
+
+var stateResetAllCmd = &cobra.Command{
+	Use:   "reset-all",
+	Short: "Forget the recorded state of every repository",
+	Args:  cobra.NoArgs,
+	RunE:  runStateResetAll,
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Write the state as JSON to a file or stdout",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runStateExport,
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Replace the state with a previously exported file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStateImport,
+}
+
+var stateCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove repositories that haven't been processed recently",
+	Args:  cobra.NoArgs,
+	RunE:  runStateCleanup,
+}
+
+func init() {
+	stateListCmd.Flags().String("status", "", "Only list repositories with this status (success, skipped, error)")
+	stateImportCmd.Flags().Bool("merge", false, "Merge into the existing state instead of replacing it")
+	stateCleanupCmd.Flags().String("older-than", "30d", "Remove entries last processed before this age (e.g. 30d, 12h)")
+
+	stateCmd.AddCommand(stateListCmd, stateResetCmd, stateResetAllCmd, stateExportCmd, stateImportCmd, stateCleanupCmd)
+	rootCmd.AddCommand(stateCmd)
+}
+
+// loadStateManager opens the configured state file, or the --shard's own one, for
+// the state subcommands. Subcommands that change the state lock it first, which the
+// caller releases with unlockState.
+func loadStateManager(lock bool) (*state.Manager, error) {
+	path := config.Runtime.StateFile
+	if path == "" {
+		path = defaultStateFile
+	}
+	if config.Runtime.Shard != "" {
+		parsed, err := parseShard(config.Runtime.Shard)
+		if err != nil {
+			return nil, err
+		}
+		runShard = parsed
+		path = shardStateFile(path)
+	}
+
+	manager, err := state.NewManager(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	if lock {
+		if err := manager.Lock(context.Background(), config.Runtime.StateLockTTL, config.Runtime.StateLockWait); err != nil {
+			return nil, fmt.Errorf("failed to lock state: %w", err)
+		}
+	}
+	return manager, nil
+}
+
+func runStateList(cmd *cobra.Command, args []string) error {
+	manager, err := loadStateManager(false)
+	if err != nil {
+		return err
+	}
+
+	status, _ := cmd.Flags().GetString("status")
+
+	states := manager.All()
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Repository < states[j].Repository
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tMODE\tSTATUS\tACTION\tIDENTIFIER\tATTEMPTS\tLAST PROCESSED")
+	shown := 0
+	for _, s := range states {
+		if status != "" && s.Status != status {
+			continue
+		}
+		lastProcessed := "-"
+		if !s.LastProcessed.IsZero() {
+			lastProcessed = s.LastProcessed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", s.Repository, s.Mode, s.Status, s.Action, s.Identifier, s.Attempts, lastProcessed)
+		shown++
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d of %d repositories in %s\n", shown, len(states), manager.Path())
+	return nil
+}
+
+func runStateReset(cmd *cobra.Command, args []string) error {
+	manager, err := loadStateManager(true)
+	if err != nil {
+		return err
+	}
+	defer unlockState(manager)
+
+	removed := 0
+	for _, arg := range args {
+		fullName := stateRepoName(arg)
+		if manager.Remove(fullName) {
+			fmt.Printf("Reset %s\n", fullName)
+			removed++
+		} else {
+			fmt.Printf("No state recorded for %s\n", fullName)
+		}
+	}
+
+	if removed == 0 {
+		return nil
+	}
+	return manager.Save()
+}
+
+func runStateResetAll(cmd *cobra.Command, args []string) error {
+	manager, err := loadStateManager(true)
+	if err != nil {
+		return err
+	}
+	defer unlockState(manager)
+
+	n := manager.Reset()
+	if err := manager.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %d repositories from %s\n", n, manager.Path())
+	return nil
+}
+
+func runStateExport(cmd *cobra.Command, args []string) error {
+	manager, err := loadStateManager(false)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if len(args) == 1 {
+		f, err := os.Create(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return manager.Export(w)
+}
+
+func runStateImport(cmd *cobra.Command, args []string) error {
+	manager, err := loadStateManager(true)
+	if err != nil {
+		return err
+	}
+	defer unlockState(manager)
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+
+	merge, _ := cmd.Flags().GetBool("merge")
+	n, err := manager.Import(f, merge)
+	if err != nil {
+		return err
+	}
+	if err := manager.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d repositories into %s\n", n, manager.Path())
+	return nil
+}
+
+func runStateCleanup(cmd *cobra.Command, args []string) error {
+	olderThan, _ := cmd.Flags().GetString("older-than")
+	age, err := parseAge(olderThan)
+	if err != nil {
+		return err
+	}
+
+	manager, err := loadStateManager(true)
+	if err != nil {
+		return err
+	}
+	defer unlockState(manager)
+
+	removed := manager.Prune(time.Now().Add(-age))
+	for _, name := range removed {
+		fmt.Printf("Removed %s\n", name)
+	}
+	if len(removed) > 0 {
+		if err := manager.Save(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Removed %d repositories not processed in the last %s\n", len(removed), olderThan)
+	return nil
+}
+
+// stateRepoName qualifies a bare repository name with the configured organization
+func stateRepoName(name string) string {
+	if strings.Contains(name, "/") || config.GitHub.Organization == "" {
+		return name
+	}
+	return config.GitHub.Organization + "/" + name
+}
+
+// parseAge parses a duration that may also use a "d" suffix for days
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q: %w", value, err)
+	}
+	return d, nil
+}
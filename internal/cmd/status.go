@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/models"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Report onboarding coverage for the organization",
+	Long: `Lists how many repositories have a catalog-info.yaml file, how many have
+a registered entity in Harness IDP, and which are still missing, along with an
+overall onboarding percentage. Nothing is modified.`,
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().Bool("show-onboarded", false, "Also list repositories that are fully onboarded")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := validateConnectionConfig(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	if err := loadProjectRoutes(); err != nil {
+		return err
+	}
+	if err := initClients(); err != nil {
+		return err
+	}
+
+	repos, err := discoverRepositories(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Checking onboarding status of %d repositories", len(repos))
+
+	registered, err := registeredComponents(ctx)
+	if err != nil {
+		log.Printf("Warning: could not list components, looking each one up instead: %v", err)
+	}
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan auditResult, len(repos))
+
+	for _, repo := range repos {
+		go func(r models.Repository) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			results <- auditRepository(ctx, r, registered)
+		}(repo)
+	}
+
+	var audits []auditResult
+	for i := 0; i < len(repos); i++ {
+		audits = append(audits, <-results)
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		return audits[i].Repository < audits[j].Repository
+	})
+
+	showOnboarded, _ := cmd.Flags().GetBool("show-onboarded")
+	printStatusReport(audits, showOnboarded)
+	return nil
+}
+
+func printStatusReport(audits []auditResult, showOnboarded bool) {
+	var withCatalog, registered, onboarded, failed int
+	var missingCatalog, missingEntity, errored []auditResult
+
+	for _, a := range audits {
+		if a.Verdict == verdictError {
+			failed++
+			errored = append(errored, a)
+			continue
+		}
+		if a.CatalogPath != "" {
+			withCatalog++
+		} else {
+			missingCatalog = append(missingCatalog, a)
+		}
+		if a.Registered {
+			registered++
+		} else if a.CatalogPath != "" {
+			missingEntity = append(missingEntity, a)
+		}
+		if a.CatalogPath != "" && a.Registered {
+			onboarded++
+		}
+	}
+
+	total := len(audits)
+	percent := func(n int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(total)
+	}
+
+	fmt.Printf("\n📊 Onboarding Status for %s:\n", config.GitHub.Organization)
+	fmt.Printf("   Total repositories: %d\n", total)
+	fmt.Printf("   With catalog-info.yaml: %d (%.1f%%)\n", withCatalog, percent(withCatalog))
+	fmt.Printf("   Registered in Harness IDP: %d (%.1f%%)\n", registered, percent(registered))
+	fmt.Printf("   Fully onboarded: %d (%.1f%%)\n", onboarded, percent(onboarded))
+	if failed > 0 {
+		fmt.Printf("   Could not be checked: %d\n", failed)
+	}
+
+	if len(missingCatalog) > 0 {
+		fmt.Printf("\n📄 Missing catalog-info.yaml (%d):\n", len(missingCatalog))
+		for _, a := range missingCatalog {
+			fmt.Printf("   - %s\n", a.Repository)
+		}
+	}
+
+	if len(missingEntity) > 0 {
+		fmt.Printf("\n🔗 Catalog file present but not registered (%d):\n", len(missingEntity))
+		for _, a := range missingEntity {
+			fmt.Printf("   - %s (%s)\n", a.Repository, a.CatalogPath)
+		}
+	}
+
+	if len(errored) > 0 {
+		fmt.Printf("\n❌ Errors (%d):\n", len(errored))
+		for _, a := range errored {
+			fmt.Printf("   - %s: %s\n", a.Repository, a.Detail)
+		}
+	}
+
+	if showOnboarded {
+		fmt.Printf("\n✅ Onboarded (%d):\n", onboarded)
+		for _, a := range audits {
+			if a.CatalogPath != "" && a.Registered {
+				fmt.Printf("   - %s\n", a.Repository)
+			}
+		}
+	}
+}
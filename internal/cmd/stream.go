@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// streamModes are the modes --stream can process one repository at a time
+var streamModes = []string{"yaml", "api", "register"}
+
+// validateStream rejects --stream with options that need every repository discovered
+// before the first one is processed
+func validateStream() error {
+	if !config.Runtime.Stream {
+		return nil
+	}
+	if !contains(streamModes, config.Runtime.Mode) {
+		return fmt.Errorf("--stream is not supported in %s mode (supported: %s)", config.Runtime.Mode, strings.Join(streamModes, ", "))
+	}
+	for flag, set := range map[string]bool{
+		"--catalog-repo":        config.GitHub.CatalogRepo != "",
+		"--api-batch-size":      config.Runtime.APIBatchSize > 0,
+		"--register-batch-size": config.Runtime.RegisterBatchSize > 0,
+		"--only-failed":         config.Runtime.OnlyFailed,
+		"--search":              config.Runtime.Search != "",
+		"--sort pushed":         config.Runtime.Sort == "pushed",
+		"--limit":               config.Runtime.Limit > 0,
+	} {
+		if set {
+			return fmt.Errorf("%s cannot be used with --stream", flag)
+		}
+	}
+	return nil
+}
+
+// streamProcessor returns the per-repository processing of the current mode
+func streamProcessor() func(context.Context, models.Repository) errors.ProcessingResult {
+	switch config.Runtime.Mode {
+	case "api":
+		return func(ctx context.Context, repo models.Repository) errors.ProcessingResult {
+			return withScores(ctx, repo, processRepositoryAPIWithResult(ctx, repo))
+		}
+	case "register":
+		return func(ctx context.Context, repo models.Repository) errors.ProcessingResult {
+			return withScores(ctx, repo, processRepositoryRegisterWithResult(ctx, repo))
+		}
+	default:
+		return processRepositoryYAMLWithResult
+	}
+}
+
+// processStreamMode hands each page of repositories to the worker pool as soon as
+// GitHub returns it, instead of discovering the whole organization first. Listing
+// waits for a free worker, so memory stays flat however large the organization is.
+// Repositories are enriched, filtered and processed one by one; dependsOn relations
+// between repositories aren't resolved since not all modules are known up front.
+func processStreamMode(ctx context.Context, enrich bool) error {
+	if err := loadTeamRepositories(ctx); err != nil {
+		return err
+	}
+	if config.Runtime.Mode == "register" {
+		indexCatalogFiles(ctx)
+	}
+	resetOwnerChecks()
+	requiredFileSkips = nil
+	process := streamProcessor()
+
+	log.Printf("Streaming repositories of %s in %s mode", config.GitHub.Organization, strings.ToUpper(config.Runtime.Mode))
+
+	summary := newRunSummary()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	listed, candidates := 0, 0
+
+	err := githubClient.StreamRepositories(ctx, config.GitHub.Organization, func(page []models.Repository) error {
+		listed += len(page)
+		for _, repo := range page {
+			if !streamCandidate(repo) {
+				continue
+			}
+			candidates++
+
+			semaphore <- struct{}{}
+			wg.Add(1)
+			go func(r models.Repository) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				result, ok := streamRepository(ctx, r, enrich, process)
+				if !ok {
+					return
+				}
+				mu.Lock()
+				summary.AddResult(result)
+				mu.Unlock()
+			}(repo)
+		}
+		return ctx.Err()
+	})
+	wg.Wait()
+
+	log.Printf("Streamed %d repositories, %d after include/exclude and shard filtering", listed, candidates)
+	if err != nil {
+		return fmt.Errorf("failed to discover repositories: %w", err)
+	}
+	if config.Runtime.DryRun {
+		return nil
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during %s processing", summary.Total, strings.ToUpper(config.Runtime.Mode))
+	}
+	return nil
+}
+
+// streamCandidate applies the checks that need only the listing, so repositories
+// filtered out by name are never enriched
+func streamCandidate(repo models.Repository) bool {
+	if repo.Archived && !config.Runtime.IncludeArchived {
+		return false
+	}
+	if !repoIncluded(repo.Name) {
+		return false
+	}
+	return runShard.Count <= 1 || shardOf(repo.FullName, runShard.Count) == runShard.Index
+}
+
+// streamRepository runs one listed repository through the steps runOnce applies to
+// the whole discovery, then processes it. It returns false for repositories that are
+// filtered out or unchanged, which don't appear in the summary.
+func streamRepository(ctx context.Context, repo models.Repository, enrich bool, process func(context.Context, models.Repository) errors.ProcessingResult) (errors.ProcessingResult, bool) {
+	if enrich {
+		enriched, err := githubClient.EnrichRepository(ctx, repo)
+		if err != nil {
+			log.Printf("Warning: failed to enrich repository %s: %v", repo.FullName, err)
+			return errors.ProcessingResult{}, false
+		}
+		repo = enriched
+	}
+	if !repoSelected(repo) {
+		return errors.ProcessingResult{}, false
+	}
+	if base := repoOverrides[repo.Name].BaseBranch; base != "" {
+		repo.BaseBranch = base
+	}
+	if stateManager != nil && stateManager.IsUnchanged(repo, config.Runtime.Mode) {
+		log.Printf("DEBUG: Skipping unchanged repository %s", repo.FullName)
+		return errors.ProcessingResult{}, false
+	}
+
+	if len(config.Runtime.RequiredFiles) > 0 {
+		path, err := missingRequiredFile(ctx, repo)
+		if err != nil {
+			log.Printf("Warning: could not check required files of %s, processing it: %v", repo.FullName, err)
+		} else if path != "" {
+			return requiredFileSkip(repo, path), true
+		}
+	}
+
+	if config.Runtime.DryRun {
+		log.Printf("Would process %s", repo.FullName)
+		return errors.ProcessingResult{}, false
+	}
+
+	checkOwner(ctx, repo)
+	throttle(ctx)
+	result := retryOnSecondaryLimit(ctx, repo, process)
+	recordState(repo, result)
+	markOnboarded(ctx, repo, result)
+	return result, true
+}
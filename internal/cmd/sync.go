@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+func processSyncMode(ctx context.Context, repos []models.Repository) error {
+	log.Printf("Processing %d repositories in SYNC mode", len(repos))
+
+	if stateManager == nil {
+		log.Printf("Warning: no state file configured, every component will be updated")
+	}
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan errors.ProcessingResult, len(repos))
+
+	for _, repo := range repos {
+		go func(r models.Repository) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			result := withScores(ctx, r, retryOnSecondaryLimit(ctx, r, processRepositorySyncWithResult))
+			recordState(r, result)
+			results <- result
+		}(repo)
+	}
+
+	summary := newRunSummary()
+	for i := 0; i < len(repos); i++ {
+		result := <-results
+		summary.AddResult(result)
+	}
+
+	summary.PrintSummary()
+	writeRunReport(summary)
+
+	if summary.Total > 0 {
+		return fmt.Errorf("encountered %d errors during SYNC processing", summary.Total)
+	}
+
+	return nil
+}
+
+func processRepositorySyncWithResult(ctx context.Context, repo models.Repository) errors.ProcessingResult {
+	log.Printf("Processing repository %s in SYNC mode", repo.FullName)
+
+	if procErr := checkComponentPolicies(ctx, repo); procErr != nil {
+		return policyResult(repo, procErr)
+	}
+
+	component := buildHarnessComponent(repo)
+	fingerprint := componentFingerprint(component)
+
+	if stateManager != nil {
+		if s, ok := stateManager.Get(repo.FullName); ok && s.Fingerprint == fingerprint {
+			log.Printf("DEBUG: Component %s unchanged, skipping update", component.Identifier)
+			return errors.ProcessingResult{
+				Repository: repo.FullName,
+				Success:    true,
+				Error:      nil,
+				Message:    "Component unchanged",
+				Skipped:    true,
+				Action:     "unchanged",
+			}
+		}
+	}
+
+	err := harnessFor(repo).UpdateComponentIfChanged(ctx, component)
+	if err == harness.ErrUnchanged {
+		if stateManager != nil {
+			stateManager.SetFingerprint(repo.FullName, fingerprint)
+		}
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    true,
+			Message:    "Component unchanged in IDP",
+			Skipped:    true,
+			Action:     "unchanged",
+		}
+	}
+	if err != nil {
+		procErr := errors.CategorizeError(err, repo.FullName)
+		return errors.ProcessingResult{
+			Repository: repo.FullName,
+			Success:    false,
+			Error:      procErr,
+			Message:    "Component update failed",
+			Action:     "failed",
+		}
+	}
+
+	if stateManager != nil {
+		stateManager.SetFingerprint(repo.FullName, fingerprint)
+	}
+
+	log.Printf("Successfully synced component for repository: %s", repo.FullName)
+	return errors.ProcessingResult{
+		Repository: repo.FullName,
+		Success:    true,
+		Error:      nil,
+		Message:    "Component updated successfully",
+		Action:     "updated",
+	}
+}
+
+// componentFingerprint hashes the fields of a component that are synced to IDP.
+// Volatile metadata such as star counts is excluded so it doesn't trigger updates.
+func componentFingerprint(component models.HarnessComponent) string {
+	material := component
+	material.Metadata = nil
+
+	data, err := json.Marshal(material)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
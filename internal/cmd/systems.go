@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// systemMatcher pairs a configured system with its compiled repository conditions
+type systemMatcher struct {
+	identifier string
+	rule       compiledRule
+}
+
+var systemMatchers []systemMatcher
+
+// loadSystems validates the domains and systems config and compiles the system conditions
+func loadSystems() error {
+	domains := make(map[string]bool)
+	for _, domain := range config.Domains {
+		if domain.Name == "" {
+			return fmt.Errorf("domain name is required")
+		}
+		domains[domain.Name] = true
+	}
+
+	systemMatchers = nil
+	for _, system := range config.Systems {
+		if system.Name == "" {
+			return fmt.Errorf("system name is required")
+		}
+		if system.Domain != "" && !domains[system.Domain] {
+			return fmt.Errorf("system %q: unknown domain %q", system.Name, system.Domain)
+		}
+
+		rule, err := compileRule(models.Rule{Name: system.Name, Match: system.Match})
+		if err != nil {
+			return fmt.Errorf("system %q: %w", system.Name, err)
+		}
+		// A system without conditions is created but never assigned automatically
+		if rule.isEmpty() {
+			continue
+		}
+		systemMatchers = append(systemMatchers, systemMatcher{
+			identifier: catalog.ToIdentifier(system.Name),
+			rule:       rule,
+		})
+	}
+
+	return nil
+}
+
+// matchSystem returns the identifier of the first configured system matching the repository
+func matchSystem(repo models.Repository) string {
+	for _, m := range systemMatchers {
+		if m.rule.matches(repo) {
+			return m.identifier
+		}
+	}
+	return ""
+}
+
+// syncSystems creates the configured Domain and System entities. Domains go first
+// so systems can reference them.
+func syncSystems(ctx context.Context) error {
+	var entities []models.HarnessEntity
+	for _, domain := range config.Domains {
+		entities = append(entities, models.HarnessEntity{
+			Kind:        "Domain",
+			Identifier:  catalog.ToIdentifier(domain.Name),
+			Name:        domain.Name,
+			Owner:       orDefault(domain.Owner, config.Defaults.Owner),
+			Description: domain.Description,
+		})
+	}
+	for _, system := range config.Systems {
+		entity := models.HarnessEntity{
+			Kind:        "System",
+			Identifier:  catalog.ToIdentifier(system.Name),
+			Name:        system.Name,
+			Owner:       orDefault(system.Owner, config.Defaults.Owner),
+			Description: system.Description,
+		}
+		if system.Domain != "" {
+			entity.Spec = map[string]interface{}{"domain": catalog.ToIdentifier(system.Domain)}
+		}
+		entities = append(entities, entity)
+	}
+
+	if len(entities) == 0 {
+		return nil
+	}
+
+	log.Printf("Syncing %d domains and %d systems", len(config.Domains), len(config.Systems))
+
+	failed := 0
+	for _, entity := range entities {
+		if config.Runtime.DryRun {
+			log.Printf("Would create %s %s", entity.Kind, entity.Identifier)
+			continue
+		}
+
+		if err := harnessClient.CreateEntity(ctx, entity); err != nil {
+			procErr := errors.CategorizeError(err, entity.Identifier)
+			if procErr.Type == errors.ErrorTypeEntityExists {
+				log.Printf("DEBUG: %s %s already exists", entity.Kind, entity.Identifier)
+				continue
+			}
+			log.Printf("Warning: failed to create %s %s: %s", entity.Kind, entity.Identifier, procErr.GetUserFriendlyMessage())
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d domain and system entities", failed, len(entities))
+	}
+	return nil
+}
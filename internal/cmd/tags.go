@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"log"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_.:-]+`)
+
+// normalizeTags applies the tag policy: tags are normalized, then filtered by the
+// allow and deny lists, de-duplicated and capped at the maximum count
+func normalizeTags(tags []string) []string {
+	policy := config.TagPolicy
+	replacement := policy.Replacement
+	if replacement == "" {
+		replacement = "-"
+	}
+
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if !policy.KeepCase {
+			tag = strings.ToLower(tag)
+		}
+		tag = strings.Trim(invalidTagChars.ReplaceAllString(tag, replacement), replacement)
+		if tag == "" || contains(normalized, tag) {
+			continue
+		}
+		if len(policy.Allow) > 0 && !matchesAny(policy.Allow, tag) {
+			continue
+		}
+		if matchesAny(policy.Deny, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+
+	if policy.MaxTags > 0 && len(normalized) > policy.MaxTags {
+		normalized = normalized[:policy.MaxTags]
+	}
+	return normalized
+}
+
+// matchesAny reports whether tag matches one of the glob patterns
+func matchesAny(patterns []string, tag string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, tag)
+		if err != nil {
+			log.Printf("Warning: invalid tag pattern %q: %v", pattern, err)
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/harness"
+	"harness-onboarder/internal/models"
+)
+
+// teamGroups maps lowercased GitHub team slugs, with and without the org prefix,
+// to the identifiers of the Group entities generated for them
+var teamGroups = make(map[string]string)
+
+// syncTeams creates an IDP Group entity for every team in the organization, so
+// CODEOWNERS teams resolve to owners that exist in the catalog
+func syncTeams(ctx context.Context) error {
+	teams, err := githubClient.ListTeams(ctx, config.GitHub.Organization)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Syncing %d GitHub teams to IDP groups", len(teams))
+
+	var created, existing, failed int
+	for _, team := range teams {
+		group := teamGroup(team)
+
+		teamGroups[strings.ToLower(team.Slug)] = group.Identifier
+		teamGroups[strings.ToLower(config.GitHub.Organization+"/"+team.Slug)] = group.Identifier
+
+		if config.Runtime.DryRun {
+			log.Printf("Would create group %s with %d members", group.Identifier, len(group.Members))
+			continue
+		}
+
+		if err := harnessClient.CreateGroup(ctx, group); err != nil {
+			procErr := errors.CategorizeError(err, team.Slug)
+			if procErr.Type == errors.ErrorTypeEntityExists {
+				existing++
+				continue
+			}
+			log.Printf("Warning: failed to create group for team %s: %s", team.Slug, procErr.GetUserFriendlyMessage())
+			failed++
+			continue
+		}
+		created++
+	}
+
+	if !config.Runtime.DryRun {
+		log.Printf("Groups: %d created, %d already existed, %d failed", created, existing, failed)
+	}
+	if failed > 0 && created+existing == 0 {
+		return fmt.Errorf("failed to create any of %d groups", failed)
+	}
+	return nil
+}
+
+// teamGroup builds the Group entity for a GitHub team
+func teamGroup(team models.Team) models.HarnessGroup {
+	members := make([]string, 0, len(team.Members))
+	for _, login := range team.Members {
+		if owner, ok := mapOwner(login); ok {
+			members = append(members, owner)
+		} else {
+			members = append(members, login)
+		}
+	}
+
+	name := team.Name
+	if name == "" {
+		name = team.Slug
+	}
+
+	group := models.HarnessGroup{
+		Identifier:  catalog.ToIdentifier(team.Slug),
+		Name:        name,
+		Description: team.Description,
+		Members:     members,
+		Annotations: map[string]string{
+			"github.com/team-slug": config.GitHub.Organization + "/" + team.Slug,
+		},
+	}
+	if team.Parent != "" {
+		group.Parent = catalog.ToIdentifier(team.Parent)
+	}
+	return group
+}
+
+// teamOwner resolves a CODEOWNERS team to its generated Group entity
+func teamOwner(handle string) (string, bool) {
+	identifier, ok := teamGroups[normalizeHandle(handle)]
+	if !ok {
+		return "", false
+	}
+	return harness.GroupRef(identifier), true
+}
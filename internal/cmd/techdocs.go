@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"harness-onboarder/internal/github"
+	"harness-onboarder/internal/models"
+)
+
+// techDocsRef points TechDocs at the mkdocs.yml in the repository root
+const techDocsRef = "dir:."
+
+// techDocsFiles returns the MkDocs skeleton added to onboarding PRs when TechDocs
+// scaffolding is enabled. Existing files are never replaced. Nil when disabled.
+func techDocsFiles(repo models.Repository) []github.PRFile {
+	if !config.Runtime.TechDocs {
+		return nil
+	}
+
+	description := repo.Description
+	if description == "" {
+		description = fmt.Sprintf("Documentation for %s.", repo.Name)
+	}
+
+	return []github.PRFile{
+		{Path: "mkdocs.yml", CreateOnly: true, Content: fmt.Sprintf(`site_name: %q
+site_description: %q
+repo_url: %s
+nav:
+  - Home: index.md
+plugins:
+  - techdocs-core
+`, repo.Name, description, repo.HTMLURL)},
+		{Path: "docs/index.md", CreateOnly: true, Content: fmt.Sprintf(`# %s
+
+%s
+
+## Getting started
+
+Describe how to build, run and test %s here.
+`, repo.Name, description, repo.Name)},
+	}
+}
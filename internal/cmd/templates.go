@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"text/template"
+
+	"harness-onboarder/internal/models"
+)
+
+// builtinTemplates are used for component types that have no template in the config file
+var builtinTemplates = map[string]models.ComponentTemplate{
+	"service": {
+		Annotations: map[string]string{
+			"backstage.io/kubernetes-id": "{{ if .HasKubernetes }}{{ .Name }}{{ end }}",
+		},
+	},
+	"library": {
+		Tags: []string{"library"},
+	},
+	"website": {
+		Links: []models.ComponentLink{
+			{Title: "Website", URL: "{{ .Homepage }}", Icon: "web", Type: "website"},
+		},
+	},
+	"infrastructure": {
+		Annotations: map[string]string{
+			"harness.io/iac-tool": "{{ .IaCTool }}",
+		},
+		Tags: []string{"{{ .IaCTool }}"},
+	},
+}
+
+var (
+	libraryKeywords = []string{"library", "lib", "sdk", "package", "plugin"}
+	websiteKeywords = []string{"website", "site", "web", "docs", "frontend", "landing"}
+)
+
+// inferComponentType guesses the component type from repository topics, name and
+// deployment signals. It returns an empty string when there is nothing to go on.
+func inferComponentType(repo models.Repository) string {
+	if repo.IaCTool != "" {
+		return "infrastructure"
+	}
+	if hasKeyword(repo, libraryKeywords) {
+		return "library"
+	}
+	if hasKeyword(repo, websiteKeywords) {
+		return "website"
+	}
+	if repo.HasDockerfile || repo.HasKubernetes {
+		return "service"
+	}
+	return ""
+}
+
+// hasKeyword reports whether any topic, or any dash-separated part of the
+// repository name, matches one of the keywords
+func hasKeyword(repo models.Repository, keywords []string) bool {
+	for _, topic := range repo.Topics {
+		if contains(keywords, strings.ToLower(topic)) {
+			return true
+		}
+	}
+	for _, part := range strings.Split(sanitizeName(repo.Name), "-") {
+		if contains(keywords, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoKind returns the entity kind generated for a repository: a matching rule's kind,
+// then Resource for Terraform and Pulumi projects, which describe infrastructure, then
+// the default kind
+func repoKind(repo models.Repository) string {
+	if kind := ruleActions(repo).Kind; kind != "" {
+		return kind
+	}
+	if repo.IaCTool != "" {
+		return "Resource"
+	}
+	if config.Defaults.Kind != "" {
+		return config.Defaults.Kind
+	}
+	return "Component"
+}
+
+// componentTemplate returns the template for a component type, preferring the config file
+func componentTemplate(componentType string) (models.ComponentTemplate, bool) {
+	if tmpl, ok := config.Templates[componentType]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := builtinTemplates[componentType]
+	return tmpl, ok
+}
+
+// applyTemplate adds the template annotations, tags and links for the component type.
+// Annotations already set are kept.
+func applyTemplate(repo models.Repository, componentType string, annotations map[string]string, tags []string, links []models.ComponentLink) ([]string, []models.ComponentLink) {
+	tmpl, ok := componentTemplate(componentType)
+	if !ok {
+		return tags, links
+	}
+
+	for key, value := range tmpl.Annotations {
+		if _, exists := annotations[key]; exists {
+			continue
+		}
+		if rendered := renderTemplateValue(repo, value); rendered != "" {
+			annotations[key] = rendered
+		}
+	}
+
+	for _, tag := range tmpl.Tags {
+		if tag = renderTemplateValue(repo, tag); tag != "" && !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, link := range tmpl.Links {
+		link.URL = renderTemplateValue(repo, link.URL)
+		if link.URL == "" {
+			continue
+		}
+		link.Title = renderTemplateValue(repo, link.Title)
+		links = append(links, link)
+	}
+
+	return tags, links
+}
+
+// renderTemplateValue renders a single template value against the repository
+func renderTemplateValue(repo models.Repository, value string) string {
+	if !strings.Contains(value, "{{") {
+		return value
+	}
+
+	tmpl, err := template.New("value").Parse(value)
+	if err != nil {
+		log.Printf("Warning: invalid template %q: %v", value, err)
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, repo); err != nil {
+		log.Printf("Warning: failed to render template %q for %s: %v", value, repo.FullName, err)
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// alreadyOnboardedMessage marks yaml mode results for repositories whose catalog file
+// is merged and whose component exists in IDP
+const alreadyOnboardedMessage = "Already onboarded (file exists in repo, component exists in IDP)"
+
+// topicPattern is GitHub's format for repository topics
+var topicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// isOnboarded reports whether a result means the repository is now in IDP, as opposed
+// to having a PR waiting for review
+func isOnboarded(result errors.ProcessingResult) bool {
+	if !result.Success {
+		return false
+	}
+	switch result.Action {
+	case "registered", "committed":
+		return true
+	case "created", "unchanged":
+		return config.Runtime.Mode == "api"
+	}
+	return result.Message == alreadyOnboardedMessage
+}
+
+// markOnboarded adds the configured onboarding topic to repositories that are now in
+// IDP. Failures are logged, since the onboarding itself succeeded.
+func markOnboarded(ctx context.Context, repo models.Repository, result errors.ProcessingResult) {
+	if config.Runtime.OnboardedTopic == "" || !isOnboarded(result) {
+		return
+	}
+	added, err := githubClient.AddTopic(ctx, repo, config.Runtime.OnboardedTopic)
+	if err != nil {
+		log.Printf("Warning: failed to tag %s with topic %s: %v", repo.FullName, config.Runtime.OnboardedTopic, err)
+		return
+	}
+	if added {
+		log.Printf("Tagged %s with topic %s", repo.FullName, config.Runtime.OnboardedTopic)
+	}
+}
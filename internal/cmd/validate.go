@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"harness-onboarder/internal/catalog"
+	"harness-onboarder/internal/models"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Lint every catalog-info.yaml in the organization",
+	Long: `Fetches the catalog-info.yaml file from each repository and checks required
+fields, identifier format, kinds, lifecycles and annotation syntax. Harness is
+not contacted and nothing is modified.`,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().Bool("strict", false, "Treat warnings as failures")
+	rootCmd.AddCommand(validateCmd)
+}
+
+// lintResult is the lint outcome for one repository's catalog file
+type lintResult struct {
+	Repository string
+	Path       string
+	Issues     []catalog.Issue
+	Err        error
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if config.GitHub.Organization == "" {
+		return fmt.Errorf("config validation failed: GitHub organization is required")
+	}
+
+	var err error
+	githubClient, err = newGitHubClient()
+	if err != nil {
+		return err
+	}
+
+	repos, err := discoverRepositories(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Linting catalog files in %d repositories", len(repos))
+
+	semaphore := make(chan struct{}, config.Runtime.Concurrency)
+	results := make(chan *lintResult, len(repos))
+
+	for _, repo := range repos {
+		go func(r models.Repository) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			throttle(ctx)
+			path, content, err := getCatalogInfoPathAndContent(ctx, r)
+			if err != nil {
+				if strings.Contains(err.Error(), "no catalog-info.yaml file found") {
+					results <- nil
+					return
+				}
+				results <- &lintResult{Repository: r.FullName, Err: err}
+				return
+			}
+			results <- &lintResult{Repository: r.FullName, Path: path, Issues: catalog.Lint(content)}
+		}(repo)
+	}
+
+	var lints []lintResult
+	for i := 0; i < len(repos); i++ {
+		if result := <-results; result != nil {
+			lints = append(lints, *result)
+		}
+	}
+
+	sort.Slice(lints, func(i, j int) bool {
+		return lints[i].Repository < lints[j].Repository
+	})
+
+	strict, _ := cmd.Flags().GetBool("strict")
+	failed := printLintReport(lints, len(repos), strict)
+	if failed > 0 {
+		return fmt.Errorf("%d catalog files failed validation", failed)
+	}
+
+	return nil
+}
+
+// printLintReport prints issues per repository and returns the number of failing files
+func printLintReport(lints []lintResult, total int, strict bool) int {
+	var failed, warned int
+
+	fmt.Printf("\n📝 Catalog Lint Report:\n")
+	for _, l := range lints {
+		if l.Err != nil {
+			failed++
+			fmt.Printf("   ❌ %s - %v\n", l.Repository, l.Err)
+			continue
+		}
+
+		status := "✅"
+		if catalog.HasErrors(l.Issues) || (strict && len(l.Issues) > 0) {
+			status = "❌"
+			failed++
+		} else if len(l.Issues) > 0 {
+			status = "⚠️ "
+			warned++
+		}
+
+		fmt.Printf("   %s %s (%s)\n", status, l.Repository, l.Path)
+		for _, issue := range l.Issues {
+			if issue.Document > 1 {
+				fmt.Printf("      └─ [doc %d] %s\n", issue.Document, issue)
+			} else {
+				fmt.Printf("      └─ %s\n", issue)
+			}
+		}
+	}
+
+	fmt.Printf("\n📊 Validation Summary:\n")
+	fmt.Printf("   Repositories scanned: %d\n", total)
+	fmt.Printf("   Catalog files found: %d\n", len(lints))
+	fmt.Printf("   Valid: %d\n", len(lints)-failed-warned)
+	fmt.Printf("   With warnings: %d\n", warned)
+	fmt.Printf("   Invalid: %d\n", failed)
+
+	return failed
+}
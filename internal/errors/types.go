@@ -1,8 +1,11 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
 // ErrorCategory represents different types of errors that can occur
@@ -43,9 +46,11 @@ const (
 	ErrorTypeInvalidIdentifier ErrorType = "INVALID_IDENTIFIER"
 	ErrorTypeMissingField      ErrorType = "MISSING_FIELD"
 	ErrorTypeInvalidValue      ErrorType = "INVALID_VALUE"
+	ErrorTypePolicyViolation   ErrorType = "POLICY_VIOLATION"
 	
 	// Network errors
 	ErrorTypeRateLimit     ErrorType = "RATE_LIMIT"
+	ErrorTypeSecondaryRateLimit ErrorType = "SECONDARY_RATE_LIMIT"
 	ErrorTypeTimeout       ErrorType = "TIMEOUT"
 	ErrorTypeConnectionFailed ErrorType = "CONNECTION_FAILED"
 	
@@ -53,6 +58,8 @@ const (
 	ErrorTypePRExists      ErrorType = "PR_EXISTS"
 	ErrorTypePRConflict    ErrorType = "PR_CONFLICT"
 	ErrorTypePRCreateFailed ErrorType = "PR_CREATE_FAILED"
+	ErrorTypeBranchProtected ErrorType = "BRANCH_PROTECTED"
+	ErrorTypePRBlocked      ErrorType = "PR_BLOCKED"
 	
 	// Unknown errors
 	ErrorTypeUnknown ErrorType = "UNKNOWN"
@@ -67,6 +74,7 @@ type ProcessingError struct {
 	Cause      error
 	Recoverable bool
 	UserFriendly string
+	RetryAfter  time.Duration // How long GitHub asked to wait, for secondary rate limits
 }
 
 func (e *ProcessingError) Error() string {
@@ -171,6 +179,32 @@ func NewPRExistsErrorWithTitle(repo string, prNumber int, title string, cause er
 	}
 }
 
+// NewBranchProtectedError creates an error for when branch protection blocks a direct commit
+func NewBranchProtectedError(repo, branch string, cause error) *ProcessingError {
+	return &ProcessingError{
+		Category:     ErrorCategoryPR,
+		Type:         ErrorTypeBranchProtected,
+		Message:      fmt.Sprintf("branch %s is protected against direct commits", branch),
+		Repository:   repo,
+		Cause:        cause,
+		Recoverable:  false,
+		UserFriendly: fmt.Sprintf("Branch '%s' in '%s' requires pull requests or status checks. Run without --commit-direct to open a PR instead.", branch, repo),
+	}
+}
+
+// NewPRBlockedError creates an error for repositories the app can't open PRs in, with
+// a hint on how to fix it
+func NewPRBlockedError(repo, reason, remediation string) *ProcessingError {
+	return &ProcessingError{
+		Category:     ErrorCategoryPR,
+		Type:         ErrorTypePRBlocked,
+		Message:      reason,
+		Repository:   repo,
+		Recoverable:  false,
+		UserFriendly: fmt.Sprintf("Can't open an onboarding PR in '%s': %s. %s", repo, reason, remediation),
+	}
+}
+
 // NewUnauthorizedError creates an error for authentication issues
 func NewUnauthorizedError(message string, cause error) *ProcessingError {
 	return &ProcessingError{
@@ -195,6 +229,31 @@ func NewRateLimitError(cause error) *ProcessingError {
 	}
 }
 
+// NewSecondaryRateLimitError creates an error for GitHub's secondary rate limit, which
+// blocks bursts of requests such as creating many PRs quickly. retryAfter is GitHub's
+// Retry-After, or 0 when it didn't send one.
+func NewSecondaryRateLimitError(repo string, retryAfter time.Duration, cause error) *ProcessingError {
+	return &ProcessingError{
+		Category:     ErrorCategoryNetwork,
+		Type:         ErrorTypeSecondaryRateLimit,
+		Message:      "secondary rate limit exceeded",
+		Repository:   repo,
+		Cause:        cause,
+		Recoverable:  true,
+		RetryAfter:   retryAfter,
+		UserFriendly: fmt.Sprintf("GitHub's secondary rate limit blocked changes to '%s'. It's retried once GitHub allows it; lower --concurrency if this keeps happening.", repo),
+	}
+}
+
+// retryAfter returns the Retry-After of a GitHub abuse rate limit error in the chain
+func retryAfter(err error) time.Duration {
+	var limited interface{ GetRetryAfter() time.Duration }
+	if stderrors.As(err, &limited) {
+		return limited.GetRetryAfter()
+	}
+	return 0
+}
+
 // CategorizeError analyzes an error and returns a structured ProcessingError
 func CategorizeError(err error, repo string) *ProcessingError {
 	if err == nil {
@@ -211,11 +270,14 @@ func CategorizeError(err error, repo string) *ProcessingError {
 	
 	errMsg := strings.ToLower(err.Error())
 	
-	// GitHub API errors
+	// GitHub API errors. Secondary rate limits are 403s too, so they're checked first.
+	if strings.Contains(errMsg, "secondary rate limit") || strings.Contains(errMsg, "abuse detection") {
+		return NewSecondaryRateLimitError(repo, retryAfter(err), err)
+	}
 	if strings.Contains(errMsg, "404") && strings.Contains(errMsg, "not found") {
 		return NewRepositoryNotFoundError(repo, err)
 	}
-	if strings.Contains(errMsg, "403") && strings.Contains(errMsg, "forbidden") {
+	if strings.Contains(errMsg, "403") && (strings.Contains(errMsg, "forbidden") || strings.Contains(errMsg, "resource not accessible")) {
 		return &ProcessingError{
 			Category:     ErrorCategoryAuthentication,
 			Type:         ErrorTypeForbidden,
@@ -270,7 +332,10 @@ type ProcessingResult struct {
 	Error      *ProcessingError
 	Message    string
 	Skipped    bool
-	Action     string // "created", "updated", "skipped", "failed"
+	Action     string             // "created", "updated", "skipped", "blocked", "failed"
+	Identifier string             // IDP entity identifier, when known
+	URL        string             // Pull request or entity link, when one was created
+	Scores     map[string]float64 // Scorecard scores by scorecard name, when fetched
 }
 
 // ErrorSummary provides a summary of all errors encountered
@@ -279,6 +344,7 @@ type ErrorSummary struct {
 	ByCategory map[ErrorCategory]int
 	ByType     map[ErrorType]int
 	Recoverable int
+	Blocked    int // repositories the app isn't allowed to change; not counted in Total
 	Results    []ProcessingResult
 }
 
@@ -295,6 +361,11 @@ func NewErrorSummary() *ErrorSummary {
 func (s *ErrorSummary) AddResult(result ProcessingResult) {
 	s.Results = append(s.Results, result)
 	
+	if result.Action == "blocked" {
+		s.Blocked++
+		return
+	}
+	
 	if result.Error != nil {
 		s.Total++
 		s.ByCategory[result.Error.Category]++
@@ -308,15 +379,18 @@ func (s *ErrorSummary) AddResult(result ProcessingResult) {
 
 // PrintSummary prints a formatted summary of all errors
 func (s *ErrorSummary) PrintSummary() {
-	if s.Total == 0 {
+	if s.Total == 0 && s.Blocked == 0 {
 		fmt.Println("✅ All repositories processed successfully!")
 		return
 	}
 	
 	fmt.Printf("\n📊 Processing Summary:\n")
 	fmt.Printf("   Total repositories: %d\n", len(s.Results))
-	fmt.Printf("   Successful: %d\n", len(s.Results)-s.Total)
+	fmt.Printf("   Successful: %d\n", len(s.Results)-s.Total-s.Blocked)
 	fmt.Printf("   Failed: %d\n", s.Total)
+	if s.Blocked > 0 {
+		fmt.Printf("   Blocked: %d\n", s.Blocked)
+	}
 	fmt.Printf("   Recoverable errors: %d\n", s.Recoverable)
 	
 	if len(s.ByCategory) > 0 {
@@ -329,7 +403,9 @@ func (s *ErrorSummary) PrintSummary() {
 	fmt.Printf("\n📝 Detailed Results:\n")
 	for _, result := range s.Results {
 		status := "✅"
-		if result.Error != nil {
+		if result.Action == "blocked" {
+			status = "🔒"
+		} else if result.Error != nil {
 			if result.Error.Recoverable {
 				status = "⚠️ "
 			} else {
@@ -343,5 +419,26 @@ func (s *ErrorSummary) PrintSummary() {
 		if result.Error != nil {
 			fmt.Printf("      └─ %s\n", result.Error.GetUserFriendlyMessage())
 		}
+		if result.Scores != nil {
+			fmt.Printf("      └─ Scorecards: %s\n", FormatScores(result.Scores))
+		}
+	}
+}
+// FormatScores renders scorecard scores as "name: score" pairs sorted by name, or
+// notes that none have been computed yet
+func FormatScores(scores map[string]float64) string {
+	if len(scores) == 0 {
+		return "not computed yet"
 	}
-}
\ No newline at end of file
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %.0f", name, scores[name])
+	}
+	return strings.Join(parts, ", ")
+}
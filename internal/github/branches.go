@@ -0,0 +1,182 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// DefaultBranchTemplate names onboarding branches when no template is configured
+const DefaultBranchTemplate = "harness-onboarding-{{ .Timestamp }}"
+
+// BranchTemplateData is what branch name templates are rendered against
+type BranchTemplateData struct {
+	models.Repository
+	Timestamp int64 // Unix time, making every run's branch unique
+}
+
+func parseBranchTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultBranchTemplate
+	}
+	tmpl, err := template.New("branch").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid branch template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// onboardingBranch renders the branch name for an onboarding PR
+func (c *Client) onboardingBranch(repo models.Repository) (string, error) {
+	var buf bytes.Buffer
+	data := BranchTemplateData{Repository: repo, Timestamp: time.Now().Unix()}
+	if err := c.branchTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render branch name: %w", err)
+	}
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", fmt.Errorf("branch template rendered an empty name for %s", repo.FullName)
+	}
+	return name, nil
+}
+
+// branchIsDeterministic reports whether reruns produce the same branch name, in which
+// case an existing branch is reused rather than treated as a conflict
+func (c *Client) branchIsDeterministic() bool {
+	return c.config.BranchTemplate != "" && !strings.Contains(c.config.BranchTemplate, ".Timestamp")
+}
+
+// baseBranch is the branch onboarding changes target: the repository's own override,
+// then the configured base branch, then the repository's default branch
+func (c *Client) baseBranch(repo models.Repository) string {
+	if repo.BaseBranch != "" {
+		return repo.BaseBranch
+	}
+	if c.config.BaseBranch != "" {
+		return c.config.BaseBranch
+	}
+	return repo.DefaultBranch
+}
+
+// discardBranch deletes a branch created for a pull request that couldn't be opened,
+// so a retry doesn't leave it behind next to its own
+func (c *Client) discardBranch(ctx context.Context, repo models.Repository, branchName string) {
+	if err := c.DeleteBranch(ctx, repo, branchName); err != nil {
+		log.Printf("Warning: failed to delete branch %s in %s: %v", branchName, repo.FullName, err)
+	}
+}
+
+// resetBranch points the branch at the head of the base branch, creating it if it
+// doesn't exist. A branch with an open pull request is left alone.
+func (c *Client) resetBranch(ctx context.Context, repo models.Repository, branchName string) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := c.client.Git.GetRef(ctx, owner, repoName, "heads/"+branchName)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return c.createBranch(ctx, repo, branchName)
+		}
+		return fmt.Errorf("failed to get branch %s: %w", branchName, err)
+	}
+
+	prs, _, err := c.client.PullRequests.List(ctx, owner, repoName, &github.PullRequestListOptions{
+		State: "open",
+		Head:  owner + ":" + branchName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests for %s: %w", branchName, err)
+	}
+	if len(prs) > 0 {
+		return errors.NewPRExistsError(repo.FullName, prs[0].GetNumber(), fmt.Errorf("branch %s has open PR #%d", branchName, prs[0].GetNumber()))
+	}
+
+	base, _, err := c.client.Repositories.GetBranch(ctx, owner, repoName, c.baseBranch(repo), true)
+	if err != nil {
+		return fmt.Errorf("failed to get base branch: %w", err)
+	}
+
+	_, _, err = c.client.Git.UpdateRef(ctx, owner, repoName, &github.Reference{
+		Ref:    github.String("refs/heads/" + branchName),
+		Object: &github.GitObject{SHA: base.Commit.SHA},
+	}, true)
+	if err != nil {
+		return fmt.Errorf("failed to reset branch %s: %w", branchName, err)
+	}
+	return nil
+}
+
+// rebaseIfStale rebuilds a PR's branch on top of the base branch when it has fallen
+// behind or has conflicts: the files the PR changes are read from its current head and
+// committed again, as one commit, onto a branch reset to the base branch's head
+func (c *Client) rebaseIfStale(ctx context.Context, repo models.Repository, pr *github.PullRequest) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+	branchName := pr.GetHead().GetRef()
+	headSHA := pr.GetHead().GetSHA()
+	base := c.baseBranch(repo)
+
+	if pr.GetMergeableState() != "dirty" {
+		comparison, _, err := c.client.Repositories.CompareCommits(ctx, owner, repoName, base, headSHA, nil)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s with %s: %w", branchName, base, err)
+		}
+		if comparison.GetBehindBy() == 0 {
+			return nil
+		}
+	}
+
+	files, _, err := c.client.PullRequests.ListFiles(ctx, owner, repoName, pr.GetNumber(), &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return fmt.Errorf("failed to list files of PR #%d: %w", pr.GetNumber(), err)
+	}
+	var changes []fileChange
+	for _, file := range files {
+		if file.GetStatus() == "removed" {
+			continue
+		}
+		content, _, _, err := c.client.Repositories.GetContents(ctx, owner, repoName, file.GetFilename(), &github.RepositoryContentGetOptions{Ref: headSHA})
+		if err != nil {
+			return fmt.Errorf("failed to get %s from PR #%d: %w", file.GetFilename(), pr.GetNumber(), err)
+		}
+		text, err := content.GetContent()
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", file.GetFilename(), err)
+		}
+		changes = append(changes, fileChange{Path: file.GetFilename(), Content: text})
+	}
+
+	baseHead, _, err := c.client.Repositories.GetBranch(ctx, owner, repoName, base, true)
+	if err != nil {
+		return fmt.Errorf("failed to get base branch: %w", err)
+	}
+	_, _, err = c.client.Git.UpdateRef(ctx, owner, repoName, &github.Reference{
+		Ref:    github.String("refs/heads/" + branchName),
+		Object: &github.GitObject{SHA: baseHead.Commit.SHA},
+	}, true)
+	if err != nil {
+		return fmt.Errorf("failed to reset branch %s: %w", branchName, err)
+	}
+
+	if len(changes) > 0 {
+		if _, err := c.commitFiles(ctx, repo, branchName, changes, "Rebase Harness IDP onboarding onto "+base); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Rebuilt branch %s of PR #%d in %s on top of %s", branchName, pr.GetNumber(), repo.FullName, base)
+	return nil
+}
@@ -0,0 +1,326 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// CatalogFile is one generated catalog file destined for the central catalog repository
+type CatalogFile struct {
+	Path       string
+	Content    string
+	Repository string // full name of the repository the file describes
+}
+
+// CreateCatalogRepoPR opens a single pull request in the central catalog repository
+// (owner/name) adding or updating every file whose content differs from the default
+// branch, all in one commit. A non-empty batch names the group of repositories the PR
+// covers and keeps its branch apart from other batches in the same run. While an
+// earlier PR for the batch is still open, files that differ from its head are
+// committed to it instead of opening another one. It returns the PR URL and the files
+// it changed; an empty URL with no error means everything was already up to date.
+func (c *Client) CreateCatalogRepoPR(ctx context.Context, catalogRepo, batch string, files []CatalogFile) (string, []CatalogFile, error) {
+	owner, repoName, err := parseFullName(catalogRepo)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ghRepo, _, err := c.client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get catalog repository %s: %w", catalogRepo, err)
+	}
+	repo := basicRepository(ghRepo)
+	repo.BaseBranch = repo.DefaultBranch // the configured base branch is for onboarded repositories
+
+	changed, err := c.catalogFilesChanged(ctx, owner, repoName, "", files)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(changed) == 0 {
+		log.Printf("Catalog files in %s are already up to date, skipping", catalogRepo)
+		return "", nil, nil
+	}
+
+	open, err := c.openCatalogRepoPR(ctx, owner, repoName, catalogRepo, batch)
+	if err != nil {
+		return "", nil, err
+	}
+	if open != nil {
+		if err := c.updateCatalogRepoPR(ctx, repo, open, batch, changed); err != nil {
+			return "", nil, err
+		}
+		return open.GetHTMLURL(), changed, nil
+	}
+
+	branchName, err := c.onboardingBranch(repo)
+	if err != nil {
+		return "", nil, err
+	}
+	if batch != "" {
+		branchName += "-" + branchSafe(batch)
+	}
+	if c.branchIsDeterministic() {
+		err = c.resetBranch(ctx, repo, branchName)
+	} else {
+		err = c.createBranch(ctx, repo, branchName)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	// A deterministic branch is reset and reused by the next attempt
+	opened := false
+	defer func() {
+		if !opened && !c.branchIsDeterministic() {
+			c.discardBranch(ctx, repo, branchName)
+		}
+	}()
+
+	changes := make([]fileChange, 0, len(changed))
+	for _, file := range changed {
+		changes = append(changes, fileChange{Path: file.Path, Content: file.Content})
+	}
+	message := fmt.Sprintf("Add Harness IDP catalog entries for %d repositories", len(changed))
+	if _, err := c.commitFiles(ctx, repo, branchName, changes, message); err != nil {
+		return "", nil, err
+	}
+
+	prTitle, prBody := catalogRepoPRText(batch, changed)
+	pr, _, err := c.client.PullRequests.Create(ctx, owner, repoName, &github.NewPullRequest{
+		Title: &prTitle,
+		Head:  &branchName,
+		Base:  &repo.BaseBranch,
+		Body:  &prBody,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+	opened = true
+
+	c.decoratePR(ctx, owner, repoName, pr.GetNumber())
+
+	log.Printf("Created catalog PR #%d in %s for %d repositories: %s", pr.GetNumber(), catalogRepo, len(changed), pr.GetHTMLURL())
+	return pr.GetHTMLURL(), changed, nil
+}
+
+// branchSafe reduces s to characters that are safe in a branch name
+func branchSafe(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// catalogFilesChanged returns the files whose content differs from ref, or from the
+// default branch when ref is empty
+func (c *Client) catalogFilesChanged(ctx context.Context, owner, repoName, ref string, files []CatalogFile) ([]CatalogFile, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+
+	var changed []CatalogFile
+	for _, file := range files {
+		current, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, file.Path, opts)
+		if err != nil {
+			if resp == nil || resp.StatusCode != 404 {
+				return nil, fmt.Errorf("failed to check %s: %w", file.Path, err)
+			}
+			changed = append(changed, file)
+			continue
+		}
+		content, err := current.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get existing content of %s: %w", file.Path, err)
+		}
+		if strings.TrimSpace(content) != strings.TrimSpace(file.Content) {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+// catalogBatchMarker is a hidden comment identifying the batch a catalog repository
+// PR was opened for
+func catalogBatchMarker(batch string) string {
+	if batch == "" {
+		return "<!-- harness-onboarder-catalog-batch -->"
+	}
+	return fmt.Sprintf("<!-- harness-onboarder-catalog-batch: %s -->", branchSafe(batch))
+}
+
+// openCatalogRepoPR returns the open PR an earlier run raised for the batch from a
+// branch of the catalog repository itself, or nil when there is none
+func (c *Client) openCatalogRepoPR(ctx context.Context, owner, repoName, catalogRepo, batch string) (*github.PullRequest, error) {
+	prs, err := c.openPRsByBranch(ctx, owner, repoName)
+	if err != nil {
+		return nil, err
+	}
+	marker := catalogBatchMarker(batch)
+	for _, pr := range prs {
+		if pr.GetHead().GetRepo().GetFullName() == catalogRepo && strings.Contains(pr.GetBody(), marker) {
+			log.Printf("Found open catalog PR #%d in %s", pr.GetNumber(), catalogRepo)
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
+
+// updateCatalogRepoPR commits the files that differ from an open catalog PR's head to
+// its branch and refreshes its title and body to list every file it now carries
+func (c *Client) updateCatalogRepoPR(ctx context.Context, repo models.Repository, pr *github.PullRequest, batch string, changed []CatalogFile) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+	branchName := pr.GetHead().GetRef()
+
+	pending, err := c.catalogFilesChanged(ctx, owner, repoName, branchName, changed)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		log.Printf("Open catalog PR #%d in %s already has these files", pr.GetNumber(), repo.FullName)
+		return nil
+	}
+
+	changes := make([]fileChange, 0, len(pending))
+	for _, file := range pending {
+		changes = append(changes, fileChange{Path: file.Path, Content: file.Content})
+	}
+	message := fmt.Sprintf("Update Harness IDP catalog entries for %d repositories", len(pending))
+	if _, err := c.commitFiles(ctx, repo, branchName, changes, message); err != nil {
+		return err
+	}
+
+	title, body := catalogRepoPRText(batch, changed)
+	if _, _, err := c.client.PullRequests.Edit(ctx, owner, repoName, pr.GetNumber(), &github.PullRequest{Title: &title, Body: &body}); err != nil {
+		return fmt.Errorf("failed to update PR #%d: %w", pr.GetNumber(), err)
+	}
+
+	log.Printf("Updated catalog PR #%d in %s with %d files", pr.GetNumber(), repo.FullName, len(pending))
+	return nil
+}
+
+// catalogRepoPRText returns the title and body of a catalog repository PR
+func catalogRepoPRText(batch string, files []CatalogFile) (string, string) {
+	title := fmt.Sprintf("Harness IDP catalog entries for %d repositories", len(files))
+	if batch != "" {
+		title += fmt.Sprintf(" (%s)", batch)
+	}
+	return title, withMarker(catalogRepoPRBody(files)) + "\n" + catalogBatchMarker(batch)
+}
+
+func catalogRepoPRBody(files []CatalogFile) string {
+	var b strings.Builder
+	b.WriteString("This PR adds or updates Harness IDP catalog entries for the following repositories:\n\n")
+	for _, file := range files {
+		fmt.Fprintf(&b, "- %s (`%s`)\n", file.Repository, file.Path)
+	}
+	b.WriteString("\nOnce merged, register the entries with `--mode register --catalog-repo`.\n\nAuto-generated by harness-onboarder tool.")
+	return b.String()
+}
+
+// commitFiles writes all changes to the branch as a single commit on top of its head
+// and returns the new commit
+func (c *Client) commitFiles(ctx context.Context, repo models.Repository, branchName string, changes []fileChange, message string) (*github.Commit, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, _, err := c.client.Git.GetRef(ctx, owner, repoName, "heads/"+branchName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch %s: %w", branchName, err)
+	}
+	parent, _, err := c.client.Git.GetCommit(ctx, owner, repoName, ref.Object.GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head commit of %s: %w", branchName, err)
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(changes))
+	for _, change := range changes {
+		entries = append(entries, &github.TreeEntry{
+			Path:    github.String(change.Path),
+			Mode:    github.String("100644"),
+			Type:    github.String("blob"),
+			Content: github.String(change.Content),
+		})
+	}
+	tree, _, err := c.client.Git.CreateTree(ctx, owner, repoName, parent.Tree.GetSHA(), entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	commit := &github.Commit{
+		Message: github.String(message),
+		Tree:    tree,
+		Parents: []*github.Commit{{SHA: parent.SHA}},
+	}
+	if author := c.commitAuthor(); author != nil {
+		commit.Author = author
+		commit.Committer = author
+	}
+	created, _, err := c.client.Git.CreateCommit(ctx, owner, repoName, commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	ref.Object.SHA = created.SHA
+	if _, _, err := c.client.Git.UpdateRef(ctx, owner, repoName, ref, false); err != nil {
+		return nil, fmt.Errorf("failed to update branch %s: %w", branchName, err)
+	}
+	return created, nil
+}
+
+// ListCatalogRepoFiles returns the YAML files directly under dir on the default branch
+// of the central catalog repository, keyed by path, along with that branch's name
+func (c *Client) ListCatalogRepoFiles(ctx context.Context, catalogRepo, dir string) (string, map[string]string, error) {
+	owner, repoName, err := parseFullName(catalogRepo)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ghRepo, _, err := c.client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get catalog repository %s: %w", catalogRepo, err)
+	}
+
+	_, entries, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, dir, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return ghRepo.GetDefaultBranch(), map[string]string{}, nil
+		}
+		return "", nil, fmt.Errorf("failed to list %s in %s: %w", dir, catalogRepo, err)
+	}
+
+	files := make(map[string]string)
+	for _, entry := range entries {
+		ext := path.Ext(entry.GetName())
+		if entry.GetType() != "file" || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		file, _, _, err := c.client.Repositories.GetContents(ctx, owner, repoName, entry.GetPath(), nil)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get %s: %w", entry.GetPath(), err)
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to decode %s: %w", entry.GetPath(), err)
+		}
+		files[entry.GetPath()] = content
+	}
+	return ghRepo.GetDefaultBranch(), files, nil
+}
@@ -0,0 +1,173 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// onboardingBranchPrefixes are the prefixes of branches created for onboarding and
+// migration PRs
+var onboardingBranchPrefixes = []string{"harness-onboarding-", "harness-idp-migration-"}
+
+// OnboardingBranch is a branch left by an onboarding or migration run
+type OnboardingBranch struct {
+	Name      string
+	CreatedAt time.Time // from the timestamp in the name, else the head commit date
+	PRNumber  int       // open PR from the branch, 0 when there is none
+	PRURL     string
+}
+
+// ListOnboardingBranches returns the repository's onboarding branches with the open
+// PR, if any, raised from each
+func (c *Client) ListOnboardingBranches(ctx context.Context, repo models.Repository) ([]OnboardingBranch, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*github.Reference
+	for _, prefix := range c.onboardingRefPrefixes() {
+		matching, err := c.listMatchingRefs(ctx, owner, repoName, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+		refs = append(refs, matching...)
+	}
+
+	openPRs, err := c.openPRsByBranch(ctx, owner, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []OnboardingBranch
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref.GetRef(), "refs/heads/")
+		if seen[name] || !c.isOnboardingBranch(name) {
+			continue
+		}
+		seen[name] = true
+
+		createdAt := branchTimestamp(name)
+		if createdAt.IsZero() {
+			commit, _, err := c.client.Git.GetCommit(ctx, owner, repoName, ref.GetObject().GetSHA())
+			if err != nil {
+				log.Printf("Warning: failed to get head commit of %s in %s: %v", name, repo.FullName, err)
+			} else {
+				createdAt = commit.GetCommitter().GetDate().Time
+			}
+		}
+
+		branch := OnboardingBranch{Name: name, CreatedAt: createdAt}
+		if pr, ok := openPRs[name]; ok {
+			branch.PRNumber = pr.GetNumber()
+			branch.PRURL = pr.GetHTMLURL()
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
+
+// onboardingRefPrefixes are the ref prefixes onboarding branches are listed under:
+// the built-in prefixes and the literal start of --branch-template
+func (c *Client) onboardingRefPrefixes() []string {
+	prefixes := []string{"heads/harness-"}
+	if prefix, _, _ := strings.Cut(c.config.BranchTemplate, "{{"); prefix != "" && !strings.HasPrefix(prefix, "harness-") {
+		prefixes = append(prefixes, "heads/"+prefix)
+	}
+	return prefixes
+}
+
+// listMatchingRefs returns every ref starting with prefix, following pagination
+func (c *Client) listMatchingRefs(ctx context.Context, owner, repoName, prefix string) ([]*github.Reference, error) {
+	var refs []*github.Reference
+	opts := &github.ReferenceListOptions{Ref: prefix, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.client.Git.ListMatchingRefs(ctx, owner, repoName, opts)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, page...)
+		if resp.NextPage == 0 {
+			return refs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// branchTimestamp returns the Unix timestamp suffix an onboarding branch with a
+// built-in prefix was created with, or the zero time when it has none
+func branchTimestamp(name string) time.Time {
+	for _, prefix := range onboardingBranchPrefixes {
+		if suffix, ok := strings.CutPrefix(name, prefix); ok {
+			if unix, err := strconv.ParseInt(suffix, 10, 64); err == nil {
+				return time.Unix(unix, 0)
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Client) openPRsByBranch(ctx context.Context, owner, repoName string) (map[string]*github.PullRequest, error) {
+	prs := make(map[string]*github.PullRequest)
+	opts := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.client.PullRequests.List(ctx, owner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		for _, pr := range page {
+			prs[pr.GetHead().GetRef()] = pr
+		}
+		if resp.NextPage == 0 {
+			return prs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// ClosePR comments on and closes a pull request
+func (c *Client) ClosePR(ctx context.Context, repo models.Repository, number int, comment string) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	if comment != "" {
+		_, _, err = c.client.Issues.CreateComment(ctx, owner, repoName, number, &github.IssueComment{
+			Body: github.String(comment),
+		})
+		if err != nil {
+			log.Printf("Warning: failed to comment on PR #%d in %s: %v", number, repo.FullName, err)
+		}
+	}
+
+	_, _, err = c.client.PullRequests.Edit(ctx, owner, repoName, number, &github.PullRequest{
+		State: github.String("closed"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the repository
+func (c *Client) DeleteBranch(ctx context.Context, repo models.Repository, name string) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+	if _, err := c.client.Git.DeleteRef(ctx, owner, repoName, "heads/"+name); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", name, err)
+	}
+	return nil
+}
@@ -13,27 +13,54 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v50/github"
 
 	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/httpcache"
+	"harness-onboarder/internal/httplog"
+	"harness-onboarder/internal/network"
 	"harness-onboarder/internal/models"
 )
 
 type Client struct {
-	client *github.Client
-	config models.GitHubConfig
+	client    *github.Client
+	config    models.GitHubConfig
+	transport *ghinstallation.Transport
+
+	// Optional templates for onboarding PR titles and bodies
+	prTitleTemplate *template.Template
+	prBodyTemplate  *template.Template
+	branchTemplate  *template.Template
+
+	// Installation permissions, read once for PR preflight checks
+	permissionsOnce        sync.Once
+	contentsPermission     string
+	pullRequestsPermission string
+	permissionsErr         error
+
+	// Catalog file locations recorded from the tree during enrichment
+	catalogPaths []string
 }
 
 func NewClient(config models.GitHubConfig) (*Client, error) {
 	var transport *ghinstallation.Transport
 	var err error
 
+	// Every GitHub call, including installation token refreshes, is paced from the
+	// rate limit headers GitHub returns
+	rateLimited := &rateLimitTransport{
+		base:    httpcache.Wrap(httplog.Wrap(network.Transport(), "github")),
+		limiter: newRateLimiter(),
+	}
+
 	if strings.HasPrefix(config.PrivateKey, "/") || strings.Contains(config.PrivateKey, ".pem") {
 		transport, err = ghinstallation.NewKeyFromFile(
-			http.DefaultTransport,
+			rateLimited,
 			config.AppID,
 			config.InstallID,
 			config.PrivateKey,
@@ -44,7 +71,7 @@ func NewClient(config models.GitHubConfig) (*Client, error) {
 			return nil, fmt.Errorf("failed to parse private key: %w", parseErr)
 		}
 		transport, err = ghinstallation.New(
-			http.DefaultTransport,
+			rateLimited,
 			config.AppID,
 			config.InstallID,
 			privateKeyBytes,
@@ -57,12 +84,39 @@ func NewClient(config models.GitHubConfig) (*Client, error) {
 
 	client := github.NewClient(&http.Client{Transport: transport})
 
+	prTitleTemplate, err := loadPRTemplate("title", config.PRTitleTemplate)
+	if err != nil {
+		return nil, err
+	}
+	prBodyTemplate, err := loadPRTemplate("body", config.PRBodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	branchTemplate, err := parseBranchTemplate(config.BranchTemplate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
-		client: client,
-		config: config,
+		client:          client,
+		config:          config,
+		transport:       transport,
+		prTitleTemplate: prTitleTemplate,
+		prBodyTemplate:  prBodyTemplate,
+		branchTemplate:  branchTemplate,
 	}, nil
 }
 
+// PrivateKeyPEM returns the app's private key, read from a file path or decoded from
+// inline PEM or base64, the same way NewClient loads it
+func PrivateKeyPEM(config models.GitHubConfig) ([]byte, error) {
+	if strings.HasPrefix(config.PrivateKey, "/") || strings.Contains(config.PrivateKey, ".pem") {
+		return ioutil.ReadFile(config.PrivateKey)
+	}
+	return parsePrivateKeyBytes(config.PrivateKey)
+}
+
 func parsePrivateKeyBytes(key string) ([]byte, error) {
 	var keyBytes []byte
 	var err error
@@ -144,10 +198,54 @@ func (c *Client) DiscoverRepositoriesWithOptions(ctx context.Context, org string
 	
 	log.Printf("DEBUG: Starting full repository discovery for: %s", org)
 	
+	err := c.listRepositoryPages(ctx, org, func(repos []*github.Repository) error {
+		for _, repo := range repos {
+			var modelRepo models.Repository
+			var err error
+			
+			if enrich {
+				log.Printf("DEBUG: Enriching repository: %s", repo.GetFullName())
+				modelRepo, err = c.enrichRepository(ctx, repo)
+				if err != nil {
+					log.Printf("Warning: failed to enrich repository %s: %v", repo.GetFullName(), err)
+					continue
+				}
+				log.Printf("DEBUG: Successfully enriched repository: %s", repo.GetFullName())
+			} else {
+				// Create minimal repository model without enrichment
+				modelRepo = basicRepository(repo)
+			}
+
+			allRepos = append(allRepos, modelRepo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allRepos, nil
+}
+
+// StreamRepositories calls fn with each page of the organization's repositories, without
+// enrichment, as soon as it's listed. Listing stops at the first error fn returns.
+func (c *Client) StreamRepositories(ctx context.Context, org string, fn func([]models.Repository) error) error {
+	return c.listRepositoryPages(ctx, org, func(repos []*github.Repository) error {
+		page := make([]models.Repository, 0, len(repos))
+		for _, repo := range repos {
+			page = append(page, basicRepository(repo))
+		}
+		return fn(page)
+	})
+}
+
+// listRepositoryPages lists the repositories of an organization, or those the app is
+// installed on for a user account, calling fn with each page
+func (c *Client) listRepositoryPages(ctx context.Context, org string, fn func([]*github.Repository) error) error {
 	// First try to get the user/org to determine if it's a user or organization
 	user, _, err := c.client.Users.Get(ctx, org)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user/org info: %w", err)
+		return fmt.Errorf("failed to get user/org info: %w", err)
 	}
 	
 	isOrg := user.GetType() == "Organization"
@@ -166,54 +264,12 @@ func (c *Client) DiscoverRepositoriesWithOptions(ctx context.Context, org string
 		for {
 			repos, resp, err := c.client.Repositories.ListByOrg(ctx, org, opts)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list repositories: %w", err)
+				return fmt.Errorf("failed to list repositories: %w", err)
 			}
 
 			log.Printf("DEBUG: Retrieved %d repositories from API", len(repos))
-			for _, repo := range repos {
-				if repo == nil {
-					continue
-				}
-
-				var modelRepo models.Repository
-				var err error
-				
-				if enrich {
-					log.Printf("DEBUG: Enriching repository: %s", repo.GetFullName())
-					modelRepo, err = c.enrichRepository(ctx, repo)
-					if err != nil {
-						log.Printf("Warning: failed to enrich repository %s: %v", repo.GetFullName(), err)
-						continue
-					}
-					log.Printf("DEBUG: Successfully enriched repository: %s", repo.GetFullName())
-				} else {
-					// Create minimal repository model without enrichment
-					modelRepo = models.Repository{
-						ID:            repo.GetID(),
-						Name:          repo.GetName(),
-						FullName:      repo.GetFullName(),
-						Description:   repo.GetDescription(),
-						HTMLURL:       repo.GetHTMLURL(),
-						CloneURL:      repo.GetCloneURL(),
-						Language:      repo.GetLanguage(),
-						Topics:        repo.Topics,
-						Private:       repo.GetPrivate(),
-						Archived:      repo.GetArchived(),
-						CreatedAt:     repo.GetCreatedAt().Time,
-						UpdatedAt:     repo.GetUpdatedAt().Time,
-						PushedAt:      repo.GetPushedAt().Time,
-						DefaultBranch: repo.GetDefaultBranch(),
-						Stars:         repo.GetStargazersCount(),
-						Forks:         repo.GetForksCount(),
-						OpenIssues:    repo.GetOpenIssuesCount(),
-						Metadata:      make(map[string]string),
-					}
-					if repo.GetLicense() != nil {
-						modelRepo.License = repo.GetLicense().GetName()
-					}
-				}
-
-				allRepos = append(allRepos, modelRepo)
+			if err := fn(nonNilRepositories(repos)); err != nil {
+				return err
 			}
 
 			if resp.NextPage == 0 {
@@ -230,53 +286,11 @@ func (c *Client) DiscoverRepositoriesWithOptions(ctx context.Context, org string
 		for {
 			installationRepos, resp, err := c.client.Apps.ListRepos(ctx, opts)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list repositories: %w", err)
+				return fmt.Errorf("failed to list repositories: %w", err)
 			}
 
-			for _, repo := range installationRepos.Repositories {
-				if repo == nil {
-					continue
-				}
-
-				var modelRepo models.Repository
-				var err error
-				
-				if enrich {
-					log.Printf("DEBUG: Enriching repository: %s", repo.GetFullName())
-					modelRepo, err = c.enrichRepository(ctx, repo)
-					if err != nil {
-						log.Printf("Warning: failed to enrich repository %s: %v", repo.GetFullName(), err)
-						continue
-					}
-					log.Printf("DEBUG: Successfully enriched repository: %s", repo.GetFullName())
-				} else {
-					// Create minimal repository model without enrichment
-					modelRepo = models.Repository{
-						ID:            repo.GetID(),
-						Name:          repo.GetName(),
-						FullName:      repo.GetFullName(),
-						Description:   repo.GetDescription(),
-						HTMLURL:       repo.GetHTMLURL(),
-						CloneURL:      repo.GetCloneURL(),
-						Language:      repo.GetLanguage(),
-						Topics:        repo.Topics,
-						Private:       repo.GetPrivate(),
-						Archived:      repo.GetArchived(),
-						CreatedAt:     repo.GetCreatedAt().Time,
-						UpdatedAt:     repo.GetUpdatedAt().Time,
-						PushedAt:      repo.GetPushedAt().Time,
-						DefaultBranch: repo.GetDefaultBranch(),
-						Stars:         repo.GetStargazersCount(),
-						Forks:         repo.GetForksCount(),
-						OpenIssues:    repo.GetOpenIssuesCount(),
-						Metadata:      make(map[string]string),
-					}
-					if repo.GetLicense() != nil {
-						modelRepo.License = repo.GetLicense().GetName()
-					}
-				}
-
-				allRepos = append(allRepos, modelRepo)
+			if err := fn(nonNilRepositories(installationRepos.Repositories)); err != nil {
+				return err
 			}
 
 			if resp.NextPage == 0 {
@@ -286,7 +300,17 @@ func (c *Client) DiscoverRepositoriesWithOptions(ctx context.Context, org string
 		}
 	}
 
-	return allRepos, nil
+	return nil
+}
+
+func nonNilRepositories(repos []*github.Repository) []*github.Repository {
+	kept := repos[:0]
+	for _, repo := range repos {
+		if repo != nil {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
 }
 
 // fetchSpecificRepositories directly fetches specific repositories by name
@@ -321,29 +345,7 @@ func (c *Client) fetchSpecificRepositories(ctx context.Context, org string, repo
 			log.Printf("DEBUG: Successfully enriched repository: %s", repo.GetFullName())
 		} else {
 			// Create minimal repository model without enrichment
-			modelRepo = models.Repository{
-				ID:            repo.GetID(),
-				Name:          repo.GetName(),
-				FullName:      repo.GetFullName(),
-				Description:   repo.GetDescription(),
-				HTMLURL:       repo.GetHTMLURL(),
-				CloneURL:      repo.GetCloneURL(),
-				Language:      repo.GetLanguage(),
-				Topics:        repo.Topics,
-				Private:       repo.GetPrivate(),
-				Archived:      repo.GetArchived(),
-				CreatedAt:     repo.GetCreatedAt().Time,
-				UpdatedAt:     repo.GetUpdatedAt().Time,
-				PushedAt:      repo.GetPushedAt().Time,
-				DefaultBranch: repo.GetDefaultBranch(),
-				Stars:         repo.GetStargazersCount(),
-				Forks:         repo.GetForksCount(),
-				OpenIssues:    repo.GetOpenIssuesCount(),
-				Metadata:      make(map[string]string),
-			}
-			if repo.GetLicense() != nil {
-				modelRepo.License = repo.GetLicense().GetName()
-			}
+			modelRepo = basicRepository(repo)
 		}
 		
 		allRepos = append(allRepos, modelRepo)
@@ -354,51 +356,126 @@ func (c *Client) fetchSpecificRepositories(ctx context.Context, org string, repo
 }
 
 func (c *Client) enrichRepository(ctx context.Context, repo *github.Repository) (models.Repository, error) {
-	modelRepo := models.Repository{
-		ID:            repo.GetID(),
-		Name:          repo.GetName(),
-		FullName:      repo.GetFullName(),
-		Description:   repo.GetDescription(),
-		HTMLURL:       repo.GetHTMLURL(),
-		CloneURL:      repo.GetCloneURL(),
-		Language:      repo.GetLanguage(),
-		Topics:        repo.Topics,
-		Private:       repo.GetPrivate(),
-		Archived:      repo.GetArchived(),
-		CreatedAt:     repo.GetCreatedAt().Time,
-		UpdatedAt:     repo.GetUpdatedAt().Time,
-		PushedAt:      repo.GetPushedAt().Time,
-		DefaultBranch: repo.GetDefaultBranch(),
-		Stars:         repo.GetStargazersCount(),
-		Forks:         repo.GetForksCount(),
-		OpenIssues:    repo.GetOpenIssuesCount(),
-		Metadata:      make(map[string]string),
-	}
+	modelRepo := basicRepository(repo)
+	c.enrich(ctx, repo, &modelRepo)
+	return modelRepo, nil
+}
 
-	if repo.GetLicense() != nil {
-		modelRepo.License = repo.GetLicense().GetName()
+// EnrichRepository adds CODEOWNERS, signals, languages, custom properties and modules
+// to a repository listed without enrichment, such as by StreamRepositories
+func (c *Client) EnrichRepository(ctx context.Context, repo models.Repository) (models.Repository, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return repo, err
+	}
+	ref := &github.Repository{
+		Owner:         &github.User{Login: &owner},
+		Name:          &repoName,
+		FullName:      &repo.FullName,
+		DefaultBranch: &repo.DefaultBranch,
 	}
+	c.enrich(ctx, ref, &repo)
+	return repo, nil
+}
 
-	codeOwners, err := c.getCodeOwners(ctx, repo)
+// enrich fills in the details of modelRepo that need API calls beyond the listing
+func (c *Client) enrich(ctx context.Context, repo *github.Repository, modelRepo *models.Repository) {
+	// One tree call answers which of the candidate files exist, so only the files
+	// that are there get read
+	tree := c.getTree(ctx, repo)
+
+	codeOwners, err := c.getCodeOwners(ctx, repo, tree)
 	if err != nil {
 		log.Printf("Warning: failed to get CODEOWNERS for %s: %v", repo.GetFullName(), err)
 	} else {
 		modelRepo.CodeOwners = codeOwners
 	}
 
-	signals, err := c.detectRepositorySignals(ctx, repo)
+	signals, err := c.repositorySignals(ctx, repo, tree)
 	if err != nil {
 		log.Printf("Warning: failed to detect signals for %s: %v", repo.GetFullName(), err)
 	} else {
 		modelRepo.HasDockerfile = signals.HasDockerfile
 		modelRepo.HasKubernetes = signals.HasKubernetes
-		modelRepo.HasCI = signals.HasCI
+		modelRepo.CISystems = signals.CISystems
+		modelRepo.HasCI = len(signals.CISystems) > 0
 	}
 
-	return modelRepo, nil
+	languages, _, err := c.client.Repositories.ListLanguages(ctx, repo.GetOwner().GetLogin(), repo.GetName())
+	if err != nil {
+		log.Printf("Warning: failed to list languages for %s: %v", repo.GetFullName(), err)
+	} else {
+		modelRepo.Languages = languages
+	}
+
+	properties, err := c.getCustomProperties(ctx, repo)
+	if err != nil {
+		log.Printf("Warning: failed to get custom properties for %s: %v", repo.GetFullName(), err)
+	} else {
+		modelRepo.Properties = properties
+	}
+
+	manifest := c.detectModules(ctx, repo, tree)
+	modelRepo.Modules = manifest.Modules
+	modelRepo.Dependencies = manifest.Dependencies
+
+	kubernetes := c.detectKubernetes(ctx, repo, tree)
+	modelRepo.K8sSelector = kubernetes.Selector
+	modelRepo.K8sNamespace = kubernetes.Namespace
+
+	if tree == nil {
+		if err := c.DetectRootSignals(ctx, modelRepo); err != nil {
+			log.Printf("Warning: failed to inspect root of %s: %v", repo.GetFullName(), err)
+		}
+		return
+	}
+	tree.rootSignals(modelRepo)
+	modelRepo.CatalogFiles = tree.catalogFiles(c.catalogPaths)
 }
 
-func (c *Client) getCodeOwners(ctx context.Context, repo *github.Repository) ([]string, error) {
+// customPropertyValue is an entry of the repository custom properties API. Values are
+// strings, or arrays of strings for multi-select properties.
+type customPropertyValue struct {
+	PropertyName string      `json:"property_name"`
+	Value        interface{} `json:"value"`
+}
+
+// getCustomProperties returns the organization custom property values set on the
+// repository. Multi-select values are joined with commas.
+func (c *Client) getCustomProperties(ctx context.Context, repo *github.Repository) (map[string]string, error) {
+	// go-github v50 predates the custom properties API, so call it directly
+	url := fmt.Sprintf("repos/%s/%s/properties/values", repo.GetOwner().GetLogin(), repo.GetName())
+	req, err := c.client.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []customPropertyValue
+	if _, err := c.client.Do(ctx, req, &values); err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]string, len(values))
+	for _, v := range values {
+		switch value := v.Value.(type) {
+		case string:
+			properties[v.PropertyName] = value
+		case []interface{}:
+			var parts []string
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					parts = append(parts, s)
+				}
+			}
+			properties[v.PropertyName] = strings.Join(parts, ",")
+		}
+	}
+	return properties, nil
+}
+
+// getCodeOwners reads the first CODEOWNERS file of the repository. With a tree, only
+// a file that exists is read.
+func (c *Client) getCodeOwners(ctx context.Context, repo *github.Repository, tree *repoTree) ([]string, error) {
 	paths := []string{
 		"CODEOWNERS",
 		".github/CODEOWNERS",
@@ -406,6 +483,9 @@ func (c *Client) getCodeOwners(ctx context.Context, repo *github.Repository) ([]
 	}
 
 	for _, path := range paths {
+		if tree != nil && !tree.exists(path) {
+			continue
+		}
 		content, _, resp, err := c.client.Repositories.GetContents(
 			ctx,
 			repo.GetOwner().GetLogin(),
@@ -465,46 +545,69 @@ func parseCodeOwners(content string) []string {
 type repositorySignals struct {
 	HasDockerfile bool
 	HasKubernetes bool
-	HasCI         bool
+	CISystems     []string
 }
 
-func (c *Client) detectRepositorySignals(ctx context.Context, repo *github.Repository) (*repositorySignals, error) {
-	signals := &repositorySignals{}
+// Paths whose presence marks a repository as containerized, deployed to Kubernetes
+// or built by CI. Entries ending in / are directories and * matches any file.
+var (
+	dockerPaths = []string{"Dockerfile", "docker-compose.yml", "docker-compose.yaml"}
 
-	files := []struct {
-		path string
-		flag *bool
-	}{
-		{"Dockerfile", &signals.HasDockerfile},
-		{"docker-compose.yml", &signals.HasDockerfile},
-		{"docker-compose.yaml", &signals.HasDockerfile},
-	}
-
-	k8sFiles := []string{
+	kubernetesPaths = []string{
 		"k8s/", "kubernetes/", "deploy/", "deployment/",
 		"*.yaml", "*.yml",
 	}
 
-	ciFiles := []string{
-		".github/workflows/", ".gitlab-ci.yml", ".circleci/",
-		"Jenkinsfile", ".travis.yml", "azure-pipelines.yml",
-		".harness/", "bitbucket-pipelines.yml",
+	// ciSystems maps each CI system to the paths that configure it, in the order
+	// they're reported. Harness CI is matched on pipeline files rather than .harness/,
+	// which can hold just the catalog file.
+	ciSystems = []struct {
+		name  string
+		paths []string
+	}{
+		{"github-actions", []string{".github/workflows/"}},
+		{"jenkins", []string{"Jenkinsfile"}},
+		{"circleci", []string{".circleci/"}},
+		{"harness", []string{".harness/pipeline.yaml", ".harness/pipeline.yml", ".harness/pipelines/"}},
+		{"gitlab-ci", []string{".gitlab-ci.yml"}},
+		{"travis", []string{".travis.yml"}},
+		{"azure-pipelines", []string{"azure-pipelines.yml"}},
+		{"bitbucket-pipelines", []string{"bitbucket-pipelines.yml"}},
+	}
+)
+
+// repositorySignals answers the signals from the tree, probing the paths one by one
+// when there is none
+func (c *Client) repositorySignals(ctx context.Context, repo *github.Repository, tree *repoTree) (*repositorySignals, error) {
+	if tree != nil {
+		return tree.signals(), nil
 	}
+	return c.detectRepositorySignals(ctx, repo)
+}
+
+// detectRepositorySignals probes the signal paths one by one, for repositories whose
+// tree couldn't be read
+func (c *Client) detectRepositorySignals(ctx context.Context, repo *github.Repository) (*repositorySignals, error) {
+	signals := &repositorySignals{}
 
-	for _, file := range files {
-		exists, err := c.fileExists(ctx, repo, file.path)
+	for _, path := range dockerPaths {
+		exists, err := c.fileExists(ctx, repo, path)
 		if err != nil {
-			log.Printf("Warning: error checking %s in %s: %v", file.path, repo.GetFullName(), err)
+			log.Printf("Warning: error checking %s in %s: %v", path, repo.GetFullName(), err)
 			continue
 		}
-		*file.flag = exists
 		if exists {
+			signals.HasDockerfile = true
 			break
 		}
 	}
 
-	signals.HasKubernetes = c.checkPathsExist(ctx, repo, k8sFiles)
-	signals.HasCI = c.checkPathsExist(ctx, repo, ciFiles)
+	signals.HasKubernetes = c.checkPathsExist(ctx, repo, kubernetesPaths)
+	for _, system := range ciSystems {
+		if c.checkPathsExist(ctx, repo, system.paths) {
+			signals.CISystems = append(signals.CISystems, system.name)
+		}
+	}
 
 	return signals, nil
 }
@@ -528,6 +631,15 @@ func (c *Client) fileExists(ctx context.Context, repo *github.Repository, path s
 	return true, nil
 }
 
+// FileExists reports whether a file or directory exists on the repository's default branch
+func (c *Client) FileExists(ctx context.Context, repo models.Repository, path string) (bool, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return false, err
+	}
+	return c.fileExists(ctx, &github.Repository{Owner: &github.User{Login: &owner}, Name: &repoName}, path)
+}
+
 func (c *Client) checkPathsExist(ctx context.Context, repo *github.Repository, paths []string) bool {
 	for _, path := range paths {
 		if strings.Contains(path, "*") {
@@ -575,90 +687,233 @@ func (c *Client) checkGlobPattern(ctx context.Context, repo *github.Repository,
 	return false
 }
 
-func (c *Client) CreatePR(ctx context.Context, repo models.Repository, yamlContent string) error {
+// CreatePR opens an onboarding pull request adding or updating the catalog file at
+// catalogPath, together with extraFiles, in a single commit, and returns its URL. An
+// empty URL with no error means the catalog file was already up to date.
+func (c *Client) CreatePR(ctx context.Context, repo models.Repository, catalogPath, yamlContent string, extraFiles []PRFile) (string, error) {
 	owner, repoName, err := parseFullName(repo.FullName)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	branchName := fmt.Sprintf("harness-onboarding-%d", time.Now().Unix())
-	
-	baseBranch, _, err := c.client.Repositories.GetBranch(ctx, owner, repoName, repo.DefaultBranch, true)
+	changes, err := c.resolveFiles(ctx, repo, append([]PRFile{{Path: catalogPath, Content: yamlContent}}, extraFiles...))
 	if err != nil {
-		return fmt.Errorf("failed to get base branch: %w", err)
+		return "", err
+	}
+	if len(changes) == 0 || changes[0].Path != catalogPath {
+		log.Printf("Catalog-info.yaml in %s is already up to date, skipping", repo.FullName)
+		return "", nil
 	}
+	isUpdate := changes[0].Exists
 
-	newRef := &github.Reference{
-		Ref: github.String(fmt.Sprintf("refs/heads/%s", branchName)),
-		Object: &github.GitObject{
-			SHA: baseBranch.Commit.SHA,
-		},
+	branchName, err := c.onboardingBranch(repo)
+	if err != nil {
+		return "", err
+	}
+	if c.branchIsDeterministic() {
+		err = c.resetBranch(ctx, repo, branchName)
+	} else {
+		err = c.createBranch(ctx, repo, branchName)
+	}
+	if err != nil {
+		return "", err
 	}
+	// A deterministic branch is reset and reused by the next attempt
+	opened := false
+	defer func() {
+		if !opened && !c.branchIsDeterministic() {
+			c.discardBranch(ctx, repo, branchName)
+		}
+	}()
 
-	_, _, err = c.client.Git.CreateRef(ctx, owner, repoName, newRef)
+	message := "Add Harness IDP catalog-info.yaml"
+	if isUpdate {
+		message = "Update Harness IDP catalog-info.yaml"
+	}
+	commit, err := c.commitFiles(ctx, repo, branchName, changes, message)
 	if err != nil {
-		// Check if branch already exists (usually indicates existing PR)
-		if strings.Contains(strings.ToLower(err.Error()), "reference already exists") {
-			return errors.NewPRExistsError(repo.FullName, 0, err)
+		return "", err
+	}
+	c.postCatalogStatus(ctx, owner, repoName, commit.GetSHA(), yamlContent)
+
+	var added []string
+	for _, change := range changes[1:] {
+		added = append(added, change.Path)
+	}
+
+	prTitle, prBody, err := c.prText(repo, isUpdate, catalogPath, added)
+	if err != nil {
+		return "", err
+	}
+
+	newPR := &github.NewPullRequest{
+		Title: &prTitle,
+		Head:  &branchName,
+		Base:  github.String(c.baseBranch(repo)),
+		Body:  &prBody,
+	}
+
+	pr, _, err := c.client.PullRequests.Create(ctx, owner, repoName, newPR)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PR: %w", err)
+	}
+	opened = true
+
+	c.decoratePR(ctx, owner, repoName, pr.GetNumber())
+
+	log.Printf("Created PR #%d for %s: %s", pr.GetNumber(), repo.FullName, pr.GetHTMLURL())
+	return pr.GetHTMLURL(), nil
+}
+
+// apiSpecPaths are the locations checked for an OpenAPI or Swagger definition
+var apiSpecPaths = []string{
+	"openapi.yaml", "openapi.yml", "openapi.json",
+	"swagger.yaml", "swagger.yml", "swagger.json",
+}
+
+// DetectRootSignals lists the repository root once and records the OpenAPI/Swagger
+// definition, infrastructure-as-code tool and MkDocs documentation found there, if any
+func (c *Client) DetectRootSignals(ctx context.Context, repo *models.Repository) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	_, entries, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, "", nil)
+	if err != nil {
+		// Empty repositories have no root to list
+		if resp != nil && resp.StatusCode == 404 {
+			return nil
 		}
-		return fmt.Errorf("failed to create branch: %w", err)
+		return fmt.Errorf("failed to list repository root: %w", err)
 	}
 
-	catalogPath := "catalog-info.yaml"
-	
-	// Check if catalog-info.yaml already exists
-	existingFile, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, catalogPath, nil)
-	var isUpdate bool
-	var message string
-	var content *github.RepositoryContentFileOptions
-	
-	if err == nil && existingFile != nil {
-		// File exists - check if content is different
-		existingContent, err := existingFile.GetContent()
-		if err != nil {
-			return fmt.Errorf("failed to get existing content: %w", err)
+	files := make(map[string]bool, len(entries))
+	hasDocsDir := false
+	for _, entry := range entries {
+		switch entry.GetType() {
+		case "file":
+			files[entry.GetName()] = true
+		case "dir":
+			hasDocsDir = hasDocsDir || entry.GetName() == "docs"
 		}
-		
-		if strings.TrimSpace(existingContent) == strings.TrimSpace(yamlContent) {
-			log.Printf("Catalog-info.yaml in %s is already up to date, skipping", repo.FullName)
-			return nil
+	}
+
+	applyRootSignals(repo, files, hasDocsDir)
+	return nil
+}
+
+// applyRootSignals records the signals found among the files at the repository root
+func applyRootSignals(repo *models.Repository, files map[string]bool, hasDocsDir bool) {
+	for _, path := range apiSpecPaths {
+		if files[path] {
+			repo.APISpecPath = path
+			break
 		}
-		
-		// Content is different - prepare for update
-		isUpdate = true
-		message = "Update Harness IDP catalog-info.yaml"
-		content = &github.RepositoryContentFileOptions{
-			Message: &message,
-			Content: []byte(yamlContent),
-			Branch:  &branchName,
-			SHA:     existingFile.SHA, // Required for updates
-		}
-	} else if resp != nil && resp.StatusCode == 404 {
-		// File doesn't exist - prepare for creation
-		isUpdate = false
-		message = "Add Harness IDP catalog-info.yaml"
-		content = &github.RepositoryContentFileOptions{
-			Message: &message,
-			Content: []byte(yamlContent),
-			Branch:  &branchName,
+	}
+
+	repo.IaCTool = detectIaCTool(files)
+	repo.HasDocs = files["mkdocs.yml"] || files["mkdocs.yaml"] || hasDocsDir
+}
+
+// detectIaCTool identifies Pulumi and Terraform projects from their root files
+func detectIaCTool(files map[string]bool) string {
+	if files["Pulumi.yaml"] || files["Pulumi.yml"] {
+		return "pulumi"
+	}
+	for name := range files {
+		if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") {
+			return "terraform"
 		}
-	} else {
-		return fmt.Errorf("failed to check existing file: %w", err)
 	}
+	return ""
+}
 
-	// Create or update the file
-	if isUpdate {
-		_, _, err = c.client.Repositories.UpdateFile(ctx, owner, repoName, catalogPath, content)
+// UpdatePR pushes refreshed catalog content, together with extraFiles, to the branch
+// of an open onboarding PR in a single commit and regenerates its title and body. Files
+// are resolved against the base branch as CreatePR does, and only those that differ
+// from the PR branch are committed. It returns false when the branch is already current.
+func (c *Client) UpdatePR(ctx context.Context, repo models.Repository, pr *github.PullRequest, catalogPath, yamlContent string, extraFiles []PRFile) (bool, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return false, err
+	}
+	if pr.GetHead().GetRepo().GetFullName() != repo.FullName {
+		return false, fmt.Errorf("PR #%d comes from a fork and can't be updated", pr.GetNumber())
+	}
+	branchName := pr.GetHead().GetRef()
+
+	if err := c.rebaseIfStale(ctx, repo, pr); err != nil {
+		return false, err
+	}
+
+	changes, err := c.resolveFiles(ctx, repo, append([]PRFile{{Path: catalogPath, Content: yamlContent}}, extraFiles...))
+	if err != nil {
+		return false, err
+	}
+	// A catalog file that now matches the base branch still replaces the PR's copy
+	if len(changes) == 0 || changes[0].Path != catalogPath {
+		changes = append([]fileChange{{Path: catalogPath, Content: yamlContent, Exists: true}}, changes...)
+	}
+	var pending []fileChange
+	for _, change := range changes {
+		current, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, change.Path, &github.RepositoryContentGetOptions{Ref: branchName})
 		if err != nil {
-			return fmt.Errorf("failed to update file: %w", err)
+			if resp == nil || resp.StatusCode != 404 {
+				return false, fmt.Errorf("failed to check %s: %w", change.Path, err)
+			}
+			pending = append(pending, change)
+			continue
 		}
-	} else {
-		_, _, err = c.client.Repositories.CreateFile(ctx, owner, repoName, catalogPath, content)
+		content, err := current.GetContent()
 		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
+			return false, fmt.Errorf("failed to get existing content of %s: %w", change.Path, err)
+		}
+		if strings.TrimSpace(content) != strings.TrimSpace(change.Content) {
+			pending = append(pending, change)
 		}
 	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	commit, err := c.commitFiles(ctx, repo, branchName, pending, "Refresh Harness IDP catalog-info.yaml")
+	if err != nil {
+		return false, err
+	}
+	c.postCatalogStatus(ctx, owner, repoName, commit.GetSHA(), yamlContent)
+
+	// The PR is an update when the base branch already has the catalog file, which is
+	// also the case when it no longer differs from the base branch
+	isUpdate := true
+	var added []string
+	for _, change := range changes {
+		if change.Path == catalogPath {
+			isUpdate = change.Exists
+			continue
+		}
+		added = append(added, change.Path)
+	}
+
+	title, body, err := c.prText(repo, isUpdate, catalogPath, added)
+	if err != nil {
+		return false, err
+	}
+	_, _, err = c.client.PullRequests.Edit(ctx, owner, repoName, pr.GetNumber(), &github.PullRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update PR #%d: %w", pr.GetNumber(), err)
+	}
+
+	log.Printf("Refreshed PR #%d for %s: %s", pr.GetNumber(), repo.FullName, pr.GetHTMLURL())
+	return true, nil
+}
 
+// prText returns the title and body of an onboarding PR, from the configured
+// templates when set
+func (c *Client) prText(repo models.Repository, isUpdate bool, catalogPath string, added []string) (string, string, error) {
 	// Set PR title and body based on whether it's an add or update
 	var prTitle string
 	var prBody string
@@ -691,20 +946,178 @@ This enables the repository to be discovered and managed through Harness IDP.
 Auto-generated by harness-onboarder tool.`
 	}
 
+	if len(added) > 0 {
+		prBody += "\n\nAlso includes:\n- " + strings.Join(added, "\n- ")
+	}
+
+	data := PRTemplateData{Repository: repo, Update: isUpdate, CatalogPath: catalogPath, AddedFiles: added}
+	prTitle, err := renderPRTemplate(c.prTitleTemplate, data, prTitle)
+	if err != nil {
+		return "", "", err
+	}
+	prBody, err = renderPRTemplate(c.prBodyTemplate, data, prBody)
+	if err != nil {
+		return "", "", err
+	}
+	return prTitle, withMarker(prBody), nil
+}
+
+// commitAuthor returns the configured commit identity, or nil to use the app's own
+func (c *Client) commitAuthor() *github.CommitAuthor {
+	if c.config.CommitAuthorName == "" && c.config.CommitAuthorEmail == "" {
+		return nil
+	}
+	return &github.CommitAuthor{
+		Name:  github.String(c.config.CommitAuthorName),
+		Email: github.String(c.config.CommitAuthorEmail),
+	}
+}
+
+// createBranch creates a branch from the head of the repository's base branch
+func (c *Client) createBranch(ctx context.Context, repo models.Repository, branchName string) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	baseBranch, _, err := c.client.Repositories.GetBranch(ctx, owner, repoName, c.baseBranch(repo), true)
+	if err != nil {
+		return fmt.Errorf("failed to get base branch: %w", err)
+	}
+
+	newRef := &github.Reference{
+		Ref: github.String(fmt.Sprintf("refs/heads/%s", branchName)),
+		Object: &github.GitObject{
+			SHA: baseBranch.Commit.SHA,
+		},
+	}
+
+	_, _, err = c.client.Git.CreateRef(ctx, owner, repoName, newRef)
+	if err != nil {
+		// A leftover branch without an open PR is reset rather than treated as a conflict
+		if strings.Contains(strings.ToLower(err.Error()), "reference already exists") {
+			return c.resetBranch(ctx, repo, branchName)
+		}
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMigrationPR opens a pull request replacing a legacy Backstage catalog file
+// at the given path with its harness.io/v1 conversion, and returns the PR URL
+func (c *Client) CreateMigrationPR(ctx context.Context, repo models.Repository, path, yamlContent string) (string, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return "", err
+	}
+
+	existingFile, _, _, err := c.client.Repositories.GetContents(ctx, owner, repoName, path, nil)
+	if err != nil || existingFile == nil {
+		return "", fmt.Errorf("failed to get %s: %w", path, err)
+	}
+
+	branchName := fmt.Sprintf("harness-idp-migration-%d", time.Now().Unix())
+	if err := c.createBranch(ctx, repo, branchName); err != nil {
+		return "", err
+	}
+	opened := false
+	defer func() {
+		if !opened {
+			c.discardBranch(ctx, repo, branchName)
+		}
+	}()
+
+	message := "Migrate catalog-info.yaml to Harness IDP 2.0 format"
+	_, _, err = c.client.Repositories.UpdateFile(ctx, owner, repoName, path, &github.RepositoryContentFileOptions{
+		Message:   &message,
+		Content:   []byte(yamlContent),
+		Branch:    &branchName,
+		SHA:       existingFile.SHA,
+		Author:    c.commitAuthor(),
+		Committer: c.commitAuthor(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update file: %w", err)
+	}
+
+	prTitle := "Migrate catalog-info.yaml to Harness IDP 2.0"
+	prBody := fmt.Sprintf(`This PR converts %s from the Backstage format (backstage.io/v1alpha1) to the Harness IDP 2.0 schema (harness.io/v1).
+
+Changes:
+- metadata.name becomes the top-level identifier (hyphens replaced with underscores)
+- spec.type and spec.owner move to the top level
+- orgIdentifier and projectIdentifier set the entity scope
+
+Please review the converted entity before merging.
+
+Auto-generated by harness-onboarder tool.`, path)
+	prBody = withMarker(prBody)
+
 	newPR := &github.NewPullRequest{
 		Title: &prTitle,
 		Head:  &branchName,
-		Base:  &repo.DefaultBranch,
+		Base:  github.String(c.baseBranch(repo)),
 		Body:  &prBody,
 	}
 
 	pr, _, err := c.client.PullRequests.Create(ctx, owner, repoName, newPR)
 	if err != nil {
-		return fmt.Errorf("failed to create PR: %w", err)
+		return "", fmt.Errorf("failed to create PR: %w", err)
 	}
+	opened = true
 
-	log.Printf("Created PR #%d for %s: %s", pr.GetNumber(), repo.FullName, pr.GetHTMLURL())
-	return nil
+	c.decoratePR(ctx, owner, repoName, pr.GetNumber())
+
+	log.Printf("Created migration PR #%d for %s: %s", pr.GetNumber(), repo.FullName, pr.GetHTMLURL())
+	return pr.GetHTMLURL(), nil
+}
+
+// decoratePR applies the configured labels, assignees and milestone to a new PR.
+// The PR already exists at this point, so failures are logged rather than returned.
+func (c *Client) decoratePR(ctx context.Context, owner, repoName string, number int) {
+	if len(c.config.PRLabels) > 0 {
+		if _, _, err := c.client.Issues.AddLabelsToIssue(ctx, owner, repoName, number, c.config.PRLabels); err != nil {
+			log.Printf("Warning: failed to label PR #%d in %s/%s: %v", number, owner, repoName, err)
+		}
+	}
+
+	if len(c.config.PRAssignees) > 0 {
+		if _, _, err := c.client.Issues.AddAssignees(ctx, owner, repoName, number, c.config.PRAssignees); err != nil {
+			log.Printf("Warning: failed to assign PR #%d in %s/%s: %v", number, owner, repoName, err)
+		}
+	}
+
+	if c.config.PRMilestone != "" {
+		milestone, err := c.findMilestone(ctx, owner, repoName, c.config.PRMilestone)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			return
+		}
+		if _, _, err := c.client.Issues.Edit(ctx, owner, repoName, number, &github.IssueRequest{Milestone: &milestone}); err != nil {
+			log.Printf("Warning: failed to set milestone on PR #%d in %s/%s: %v", number, owner, repoName, err)
+		}
+	}
+}
+
+// findMilestone returns the number of the open milestone with the given title
+func (c *Client) findMilestone(ctx context.Context, owner, repoName, title string) (int, error) {
+	opts := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, resp, err := c.client.Issues.ListMilestones(ctx, owner, repoName, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list milestones in %s/%s: %w", owner, repoName, err)
+		}
+		for _, m := range milestones {
+			if m.GetTitle() == title {
+				return m.GetNumber(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return 0, fmt.Errorf("milestone %q not found in %s/%s", title, owner, repoName)
+		}
+		opts.Page = resp.NextPage
+	}
 }
 
 func parseFullName(fullName string) (string, string, error) {
@@ -770,70 +1183,247 @@ func (c *Client) GetCatalogInfo(ctx context.Context, repo models.Repository) (st
 	return "", fmt.Errorf("no catalog-info.yaml file found in %s", repo.FullName)
 }
 
-// CheckForExistingOnboardingPR checks if there are any open PRs related to Harness onboarding
+// CheckForExistingOnboardingPR checks if there are any open PRs related to Harness
+// onboarding, paging through every open PR so busy repositories don't hide it
 func (c *Client) CheckForExistingOnboardingPR(ctx context.Context, repo models.Repository) (*github.PullRequest, error) {
 	owner, repoName, err := parseFullName(repo.FullName)
 	if err != nil {
 		return nil, err
 	}
 
-	// List open pull requests
-	opts := &github.PullRequestListOptions{
-		State: "open",
-		ListOptions: github.ListOptions{
-			PerPage: 50, // Should be enough to find recent onboarding PRs
-		},
+	opts := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		prs, resp, err := c.client.PullRequests.List(ctx, owner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		for _, pr := range prs {
+			if pr != nil && c.isOnboardingPR(pr) {
+				log.Printf("Found existing Harness onboarding PR #%d: %s", pr.GetNumber(), pr.GetTitle())
+				return pr, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
 	}
+}
 
-	prs, _, err := c.client.PullRequests.List(ctx, owner, repoName, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+// prMarker is a hidden comment added to the body of every PR the tool opens
+const prMarker = "<!-- harness-onboarder -->"
+
+// withMarker appends prMarker to a PR body
+func withMarker(body string) string {
+	return body + "\n\n" + prMarker
+}
+
+// isOnboardingPR reports whether a PR was opened by this tool: it comes from an
+// onboarding branch, carries one of the configured labels, or has the hidden marker
+func (c *Client) isOnboardingPR(pr *github.PullRequest) bool {
+	if strings.Contains(pr.GetBody(), prMarker) {
+		return true
 	}
+	if len(c.config.PRLabels) > 0 && hasAnyLabel(pr, c.config.PRLabels) {
+		return true
+	}
+	return c.isOnboardingBranch(pr.GetHead().GetRef())
+}
 
-	// Look for PRs that appear to be Harness onboarding related
-	for _, pr := range prs {
-		if pr == nil {
-			continue
+// isOnboardingBranch reports whether a branch name matches the built-in onboarding
+// prefixes or the fixed part of the configured branch template
+func (c *Client) isOnboardingBranch(name string) bool {
+	for _, prefix := range onboardingBranchPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
 		}
+	}
+	if c.config.BranchTemplate != "" {
+		prefix, _, _ := strings.Cut(c.config.BranchTemplate, "{{")
+		return prefix != "" && strings.HasPrefix(name, prefix)
+	}
+	return false
+}
 
-		title := strings.ToLower(pr.GetTitle())
-		body := strings.ToLower(pr.GetBody())
-		
-		// Check if PR is related to Harness onboarding
-		if isHarnessOnboardingPR(title, body) {
-			log.Printf("Found existing Harness onboarding PR #%d: %s", pr.GetNumber(), pr.GetTitle())
-			return pr, nil
+func hasAnyLabel(pr *github.PullRequest, labels []string) bool {
+	for _, label := range pr.Labels {
+		for _, name := range labels {
+			if strings.EqualFold(label.GetName(), name) {
+				return true
+			}
 		}
 	}
+	return false
+}
+
+// ValidateAccess checks that the app installation can see the given organization or user
+func (c *Client) ValidateAccess(ctx context.Context, org string) error {
+	if _, _, err := c.client.Users.Get(ctx, org); err != nil {
+		return fmt.Errorf("failed to access %s: %w", org, err)
+	}
 
-	return nil, nil
+	if _, _, err := c.client.Apps.ListRepos(ctx, &github.ListOptions{PerPage: 1}); err != nil {
+		return fmt.Errorf("failed to list installation repositories: %w", err)
+	}
+
+	return nil
 }
 
-// isHarnessOnboardingPR determines if a PR is related to Harness onboarding
-func isHarnessOnboardingPR(title, body string) bool {
-	harnessKeywords := []string{
-		"harness",
-		"catalog-info.yaml",
-		"catalog-info",
-		"idp",
-		"harness-onboarder",
-		"harness onboarding",
-		"add harness",
-		"update harness",
+// GetRepository fetches a single repository by full name without enrichment.
+// It returns nil with no error when the repository no longer exists.
+func (c *Client) GetRepository(ctx context.Context, fullName string) (*models.Repository, error) {
+	owner, repoName, err := parseFullName(fullName)
+	if err != nil {
+		return nil, err
 	}
 
-	text := title + " " + body
-	
-	for _, keyword := range harnessKeywords {
-		if strings.Contains(text, keyword) {
-			return true
+	repo, resp, err := c.client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return nil, nil
 		}
+		return nil, fmt.Errorf("failed to get repository %s: %w", fullName, err)
 	}
 
-	return false
+	modelRepo := basicRepository(repo)
+	return &modelRepo, nil
+}
+
+// basicRepository converts a GitHub repository into the model without enrichment
+func basicRepository(repo *github.Repository) models.Repository {
+	modelRepo := models.Repository{
+		ID:            repo.GetID(),
+		Name:          repo.GetName(),
+		FullName:      repo.GetFullName(),
+		Description:   repo.GetDescription(),
+		HTMLURL:       repo.GetHTMLURL(),
+		Homepage:      repo.GetHomepage(),
+		CloneURL:      repo.GetCloneURL(),
+		Language:      repo.GetLanguage(),
+		Topics:        repo.Topics,
+		Private:       repo.GetPrivate(),
+		Visibility:    repo.GetVisibility(),
+		Archived:      repo.GetArchived(),
+		Fork:          repo.GetFork(),
+		IsTemplate:    repo.GetIsTemplate(),
+		MirrorURL:     repo.GetMirrorURL(),
+		CreatedAt:     repo.GetCreatedAt().Time,
+		UpdatedAt:     repo.GetUpdatedAt().Time,
+		PushedAt:      repo.GetPushedAt().Time,
+		DefaultBranch: repo.GetDefaultBranch(),
+		Stars:         repo.GetStargazersCount(),
+		SizeKB:        repo.GetSize(),
+		Forks:         repo.GetForksCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		Metadata:      make(map[string]string),
+	}
+	if modelRepo.Visibility == "" {
+		// Older GitHub Enterprise Server versions don't report visibility
+		modelRepo.Visibility = "public"
+		if modelRepo.Private {
+			modelRepo.Visibility = "private"
+		}
+	}
+	if repo.GetLicense() != nil {
+		modelRepo.License = repo.GetLicense().GetName()
+	}
+	return modelRepo
 }
 
 // GetClient returns the underlying GitHub client for direct API access
 func (c *Client) GetClient() *github.Client {
 	return c.client
-}
\ No newline at end of file
+}
+// ListTeams returns the organization's teams along with their members
+func (c *Client) ListTeams(ctx context.Context, org string) ([]models.Team, error) {
+	var teams []models.Team
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.client.Teams.ListTeams(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list teams: %w", err)
+		}
+
+		for _, team := range page {
+			if team == nil {
+				continue
+			}
+
+			members, err := c.listTeamMembers(ctx, org, team.GetSlug())
+			if err != nil {
+				log.Printf("Warning: failed to list members of team %s: %v", team.GetSlug(), err)
+			}
+
+			modelTeam := models.Team{
+				Slug:        team.GetSlug(),
+				Name:        team.GetName(),
+				Description: team.GetDescription(),
+				HTMLURL:     team.GetHTMLURL(),
+				Members:     members,
+			}
+			if team.Parent != nil {
+				modelTeam.Parent = team.Parent.GetSlug()
+			}
+			teams = append(teams, modelTeam)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return teams, nil
+}
+
+func (c *Client) listTeamMembers(ctx context.Context, org, slug string) ([]string, error) {
+	var members []string
+
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := c.client.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return members, nil
+}
+
+// ListTeamRepositories returns the names of the organization's repositories the team
+// has admin, maintain or write access to
+func (c *Client) ListTeamRepositories(ctx context.Context, org, slug string) ([]string, error) {
+	var names []string
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		repos, resp, err := c.client.Teams.ListTeamReposBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories of team %s: %w", slug, err)
+		}
+
+		for _, repo := range repos {
+			permissions := repo.GetPermissions()
+			if permissions["admin"] || permissions["maintain"] || permissions["push"] {
+				names = append(names, repo.GetName())
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return names, nil
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// CommitDirect commits the catalog file at catalogPath, together with extraFiles, straight
+// to the default branch in a single commit instead of opening a pull request, and returns
+// the commit URL. An empty URL with no error means the file was already up to date.
+// PreflightPR with direct set checks the branch protection beforehand.
+func (c *Client) CommitDirect(ctx context.Context, repo models.Repository, catalogPath, yamlContent string, extraFiles []PRFile) (string, error) {
+	changes, err := c.resolveFiles(ctx, repo, append([]PRFile{{Path: catalogPath, Content: yamlContent}}, extraFiles...))
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 || changes[0].Path != catalogPath {
+		log.Printf("Catalog-info.yaml in %s is already up to date, skipping", repo.FullName)
+		return "", nil
+	}
+
+	message := "Add Harness IDP catalog-info.yaml"
+	if changes[0].Exists {
+		message = "Update Harness IDP catalog-info.yaml"
+	}
+	branch := c.baseBranch(repo)
+	commit, err := c.commitFiles(ctx, repo, branch, changes, message)
+	if err != nil {
+		return "", directCommitError(repo, branch, err)
+	}
+
+	if owner, repoName, err := parseFullName(repo.FullName); err == nil {
+		c.postCatalogStatus(ctx, owner, repoName, commit.GetSHA(), yamlContent)
+	}
+
+	url := commit.GetHTMLURL()
+	log.Printf("Committed %s to %s in %s: %s", catalogPath, branch, repo.FullName, url)
+	return url, nil
+}
+
+// checkDirectCommitAllowed rejects base branches whose protection requires pull
+// requests, status checks or push restrictions. Reading protection rules needs admin
+// access; without it the commit is attempted and GitHub has the final say.
+func (c *Client) checkDirectCommitAllowed(ctx context.Context, repo models.Repository) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	branch := c.baseBranch(repo)
+	protection, resp, err := c.client.Repositories.GetBranchProtection(ctx, owner, repoName, branch)
+	if err != nil {
+		if err == github.ErrBranchNotProtected || (resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 403)) {
+			return nil
+		}
+		return fmt.Errorf("failed to read branch protection: %w", err)
+	}
+
+	if protection.RequiredPullRequestReviews != nil || protection.RequiredStatusChecks != nil || protection.Restrictions != nil {
+		return errors.NewBranchProtectedError(repo.FullName, branch, nil)
+	}
+	return nil
+}
+
+// directCommitError reports commits rejected by protection rules checkDirectCommitAllowed
+// couldn't see as branch protection errors
+func directCommitError(repo models.Repository, branch string, err error) error {
+	if strings.Contains(strings.ToLower(err.Error()), "protected branch") {
+		return errors.NewBranchProtectedError(repo.FullName, branch, err)
+	}
+	return err
+}
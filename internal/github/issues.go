@@ -0,0 +1,85 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// onboardingIssueTitle identifies fallback issues so reruns don't open duplicates
+const onboardingIssueTitle = "Add Harness IDP catalog-info.yaml"
+
+// CreateOnboardingIssue opens an issue containing the generated catalog file and
+// instructions for committing it, for repositories where a PR couldn't be opened. An
+// open fallback issue is reused, and its URL is returned either way.
+func (c *Client) CreateOnboardingIssue(ctx context.Context, repo models.Repository, catalogPath, yamlContent, reason string) (string, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := c.openOnboardingIssue(ctx, owner, repoName)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		log.Printf("Repository %s already has onboarding issue #%d", repo.FullName, existing.GetNumber())
+		return existing.GetHTMLURL(), nil
+	}
+
+	body := fmt.Sprintf("harness-onboarder could not open a pull request adding `%s` to this repository (%s).\n\n"+
+		"To onboard it to Harness IDP, commit the following content to `%s` on `%s`:\n\n"+
+		"```yaml\n%s\n```\n\n"+
+		"Once it is merged, the component will be registered on the next run. Feel free to adjust the owner, type, lifecycle and tags first.\n\n"+
+		"Auto-generated by harness-onboarder tool.",
+		catalogPath, reason, catalogPath, c.baseBranch(repo), yamlContent)
+	body = withMarker(body)
+
+	request := &github.IssueRequest{
+		Title: github.String(onboardingIssueTitle),
+		Body:  &body,
+	}
+	if len(c.config.PRLabels) > 0 {
+		request.Labels = &c.config.PRLabels
+	}
+	if len(c.config.PRAssignees) > 0 {
+		request.Assignees = &c.config.PRAssignees
+	}
+
+	issue, _, err := c.client.Issues.Create(ctx, owner, repoName, request)
+	if err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	log.Printf("Opened onboarding issue #%d for %s: %s", issue.GetNumber(), repo.FullName, issue.GetHTMLURL())
+	return issue.GetHTMLURL(), nil
+}
+
+// openOnboardingIssue pages through the open issues for a fallback issue opened by an
+// earlier run, recognized by its marker or, for older issues, its title
+func (c *Client) openOnboardingIssue(ctx context.Context, owner, repoName string) (*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		issues, resp, err := c.client.Issues.ListByRepo(ctx, owner, repoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			if strings.Contains(issue.GetBody(), prMarker) || issue.GetTitle() == onboardingIssueTitle {
+				return issue, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+	"gopkg.in/yaml.v2"
+)
+
+// kubernetesDirs are the top-level directories searched for manifests and Helm charts
+var kubernetesDirs = []string{"k8s", "kubernetes", "deploy", "deployment", "manifests", "charts", "helm"}
+
+// maxManifests caps how many manifest files are read per repository
+const maxManifests = 5
+
+// workloadKinds are the Kubernetes kinds whose selector identifies the pods of a
+// component
+var workloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "Rollout"}
+
+// kubernetesInfo is what a repository's manifests say about where it runs
+type kubernetesInfo struct {
+	Selector  string // label selector of the pods, e.g. app=payments
+	Namespace string
+}
+
+// k8sObject is the part of a Kubernetes manifest read for kubernetesInfo
+type k8sObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Selector struct {
+			MatchLabels map[string]string `yaml:"matchLabels"`
+		} `yaml:"selector"`
+	} `yaml:"spec"`
+}
+
+// detectKubernetes reads the first workload manifests under kubernetesDirs for the
+// pods' label selector and namespace. Helm charts give an app.kubernetes.io/name
+// selector from Chart.yaml, since their templates can't be parsed. Manifests are only
+// found through the tree, so repositories without one aren't searched.
+func (c *Client) detectKubernetes(ctx context.Context, repo *github.Repository, tree *repoTree) kubernetesInfo {
+	var info kubernetesInfo
+	if tree == nil {
+		return info
+	}
+
+	read := 0
+	for _, file := range kubernetesFiles(tree) {
+		if info.Selector != "" && info.Namespace != "" || read == maxManifests {
+			break
+		}
+		content, ok := c.fileContent(ctx, repo, file)
+		read++
+		if !ok {
+			continue
+		}
+
+		if path.Base(file) == "Chart.yaml" {
+			var chart struct {
+				Name string `yaml:"name"`
+			}
+			if yaml.Unmarshal([]byte(content), &chart) == nil && chart.Name != "" && info.Selector == "" {
+				info.Selector = "app.kubernetes.io/name=" + chart.Name
+			}
+			continue
+		}
+		parseManifests(content, &info)
+	}
+	return info
+}
+
+// kubernetesFiles lists the manifest candidates in the tree: Chart.yaml files first,
+// then YAML files outside Helm templates, in path order
+func kubernetesFiles(tree *repoTree) []string {
+	var charts, manifests []string
+	for file := range tree.files {
+		top, _, _ := strings.Cut(file, "/")
+		if !contains(kubernetesDirs, top) || !strings.Contains(file, "/") {
+			continue
+		}
+		switch {
+		case path.Base(file) == "Chart.yaml":
+			charts = append(charts, file)
+		case strings.Contains(file, "/templates/"):
+			// Helm templates aren't valid YAML until rendered
+		case strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml"):
+			manifests = append(manifests, file)
+		}
+	}
+	sort.Strings(charts)
+	sort.Strings(manifests)
+	return append(charts, manifests...)
+}
+
+// parseManifests fills in info from the first workload and namespace in a
+// multi-document manifest file
+func parseManifests(content string, info *kubernetesInfo) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var obj k8sObject
+		if err := decoder.Decode(&obj); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("DEBUG: Skipping unparseable Kubernetes manifest: %v", err)
+			}
+			return
+		}
+
+		if obj.Kind == "Namespace" && info.Namespace == "" {
+			info.Namespace = obj.Metadata.Name
+		}
+		if !contains(workloadKinds, obj.Kind) {
+			continue
+		}
+		if info.Namespace == "" {
+			info.Namespace = obj.Metadata.Namespace
+		}
+		if info.Selector == "" {
+			labels := obj.Spec.Selector.MatchLabels
+			if len(labels) == 0 {
+				labels = obj.Metadata.Labels
+			}
+			info.Selector = labelSelector(labels)
+		}
+	}
+}
+
+// labelSelector writes labels as a selector in key order, e.g. app=payments,tier=api
+func labelSelector(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+labels[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+// fileContent reads a file from the default branch, logging failures other than a
+// missing file
+func (c *Client) fileContent(ctx context.Context, repo *github.Repository, file string) (string, bool) {
+	content, _, resp, err := c.client.Repositories.GetContents(ctx, repo.GetOwner().GetLogin(), repo.GetName(), file, nil)
+	if err != nil {
+		if resp == nil || resp.StatusCode != 404 {
+			log.Printf("Warning: error reading %s in %s: %v", file, repo.GetFullName(), err)
+		}
+		return "", false
+	}
+	if content == nil {
+		return "", false
+	}
+	text, err := content.GetContent()
+	if err != nil {
+		log.Printf("Warning: error decoding %s in %s: %v", file, repo.GetFullName(), err)
+		return "", false
+	}
+	return text, true
+}
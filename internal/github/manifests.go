@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+)
+
+// moduleManifest is what a dependency manifest says a repository publishes and uses
+type moduleManifest struct {
+	Modules      []string
+	Dependencies []string
+}
+
+// manifestParsers maps manifest files at the repository root to their parsers
+var manifestParsers = map[string]func(string) moduleManifest{
+	"go.mod":       parseGoMod,
+	"package.json": parsePackageJSON,
+	"pom.xml":      parsePomXML,
+}
+
+// detectModules reads the dependency manifests in a repository. With a tree, only
+// manifests that exist are read.
+func (c *Client) detectModules(ctx context.Context, repo *github.Repository, tree *repoTree) moduleManifest {
+	var result moduleManifest
+
+	for path, parse := range manifestParsers {
+		if tree != nil && !tree.exists(path) {
+			continue
+		}
+		content, _, resp, err := c.client.Repositories.GetContents(ctx, repo.GetOwner().GetLogin(), repo.GetName(), path, nil)
+		if err != nil {
+			if resp == nil || resp.StatusCode != 404 {
+				log.Printf("Warning: error reading %s in %s: %v", path, repo.GetFullName(), err)
+			}
+			continue
+		}
+		if content == nil {
+			continue
+		}
+
+		text, err := content.GetContent()
+		if err != nil {
+			log.Printf("Warning: error decoding %s in %s: %v", path, repo.GetFullName(), err)
+			continue
+		}
+
+		manifest := parse(text)
+		result.Modules = append(result.Modules, manifest.Modules...)
+		result.Dependencies = append(result.Dependencies, manifest.Dependencies...)
+	}
+
+	return result
+}
+
+func parseGoMod(content string) moduleManifest {
+	var m moduleManifest
+	inRequire := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "module" && len(fields) > 1:
+			m.Modules = append(m.Modules, strings.Trim(fields[1], `"`))
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) > 1:
+			m.Dependencies = append(m.Dependencies, fields[1])
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire:
+			m.Dependencies = append(m.Dependencies, fields[0])
+		}
+	}
+
+	return m
+}
+
+func parsePackageJSON(content string) moduleManifest {
+	var pkg struct {
+		Name                 string            `json:"name"`
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+
+	var m moduleManifest
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return m
+	}
+
+	if pkg.Name != "" {
+		m.Modules = append(m.Modules, pkg.Name)
+	}
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name := range deps {
+			m.Dependencies = append(m.Dependencies, name)
+		}
+	}
+
+	return m
+}
+
+func parsePomXML(content string) moduleManifest {
+	type artifact struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+	}
+	var pom struct {
+		artifact
+		Parent       artifact   `xml:"parent"`
+		Dependencies []artifact `xml:"dependencies>dependency"`
+	}
+
+	var m moduleManifest
+	if err := xml.Unmarshal([]byte(content), &pom); err != nil {
+		return m
+	}
+
+	groupID := pom.GroupID
+	if groupID == "" {
+		groupID = pom.Parent.GroupID
+	}
+	if pom.ArtifactID != "" {
+		m.Modules = append(m.Modules, groupID+":"+pom.ArtifactID)
+	}
+	for _, dep := range pom.Dependencies {
+		if dep.ArtifactID != "" {
+			m.Dependencies = append(m.Dependencies, dep.GroupID+":"+dep.ArtifactID)
+		}
+	}
+
+	return m
+}
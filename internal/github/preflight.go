@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// installationPermissions returns the contents and pull request permission levels of
+// the app installation token, fetching them once per client
+func (c *Client) installationPermissions(ctx context.Context) (string, string, error) {
+	c.permissionsOnce.Do(func() {
+		if _, err := c.transport.Token(ctx); err != nil {
+			c.permissionsErr = fmt.Errorf("failed to get installation token: %w", err)
+			return
+		}
+		perms, err := c.transport.Permissions()
+		if err != nil {
+			c.permissionsErr = err
+			return
+		}
+		c.contentsPermission = perms.GetContents()
+		c.pullRequestsPermission = perms.GetPullRequests()
+	})
+	return c.contentsPermission, c.pullRequestsPermission, c.permissionsErr
+}
+
+// PreflightPR checks that the app can open an onboarding PR in the repository, or
+// commit to its base branch when direct is set, before any branch is created. It
+// returns a PR_BLOCKED or BRANCH_PROTECTED error with remediation hints when it can't.
+func (c *Client) PreflightPR(ctx context.Context, repo models.Repository, direct bool) error {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return err
+	}
+
+	if repo.Archived {
+		return errors.NewPRBlockedError(repo.FullName, "the repository is archived", "Unarchive it or exclude it from onboarding.")
+	}
+
+	contents, pullRequests, err := c.installationPermissions(ctx)
+	if err != nil {
+		// Permissions are only a hint; GitHub still enforces them on the actual requests
+		log.Printf("Warning: could not read GitHub App permissions: %v", err)
+	} else {
+		if contents != "write" {
+			return errors.NewPRBlockedError(repo.FullName, "the GitHub App can't push to repositories (Contents permission is "+permissionLevel(contents)+")",
+				"Grant the app Contents: Read and write and approve the updated permissions on the installation.")
+		}
+		if !direct && pullRequests != "write" {
+			return errors.NewPRBlockedError(repo.FullName, "the GitHub App can't open pull requests (Pull requests permission is "+permissionLevel(pullRequests)+")",
+				"Grant the app Pull requests: Read and write and approve the updated permissions on the installation.")
+		}
+	}
+
+	base := c.baseBranch(repo)
+	_, resp, err := c.client.Repositories.GetBranch(ctx, owner, repoName, base, true)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			if base != repo.DefaultBranch {
+				return errors.NewPRBlockedError(repo.FullName, fmt.Sprintf("the base branch %s doesn't exist", base),
+					"Create the branch or set a different base branch for this repository.")
+			}
+			return errors.NewPRBlockedError(repo.FullName, fmt.Sprintf("the default branch %s doesn't exist", base),
+				"Push an initial commit to the repository first.")
+		}
+		return fmt.Errorf("failed to get base branch: %w", err)
+	}
+
+	if direct {
+		return c.checkDirectCommitAllowed(ctx, repo)
+	}
+	return nil
+}
+
+func permissionLevel(level string) string {
+	if level == "" {
+		return "none"
+	}
+	return level
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// PRFile is one file committed by an onboarding change
+type PRFile struct {
+	Path    string
+	Content string
+
+	// CreateOnly leaves the file alone when it already exists
+	CreateOnly bool
+
+	// Edit, when set, derives the new content from the current file ("" when the file
+	// doesn't exist) and replaces Content. Returning the input unchanged skips the file.
+	Edit func(existing string) string
+}
+
+// fileChange is a PRFile resolved against the base branch
+type fileChange struct {
+	Path    string
+	Content string
+	Exists  bool
+}
+
+// resolveFiles reads each file from the base branch and returns those whose content
+// would change, in the order given
+func (c *Client) resolveFiles(ctx context.Context, repo models.Repository, files []PRFile) ([]fileChange, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &github.RepositoryContentGetOptions{Ref: c.baseBranch(repo)}
+	var changes []fileChange
+	for _, file := range files {
+		var existing string
+		current, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repoName, file.Path, ref)
+		exists := err == nil && current != nil
+		if err != nil && (resp == nil || resp.StatusCode != 404) {
+			return nil, fmt.Errorf("failed to check %s: %w", file.Path, err)
+		}
+		if exists {
+			if file.CreateOnly {
+				continue
+			}
+			existing, err = current.GetContent()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get existing content of %s: %w", file.Path, err)
+			}
+		}
+
+		content := file.Content
+		if file.Edit != nil {
+			content = file.Edit(existing)
+			if content == existing {
+				continue
+			}
+		}
+		if exists && strings.TrimSpace(content) == strings.TrimSpace(existing) {
+			continue
+		}
+		changes = append(changes, fileChange{Path: file.Path, Content: content, Exists: exists})
+	}
+	return changes, nil
+}
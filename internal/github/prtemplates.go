@@ -0,0 +1,48 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"harness-onboarder/internal/models"
+)
+
+// PRTemplateData is what PR title and body templates are rendered against. Repository
+// fields such as .Name, .FullName and .HTMLURL are available directly.
+type PRTemplateData struct {
+	models.Repository
+	Update      bool     // true when an existing catalog file is being updated
+	CatalogPath string   // path of the catalog file in the PR
+	AddedFiles  []string // other files the PR adds or edits, e.g. TechDocs scaffolding
+}
+
+// loadPRTemplate parses a PR title or body template file; an empty path returns nil
+func loadPRTemplate(name, path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PR %s template: %w", name, err)
+	}
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"join": strings.Join}).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR %s template %s: %w", name, path, err)
+	}
+	return tmpl, nil
+}
+
+// renderPRTemplate renders tmpl, returning fallback when there is no template
+func renderPRTemplate(tmpl *template.Template, data PRTemplateData, fallback string) (string, error) {
+	if tmpl == nil {
+		return fallback, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render PR %s template: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,186 @@
+package github
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter paces GitHub requests from the X-RateLimit-* headers of earlier
+// responses. Once less than a quarter of a resource's limit is left, requests are
+// spread evenly over the rest of the window; when it's nearly used up they pause
+// until the reset instead of running into 403s mid-run.
+type rateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+// rateWindow is the state of one rate limit resource, such as core or search
+type rateWindow struct {
+	limit     int
+	remaining int
+	reset     time.Time
+	next      time.Time // earliest start of the next request while spreading
+	paused    bool      // the pause until reset was logged
+	retryAt   time.Time // end of a secondary rate limit's Retry-After
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{windows: make(map[string]*rateWindow)}
+}
+
+// rateLimitTransport waits for the limiter before each request and feeds it the
+// rate limit headers of each response
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := rateResource(req.URL.Path)
+	if err := t.limiter.wait(req, resource); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.limiter.observe(resp.Header, resource)
+		t.limiter.observeRetryAfter(resp, resource)
+	}
+	return resp, err
+}
+
+// rateResource guesses the rate limit resource of a request before its response
+// names it
+func rateResource(path string) string {
+	switch {
+	case strings.Contains(path, "/search/code"):
+		return "code_search"
+	case strings.Contains(path, "/search/"):
+		return "search"
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	default:
+		return "core"
+	}
+}
+
+// wait blocks until the request may start, or its context is done
+func (l *rateLimiter) wait(req *http.Request, resource string) error {
+	l.mu.Lock()
+	start := time.Now()
+	if w := l.windows[resource]; w != nil {
+		start = w.schedule(start, resource)
+	}
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// schedule returns when a request may start and counts it against the window, so
+// concurrent workers are spread rather than all waiting the same delay
+func (w *rateWindow) schedule(now time.Time, resource string) time.Time {
+	if now.Before(w.retryAt) {
+		return w.retryAt
+	}
+	// The window has reset; the next response reports the new one
+	if w.limit == 0 || !now.Before(w.reset) {
+		return now
+	}
+
+	start := now
+	if w.next.After(start) {
+		start = w.next
+	}
+
+	reserve := w.limit / 50
+	if reserve < 1 {
+		reserve = 1
+	}
+	switch {
+	case w.remaining <= reserve:
+		if !w.paused {
+			log.Printf("GitHub %s rate limit nearly used up (%d of %d left), pausing until %s", resource, w.remaining, w.limit, w.reset.Format(time.TimeOnly))
+			w.paused = true
+		}
+		start = w.reset.Add(time.Second)
+	case w.remaining < w.limit/4:
+		w.next = start.Add(w.reset.Sub(now) / time.Duration(w.remaining))
+	}
+
+	if w.remaining > 0 {
+		w.remaining--
+	}
+	return start
+}
+
+// observe records the rate limit headers of a response
+func (l *rateLimiter) observe(header http.Header, resource string) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	if name := header.Get("X-RateLimit-Resource"); name != "" {
+		resource = name
+	}
+	reset := time.Unix(resetUnix, 0)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := l.windows[resource]
+	if w == nil {
+		w = &rateWindow{}
+		l.windows[resource] = w
+	}
+	if !reset.Equal(w.reset) {
+		w.paused = false
+		w.next = time.Time{}
+	}
+	w.limit, w.remaining, w.reset = limit, remaining, reset
+}
+
+// observeRetryAfter holds back every request to the resource for the Retry-After of
+// a secondary rate limit response, since GitHub counts further requests against it
+func (l *rateLimiter) observeRetryAfter(resp *http.Response, resource string) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return
+	}
+	retryAt := time.Now().Add(time.Duration(seconds) * time.Second)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w := l.windows[resource]
+	if w == nil {
+		w = &rateWindow{}
+		l.windows[resource] = w
+	}
+	if retryAt.After(w.retryAt) {
+		log.Printf("GitHub secondary rate limit hit, holding %s requests for %ds", resource, seconds)
+		w.retryAt = retryAt
+	}
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// codeSearchLimit is the most results GitHub returns for one code or repository
+// search query
+const codeSearchLimit = 1000
+
+// CatalogIndex maps repository full names to the paths of the catalog files code
+// search found in them. Complete is false when GitHub returned partial results, so
+// repositories missing from Paths may still have a catalog file.
+type CatalogIndex struct {
+	Paths    map[string][]string
+	Complete bool
+}
+
+// SearchCatalogFiles runs one code search per file name over the organization's
+// default branches, instead of probing every repository for each catalog path
+func (c *Client) SearchCatalogFiles(ctx context.Context, org string, filenames []string) (*CatalogIndex, error) {
+	index := &CatalogIndex{Paths: make(map[string][]string), Complete: true}
+	for _, filename := range filenames {
+		query := fmt.Sprintf("filename:%s org:%s", filename, org)
+		opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			result, resp, err := c.client.Search.Code(ctx, query, opts)
+			if err != nil {
+				return nil, fmt.Errorf("code search %q failed: %w", query, err)
+			}
+			if result.GetIncompleteResults() || result.GetTotal() > codeSearchLimit {
+				index.Complete = false
+			}
+			for _, file := range result.CodeResults {
+				repo := file.GetRepository().GetFullName()
+				if !contains(index.Paths[repo], file.GetPath()) {
+					index.Paths[repo] = append(index.Paths[repo], file.GetPath())
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	log.Printf("Code search found catalog files in %d repositories of %s", len(index.Paths), org)
+	if !index.Complete {
+		log.Printf("Warning: code search results for %s are incomplete; repositories without a match will be probed", org)
+	}
+	return index, nil
+}
+
+// SearchRepositories returns the repositories matching a GitHub search query, limited to
+// the organization unless the query has its own org:, user: or repo: qualifier. GitHub
+// returns at most 1000 results per query.
+func (c *Client) SearchRepositories(ctx context.Context, org, query string, enrich bool) ([]models.Repository, error) {
+	if !hasScopeQualifier(query) {
+		query = strings.TrimSpace(query + " org:" + org)
+	}
+
+	var repos []models.Repository
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := c.client.Search.Repositories(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("repository search %q failed: %w", query, err)
+		}
+		for _, repo := range result.Repositories {
+			if !enrich {
+				repos = append(repos, basicRepository(repo))
+				continue
+			}
+			modelRepo, err := c.enrichRepository(ctx, repo)
+			if err != nil {
+				log.Printf("Warning: failed to enrich repository %s: %v", repo.GetFullName(), err)
+				continue
+			}
+			repos = append(repos, modelRepo)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	log.Printf("Search %q matched %d repositories", query, len(repos))
+	if len(repos) >= codeSearchLimit {
+		log.Printf("Warning: GitHub returns at most %d search results; narrow the query to cover the rest", codeSearchLimit)
+	}
+	return repos, nil
+}
+
+// hasScopeQualifier reports whether a search query already limits itself to an owner
+// or repository
+func hasScopeQualifier(query string) bool {
+	for _, term := range strings.Fields(query) {
+		term = strings.ToLower(term)
+		if strings.HasPrefix(term, "org:") || strings.HasPrefix(term, "user:") || strings.HasPrefix(term, "repo:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/catalog"
+)
+
+// catalogStatusContext names the commit status posted on onboarding commits
+const catalogStatusContext = "harness-onboarder/catalog"
+
+// postCatalogStatus lints the committed catalog content and reports the outcome as a
+// commit status on sha, when enabled. Failing to post is logged rather than returned,
+// since the commit itself already succeeded.
+func (c *Client) postCatalogStatus(ctx context.Context, owner, repoName, sha, content string) {
+	if !c.config.CommitStatus || sha == "" {
+		return
+	}
+
+	state, description := catalogStatus(catalog.Lint(content))
+	_, _, err := c.client.Repositories.CreateStatus(ctx, owner, repoName, sha, &github.RepoStatus{
+		State:       github.String(state),
+		Description: github.String(description),
+		Context:     github.String(catalogStatusContext),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to post catalog status on %s/%s@%s: %v", owner, repoName, sha, err)
+	}
+}
+
+// catalogStatus turns lint issues into a commit status state and description. GitHub
+// truncates descriptions at 140 characters, so only the first error is named.
+func catalogStatus(issues []catalog.Issue) (string, string) {
+	if catalog.HasErrors(issues) {
+		for _, issue := range issues {
+			if issue.Severity == catalog.SeverityError {
+				description := issue.String()
+				if len(description) > 140 {
+					description = description[:137] + "..."
+				}
+				return "failure", description
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return "success", fmt.Sprintf("catalog validated with %d warnings", len(issues))
+	}
+	return "success", "catalog validated"
+}
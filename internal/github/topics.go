@@ -0,0 +1,30 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"harness-onboarder/internal/models"
+)
+
+// AddTopic adds a topic to the repository, keeping its existing topics. It returns
+// false when the repository already has the topic.
+func (c *Client) AddTopic(ctx context.Context, repo models.Repository, topic string) (bool, error) {
+	owner, repoName, err := parseFullName(repo.FullName)
+	if err != nil {
+		return false, err
+	}
+
+	topics, _, err := c.client.Repositories.ListAllTopics(ctx, owner, repoName)
+	if err != nil {
+		return false, fmt.Errorf("failed to list topics: %w", err)
+	}
+	if contains(topics, topic) {
+		return false, nil
+	}
+
+	if _, _, err := c.client.Repositories.ReplaceAllTopics(ctx, owner, repoName, append(topics, topic)); err != nil {
+		return false, fmt.Errorf("failed to add topic %s: %w", topic, err)
+	}
+	return true, nil
+}
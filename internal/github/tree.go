@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+
+	"harness-onboarder/internal/models"
+)
+
+// repoTree holds the paths of a repository's default branch, fetched with one
+// recursive tree call so enrichment doesn't probe each candidate file separately
+type repoTree struct {
+	files map[string]bool
+	dirs  map[string]bool
+}
+
+// getTree fetches the default branch's tree. It returns nil when the tree can't be
+// read or GitHub truncated it, in which case enrichment probes paths one by one.
+func (c *Client) getTree(ctx context.Context, repo *github.Repository) *repoTree {
+	tree, _, err := c.client.Git.GetTree(ctx, repo.GetOwner().GetLogin(), repo.GetName(), repo.GetDefaultBranch(), true)
+	if err != nil {
+		log.Printf("DEBUG: Could not read the tree of %s, probing files instead: %v", repo.GetFullName(), err)
+		return nil
+	}
+	if tree.GetTruncated() {
+		log.Printf("DEBUG: Tree of %s is too large to read at once, probing files instead", repo.GetFullName())
+		return nil
+	}
+
+	t := &repoTree{files: make(map[string]bool), dirs: make(map[string]bool)}
+	for _, entry := range tree.Entries {
+		switch entry.GetType() {
+		case "blob":
+			t.files[entry.GetPath()] = true
+		case "tree":
+			t.dirs[entry.GetPath()] = true
+		}
+	}
+	return t
+}
+
+// exists reports whether a file, or a directory written with a trailing slash, is in
+// the tree
+func (t *repoTree) exists(p string) bool {
+	if dir, ok := strings.CutSuffix(p, "/"); ok {
+		return t.dirs[dir]
+	}
+	return t.files[p] || t.dirs[p]
+}
+
+// anyExists is checkPathsExist answered from the tree: paths with * match any file
+// the same way checkGlobPattern does
+func (t *repoTree) anyExists(paths []string) bool {
+	for _, p := range paths {
+		if !strings.Contains(p, "*") {
+			if t.exists(p) {
+				return true
+			}
+			continue
+		}
+		re, err := regexp.Compile(strings.ReplaceAll(p, "*", ".*"))
+		if err != nil {
+			continue
+		}
+		for file := range t.files {
+			if re.MatchString(file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// signals answers detectRepositorySignals from the tree
+func (t *repoTree) signals() *repositorySignals {
+	signals := &repositorySignals{}
+	for _, p := range dockerPaths {
+		if t.exists(p) {
+			signals.HasDockerfile = true
+			break
+		}
+	}
+	signals.HasKubernetes = t.anyExists(kubernetesPaths)
+	for _, system := range ciSystems {
+		if t.anyExists(system.paths) {
+			signals.CISystems = append(signals.CISystems, system.name)
+		}
+	}
+	return signals
+}
+
+// rootSignals answers DetectRootSignals from the tree
+func (t *repoTree) rootSignals(repo *models.Repository) {
+	files := make(map[string]bool)
+	for file := range t.files {
+		if !strings.Contains(file, "/") {
+			files[file] = true
+		}
+	}
+	applyRootSignals(repo, files, t.dirs["docs"])
+}
+
+// catalogFiles returns the catalog paths present in the tree, in search order
+func (t *repoTree) catalogFiles(paths []string) []string {
+	found := []string{}
+	for _, p := range paths {
+		if t.files[path.Clean(p)] {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// SetCatalogPaths sets the catalog file locations enrichment looks for in the tree,
+// in search order, so later lookups can skip paths that don't exist
+func (c *Client) SetCatalogPaths(paths []string) {
+	c.catalogPaths = paths
+}
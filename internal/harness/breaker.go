@@ -0,0 +1,81 @@
+package harness
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Defaults for the circuit breaker when the config leaves them unset
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Minute
+)
+
+// circuitBreaker pauses requests to the Harness API once it has failed with server
+// errors or timeouts several times in a row, rather than letting every worker burn
+// through its repositories collecting identical failures. After the cool-down requests
+// resume; the next failure trips it again straight away, a success closes it.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int       // consecutive failures
+	openUntil time.Time // zero while closed
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// wait blocks while the breaker is open, returning early only when ctx is done
+func (b *circuitBreaker) wait(ctx context.Context) error {
+	b.mu.Lock()
+	until := b.openUntil
+	b.mu.Unlock()
+
+	delay := time.Until(until)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record notes the outcome of a request; failed means a 5xx response, a timeout or a
+// connection error
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if !b.openUntil.IsZero() {
+			log.Printf("Harness API is responding again, resuming")
+		}
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+
+	b.failures++
+	if b.failures < b.threshold || time.Now().Before(b.openUntil) {
+		return
+	}
+	b.openUntil = time.Now().Add(b.cooldown)
+	log.Printf("Harness API failed %d times in a row (server errors or timeouts); pausing all requests for %s until %s",
+		b.failures, b.cooldown, b.openUntil.Format(time.TimeOnly))
+}
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v2"
 	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/httplog"
+	"harness-onboarder/internal/network"
 	"harness-onboarder/internal/models"
 )
 
@@ -21,24 +26,7 @@ type Client struct {
 	httpClient *http.Client
 	config     models.HarnessConfig
 	baseURL    *url.URL
-}
-
-type ComponentCreateRequest struct {
-	Component models.HarnessComponent `json:"component"`
-}
-
-type ComponentResponse struct {
-	Status    string                  `json:"status"`
-	Component models.HarnessComponent `json:"component,omitempty"`
-	Error     string                  `json:"error,omitempty"`
-	Message   string                  `json:"message,omitempty"`
-}
-
-type ListComponentsResponse struct {
-	Status     string                    `json:"status"`
-	Components []models.HarnessComponent `json:"components,omitempty"`
-	Total      int                       `json:"total"`
-	Error      string                    `json:"error,omitempty"`
+	breaker    *circuitBreaker
 }
 
 type EntityImportRequest struct {
@@ -53,6 +41,20 @@ type EntityImportRequest struct {
 	ProjectIdentifier string `json:"projectIdentifier"`
 }
 
+// EntityResponse is an entity as returned by the IDP 2.0 entities API; YAML holds its
+// full definition
+type EntityResponse struct {
+	Identifier  string   `json:"identifier"`
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Type        string   `json:"type"`
+	Owner       string   `json:"owner"`
+	Lifecycle   string   `json:"lifecycle"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	YAML        string   `json:"yaml"`
+}
+
 type CatalogLocationResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message,omitempty"`
@@ -64,9 +66,9 @@ type CatalogEntity struct {
 	Identifier        string `yaml:"identifier"`
 	Name              string `yaml:"name"`
 	Kind              string `yaml:"kind"`
-	Type              string `yaml:"type"`
-	ProjectIdentifier string `yaml:"projectIdentifier"`
-	OrgIdentifier     string `yaml:"orgIdentifier"`
+	Type              string `yaml:"type,omitempty"`
+	ProjectIdentifier string `yaml:"projectIdentifier,omitempty"`
+	OrgIdentifier     string `yaml:"orgIdentifier,omitempty"`
 	Owner             string `yaml:"owner"`
 	Metadata          struct {
 		Description string            `yaml:"description,omitempty"`
@@ -79,32 +81,78 @@ type CatalogEntity struct {
 			Type  string `yaml:"type,omitempty"`
 		} `yaml:"links,omitempty"`
 	} `yaml:"metadata,omitempty"`
-	Spec struct {
-		Lifecycle string `yaml:"lifecycle"`
-	} `yaml:"spec"`
+	Spec EntitySpec `yaml:"spec"`
+}
+
+// EntitySpec holds the spec fields of every kind componentToYAML writes; fields a kind
+// doesn't use are left empty. Definition is an API's definition, a {$text: url}
+// reference when written by the onboarder.
+type EntitySpec struct {
+	Lifecycle    string      `yaml:"lifecycle,omitempty"`
+	System       string      `yaml:"system,omitempty"`
+	Domain       string      `yaml:"domain,omitempty"`
+	DependsOn    []string    `yaml:"dependsOn,omitempty"`
+	ProvidesAPIs []string    `yaml:"providesApis,omitempty"`
+	Definition   interface{} `yaml:"definition,omitempty"`
+	Members      []string    `yaml:"members,omitempty"`
 }
 
+// Transport defaults for settings the config leaves unset
+const (
+	defaultTimeout             = 30 * time.Second
+	defaultMaxIdleConns        = 10
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
 func NewClient(config models.HarnessConfig) (*Client, error) {
 	baseURL, err := url.Parse(config.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
 
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxIdleConns := config.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+	tlsHandshakeTimeout := config.TLSHandshakeTimeout
+	if tlsHandshakeTimeout <= 0 {
+		tlsHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+
+	transport := network.Transport()
+	transport.MaxIdleConns = maxIdleConns
+	transport.MaxIdleConnsPerHost = batchConcurrency // keep a connection per in-flight batch request
+	transport.IdleConnTimeout = 30 * time.Second
+	transport.TLSHandshakeTimeout = tlsHandshakeTimeout
+
 	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:    10,
-			IdleConnTimeout: 30 * time.Second,
-		},
+		Timeout:   timeout,
+		Transport: httplog.Wrap(transport, "harness"),
 	}
 
 	return &Client{
 		httpClient: httpClient,
 		config:     config,
 		baseURL:    baseURL,
+		breaker:    newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
 	}, nil
 }
 
+// WithProject returns a client for another org and project that shares this client's
+// connections and circuit breaker
+func (c *Client) WithProject(orgID, projectID string) *Client {
+	clone := *c
+	clone.config.OrgID = orgID
+	clone.config.ProjectID = projectID
+	return &clone
+}
+
+// CreateComponent creates a component, or updates it when it already exists. An
+// existing entity that already matches the component returns ErrUnchanged.
 func (c *Client) CreateComponent(ctx context.Context, component models.HarnessComponent) error {
 	if err := c.validateComponent(component); err != nil {
 		return &errors.ProcessingError{
@@ -117,10 +165,10 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 		}
 	}
 
-	existing, err := c.GetComponent(ctx, component.Identifier)
+	existing, err := c.GetEntity(ctx, componentKind(component), component.Identifier)
 	if err == nil && existing != nil {
 		log.Printf("Component %s (identifier: %s) already exists, updating instead", component.Name, component.Identifier)
-		return c.UpdateComponent(ctx, component)
+		return c.updateChanged(ctx, existing, component)
 	}
 
 	// Convert component to YAML string for the new API format
@@ -129,6 +177,257 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 		return fmt.Errorf("failed to convert component to YAML: %w", err)
 	}
 
+	if err := c.createEntity(ctx, yamlData, component.Identifier); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully created component: %s (identifier: %s)", component.Name, component.Identifier)
+	return nil
+}
+
+// batchConcurrency caps the entity requests CreateComponents has in flight at once
+const batchConcurrency = 8
+
+// CreateComponents creates many components, returning one error per component in the
+// same order (nil for success). The entities API takes one entity per request, so the
+// requests are pipelined over shared keep-alive connections, and the existence check
+// CreateComponent does up front is skipped: an entity that already exists is updated
+// instead, costing one request for new entities rather than two. Components that
+// already match their entity return ErrUnchanged.
+func (c *Client) CreateComponents(ctx context.Context, components []models.HarnessComponent) []error {
+	errs := make([]error, len(components))
+	semaphore := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, component := range components {
+		wg.Add(1)
+		go func(i int, component models.HarnessComponent) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			errs[i] = c.createOrUpdateComponent(ctx, component)
+		}(i, component)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil || err == ErrUnchanged {
+			created++
+		}
+	}
+	log.Printf("Created, updated or left unchanged %d of %d components in batch", created, len(components))
+	return errs
+}
+
+// createOrUpdateComponent creates a component, updating it when it already exists
+func (c *Client) createOrUpdateComponent(ctx context.Context, component models.HarnessComponent) error {
+	if err := c.validateComponent(component); err != nil {
+		return &errors.ProcessingError{
+			Category:     errors.ErrorCategoryValidation,
+			Type:         errors.ErrorTypeEntityValidationFailed,
+			Message:      fmt.Sprintf("component validation failed: %s", err.Error()),
+			Cause:        err,
+			Recoverable:  false,
+			UserFriendly: fmt.Sprintf("Component validation failed: %s", err.Error()),
+		}
+	}
+
+	yamlData, err := c.componentToYAML(component)
+	if err != nil {
+		return fmt.Errorf("failed to convert component to YAML: %w", err)
+	}
+
+	err = c.createEntity(ctx, yamlData, component.Identifier)
+	if procErr, ok := err.(*errors.ProcessingError); ok && procErr.Type == errors.ErrorTypeEntityExists {
+		log.Printf("Component %s (identifier: %s) already exists, updating instead", component.Name, component.Identifier)
+		return c.UpdateComponentIfChanged(ctx, component)
+	}
+	return err
+}
+
+// CreateEntityYAML creates an entity directly from harness.io/v1 YAML content
+func (c *Client) CreateEntityYAML(ctx context.Context, yamlData string) error {
+	identifier, err := ExtractEntityIdentifier(yamlData)
+	if err != nil {
+		return fmt.Errorf("invalid entity YAML: %w", err)
+	}
+
+	if err := c.createEntity(ctx, yamlData, identifier); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully created entity: %s", identifier)
+	return nil
+}
+
+// CreateEntity creates a generic entity such as a System or Domain at the configured scope
+func (c *Client) CreateEntity(ctx context.Context, entity models.HarnessEntity) error {
+	orgID, projectID := ScopeIdentifiers(c.config)
+	yamlData, err := EntityYAML(entity, orgID, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.createEntity(ctx, yamlData, entity.Identifier); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully created %s: %s (identifier: %s)", entity.Kind, entity.Name, entity.Identifier)
+	return nil
+}
+
+// EntityYAML renders a generic entity as harness.io/v1 YAML in the given scope
+func EntityYAML(entity models.HarnessEntity, orgID, projectID string) (string, error) {
+	doc := yaml.MapSlice{
+		{Key: "apiVersion", Value: "harness.io/v1"},
+		{Key: "kind", Value: entity.Kind},
+	}
+	if entity.Type != "" {
+		doc = append(doc, yaml.MapItem{Key: "type", Value: entity.Type})
+	}
+	doc = append(doc,
+		yaml.MapItem{Key: "identifier", Value: entity.Identifier},
+		yaml.MapItem{Key: "name", Value: entity.Name},
+	)
+	if orgID != "" {
+		doc = append(doc, yaml.MapItem{Key: "orgIdentifier", Value: orgID})
+	}
+	if projectID != "" {
+		doc = append(doc, yaml.MapItem{Key: "projectIdentifier", Value: projectID})
+	}
+	doc = append(doc, yaml.MapItem{Key: "owner", Value: entity.Owner})
+
+	metadata := yaml.MapSlice{}
+	if entity.Description != "" {
+		metadata = append(metadata, yaml.MapItem{Key: "description", Value: entity.Description})
+	}
+	if len(entity.Tags) > 0 {
+		metadata = append(metadata, yaml.MapItem{Key: "tags", Value: entity.Tags})
+	}
+	if len(entity.Annotations) > 0 {
+		metadata = append(metadata, yaml.MapItem{Key: "annotations", Value: entity.Annotations})
+	}
+	if len(metadata) > 0 {
+		doc = append(doc, yaml.MapItem{Key: "metadata", Value: metadata})
+	}
+	if len(entity.Spec) > 0 {
+		doc = append(doc, yaml.MapItem{Key: "spec", Value: entity.Spec})
+	}
+
+	yamlData, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert %s to YAML: %w", entity.Kind, err)
+	}
+	return string(yamlData), nil
+}
+
+// GroupRef returns the owner reference for an account-level Group entity
+func GroupRef(identifier string) string {
+	return "group:account/" + identifier
+}
+
+// CreateGroup creates an account-level Group entity so components in any project can
+// reference it as their owner
+func (c *Client) CreateGroup(ctx context.Context, group models.HarnessGroup) error {
+	entity := yaml.MapSlice{
+		{Key: "apiVersion", Value: "harness.io/v1"},
+		{Key: "kind", Value: "Group"},
+		{Key: "type", Value: "team"},
+		{Key: "identifier", Value: group.Identifier},
+		{Key: "name", Value: group.Name},
+	}
+
+	metadata := yaml.MapSlice{}
+	if group.Description != "" {
+		metadata = append(metadata, yaml.MapItem{Key: "description", Value: group.Description})
+	}
+	if len(group.Annotations) > 0 {
+		metadata = append(metadata, yaml.MapItem{Key: "annotations", Value: group.Annotations})
+	}
+	if len(metadata) > 0 {
+		entity = append(entity, yaml.MapItem{Key: "metadata", Value: metadata})
+	}
+
+	spec := yaml.MapSlice{}
+	if group.Parent != "" {
+		spec = append(spec, yaml.MapItem{Key: "parent", Value: GroupRef(group.Parent)})
+	}
+	spec = append(spec, yaml.MapItem{Key: "members", Value: group.Members})
+	entity = append(entity, yaml.MapItem{Key: "spec", Value: spec})
+
+	yamlData, err := yaml.Marshal(entity)
+	if err != nil {
+		return fmt.Errorf("failed to convert group to YAML: %w", err)
+	}
+
+	if err := c.createEntityInScope(ctx, string(yamlData), group.Identifier, "", ""); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully created group: %s (identifier: %s)", group.Name, group.Identifier)
+	return nil
+}
+
+// createEntity posts an entity definition to the IDP 2.0 entities API at the configured scope
+func (c *Client) createEntity(ctx context.Context, yamlData, identifier string) error {
+	orgID, projectID := ScopeIdentifiers(c.config)
+	return c.createEntityInScope(ctx, yamlData, identifier, orgID, projectID)
+}
+
+// createEntityInScope posts an entity definition at the given org and project scope.
+// Empty identifiers create the entity at a higher scope.
+func (c *Client) createEntityInScope(ctx context.Context, yamlData, identifier, orgID, projectID string) error {
+	return c.postEntity(ctx, yamlData, identifier, orgID, projectID, false)
+}
+
+// ValidateEntityYAML submits an entity definition to the entities API as a dry run at
+// the configured scope, so Harness checks it without creating anything. An entity that
+// already exists passes; definitions Harness rejects return ENTITY_VALIDATION_FAILED.
+func (c *Client) ValidateEntityYAML(ctx context.Context, yamlData string) error {
+	identifier, err := ExtractEntityIdentifier(yamlData)
+	if err != nil {
+		return fmt.Errorf("invalid entity YAML: %w", err)
+	}
+
+	orgID, projectID := ScopeIdentifiers(c.config)
+	err = c.postEntity(ctx, yamlData, identifier, orgID, projectID, true)
+	if err == nil {
+		return nil
+	}
+	var procErr *errors.ProcessingError
+	if stderrors.As(err, &procErr) && procErr.Type == errors.ErrorTypeEntityExists {
+		return nil
+	}
+	var httpErr *HTTPError
+	if stderrors.As(err, &httpErr) && (httpErr.StatusCode == 400 || httpErr.StatusCode == 422) {
+		reason := entityErrorMessage(httpErr.Body)
+		return &errors.ProcessingError{
+			Category:     errors.ErrorCategoryValidation,
+			Type:         errors.ErrorTypeEntityValidationFailed,
+			Message:      fmt.Sprintf("Harness rejected entity %s: %s", identifier, reason),
+			Cause:        err,
+			Recoverable:  false,
+			UserFriendly: fmt.Sprintf("Harness IDP would reject the generated entity %s: %s", identifier, reason),
+		}
+	}
+	return err
+}
+
+// entityErrorMessage extracts the message from an entities API error body, falling
+// back to the raw body
+func entityErrorMessage(body string) string {
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil && parsed.Message != "" {
+		return parsed.Message
+	}
+	return strings.TrimSpace(body)
+}
+
+// postEntity posts an entity definition to the entities API; dryRun only validates it
+func (c *Client) postEntity(ctx context.Context, yamlData, identifier, orgID, projectID string, dryRun bool) error {
 	// Create request body with YAML string
 	reqBody := map[string]interface{}{
 		"yaml": yamlData,
@@ -142,8 +441,13 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 	log.Printf("DEBUG: Creating component with YAML payload: %s", string(jsonData))
 
 	// Use the correct API endpoint
-	endpoint := fmt.Sprintf("/gateway/v1/entities?convert=false&dry_run=false&accountIdentifier=%s&orgIdentifier=%s&projectIdentifier=%s",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID)
+	endpoint := fmt.Sprintf("/gateway/v1/entities?convert=false&dry_run=%t&accountIdentifier=%s", dryRun, c.config.AccountID)
+	if orgID != "" {
+		endpoint += "&orgIdentifier=" + orgID
+	}
+	if projectID != "" {
+		endpoint += "&projectIdentifier=" + projectID
+	}
 
 	log.Printf("DEBUG: POST %s", endpoint)
 
@@ -154,8 +458,12 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 
 	// Add required headers for entity creation API
 	req.Header.Set("harness-account", c.config.AccountID)
-	req.Header.Set("harness-org", c.config.OrgID)
-	req.Header.Set("harness-project", c.config.ProjectID)
+	if orgID != "" {
+		req.Header.Set("harness-org", orgID)
+	}
+	if projectID != "" {
+		req.Header.Set("harness-project", projectID)
+	}
 
 	// The new entity creation API returns a different response format
 	var resp interface{} // Use generic interface to handle any response format
@@ -163,7 +471,7 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 		// Check for specific Harness API errors
 		if httpErr, ok := err.(*HTTPError); ok {
 			if httpErr.StatusCode == 409 || strings.Contains(strings.ToLower(httpErr.Body), "already exists") {
-				return errors.NewEntityExistsError("", component.Identifier, err)
+				return errors.NewEntityExistsError("", identifier, err)
 			}
 			if httpErr.StatusCode == 401 {
 				return errors.NewUnauthorizedError("Harness API authentication failed", err)
@@ -184,21 +492,25 @@ func (c *Client) CreateComponent(ctx context.Context, component models.HarnessCo
 	
 	// For the entity creation API, success is indicated by HTTP 200/201 status
 	// The response format may vary, so we don't need to parse specific fields
-
-	log.Printf("Successfully created component: %s (identifier: %s)", component.Name, component.Identifier)
 	return nil
 }
 
 // componentToYAML converts a HarnessComponent to IDP 2.0 YAML format
 func (c *Client) componentToYAML(component models.HarnessComponent) (string, error) {
+	kind := component.Kind
+	if kind == "" {
+		kind = "Component"
+	}
+	orgID, projectID := ScopeIdentifiers(c.config)
+
 	yamlComponent := CatalogEntity{
 		APIVersion:        "harness.io/v1",
-		Kind:              "Component",
+		Kind:              kind,
 		Identifier:        component.Identifier,
 		Name:              component.Name,
 		Type:              component.Type,
-		ProjectIdentifier: c.config.ProjectID,
-		OrgIdentifier:     c.config.OrgID,
+		ProjectIdentifier: projectID,
+		OrgIdentifier:     orgID,
 		Owner:             component.Owner,
 		Metadata: struct {
 			Description string            `yaml:"description,omitempty"`
@@ -215,12 +527,18 @@ func (c *Client) componentToYAML(component models.HarnessComponent) (string, err
 			Annotations: component.Annotations,
 			Tags:        component.Tags,
 		},
-		Spec: struct {
-			Lifecycle string `yaml:"lifecycle"`
-		}{
-			Lifecycle: component.Lifecycle,
+		Spec: EntitySpec{
+			Lifecycle:    component.Lifecycle,
+			System:       component.System,
+			Domain:       component.Domain,
+			DependsOn:    component.DependsOn,
+			ProvidesAPIs: component.ProvidesAPIs,
+			Members:      component.Members,
 		},
 	}
+	if component.Definition != "" {
+		yamlComponent.Spec.Definition = map[string]string{"$text": component.Definition}
+	}
 
 	// Convert component links
 	for _, link := range component.Links {
@@ -245,162 +563,291 @@ func (c *Client) componentToYAML(component models.HarnessComponent) (string, err
 	return string(yamlBytes), nil
 }
 
+// UpdateComponent replaces a component's entity with its IDP 2.0 YAML definition through
+// the entities API at the configured scope
 func (c *Client) UpdateComponent(ctx context.Context, component models.HarnessComponent) error {
 	if err := c.validateComponent(component); err != nil {
 		return fmt.Errorf("component validation failed: %w", err)
 	}
 
-	reqBody := ComponentCreateRequest{
-		Component: component,
+	yamlData, err := c.componentToYAML(component)
+	if err != nil {
+		return fmt.Errorf("failed to convert component to YAML: %w", err)
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	jsonData, err := json.Marshal(map[string]interface{}{"yaml": yamlData})
 	if err != nil {
-		return fmt.Errorf("failed to marshal component: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("/gateway/idp/api/v1/accounts/%s/orgs/%s/projects/%s/catalog/components/%s",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID, component.Identifier)
+	endpoint := fmt.Sprintf("/gateway/v1/entities/%s/%s/%s", c.entityScope(), strings.ToLower(componentKind(component)), url.PathEscape(component.Identifier))
 
 	req, err := c.newRequest(ctx, "PUT", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
-
-	var resp ComponentResponse
-	if err := c.doRequest(req, &resp); err != nil {
-		return fmt.Errorf("failed to update component: %w", err)
+	orgID, projectID := ScopeIdentifiers(c.config)
+	req.Header.Set("harness-account", c.config.AccountID)
+	if orgID != "" {
+		req.Header.Set("harness-org", orgID)
+	}
+	if projectID != "" {
+		req.Header.Set("harness-project", projectID)
 	}
 
-	if resp.Status != "success" && resp.Status != "SUCCESS" {
-		return fmt.Errorf("component update failed: %s - %s", resp.Status, resp.Error)
+	if err := c.doRequest(req, nil); err != nil {
+		if httpErr, ok := err.(*HTTPError); ok {
+			if httpErr.IsNotFound() {
+				return &errors.ProcessingError{
+					Category:     errors.ErrorCategoryEntity,
+					Type:         errors.ErrorTypeEntityNotFound,
+					Message:      fmt.Sprintf("entity %s not found", component.Identifier),
+					Cause:        err,
+					Recoverable:  false,
+					UserFriendly: fmt.Sprintf("Component '%s' doesn't exist in Harness IDP. Create it in api mode first.", component.Identifier),
+				}
+			}
+			if httpErr.IsUnauthorized() {
+				return errors.NewUnauthorizedError("Harness API authentication failed", err)
+			}
+			if httpErr.StatusCode == 400 || httpErr.StatusCode == 422 {
+				reason := entityErrorMessage(httpErr.Body)
+				return &errors.ProcessingError{
+					Category:     errors.ErrorCategoryValidation,
+					Type:         errors.ErrorTypeEntityValidationFailed,
+					Message:      fmt.Sprintf("Harness rejected entity %s: %s", component.Identifier, reason),
+					Cause:        err,
+					Recoverable:  false,
+					UserFriendly: fmt.Sprintf("Harness IDP rejected the update of %s: %s", component.Identifier, reason),
+				}
+			}
+		}
+		return fmt.Errorf("failed to update component: %w", err)
 	}
 
 	log.Printf("Successfully updated component: %s (identifier: %s)", component.Name, component.Identifier)
 	return nil
 }
 
-func (c *Client) GetComponent(ctx context.Context, name string) (*models.HarnessComponent, error) {
-	// Use the same approach as CreateComponent - try to create and see if it already exists
-	// This leverages the existing error detection logic that works in API mode
-	
-	// Build a minimal component for testing existence
-	testComponent := models.HarnessComponent{
-		Identifier: name,
-		Name:       name,
-		Type:       "service",
-		Lifecycle:  "production",
-		Owner:      "test",
-	}
-	
-	// Convert component to YAML string for the API format
-	yamlData, err := c.componentToYAML(testComponent)
+// GetComponent fetches a Component from the IDP 2.0 entities API at the configured
+// scope. It returns nil without an error when no such entity exists.
+func (c *Client) GetComponent(ctx context.Context, identifier string) (*models.HarnessComponent, error) {
+	return c.GetEntity(ctx, "component", identifier)
+}
+
+// GetEntity fetches an entity of the given kind (component, resource, ...) from the
+// IDP 2.0 entities API, filling annotations, links and spec fields from its YAML
+// definition. It returns nil without an error when no such entity exists.
+func (c *Client) GetEntity(ctx context.Context, kind, identifier string) (*models.HarnessComponent, error) {
+	endpoint := fmt.Sprintf("/gateway/v1/entities/%s/%s/%s", c.entityScope(), strings.ToLower(kind), url.PathEscape(identifier))
+
+	req, err := c.newRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert test component to YAML: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-
-	// Create request body with YAML string
-	reqBody := map[string]interface{}{
-		"yaml": yamlData,
+	orgID, projectID := ScopeIdentifiers(c.config)
+	req.Header.Set("harness-account", c.config.AccountID)
+	if orgID != "" {
+		req.Header.Set("harness-org", orgID)
 	}
-	
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal test request: %w", err)
+	if projectID != "" {
+		req.Header.Set("harness-project", projectID)
 	}
 
-	// Use the same endpoint as CreateComponent but with dry_run=true
-	endpoint := fmt.Sprintf("/gateway/v1/entities?convert=false&dry_run=true&accountIdentifier=%s&orgIdentifier=%s&projectIdentifier=%s",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID)
-
-	req, err := c.newRequest(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create test request: %w", err)
+	var resp EntityResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		if httpErr, ok := err.(*HTTPError); ok && (httpErr.IsUnauthorized() || httpErr.IsForbidden()) {
+			return nil, fmt.Errorf("authentication/authorization error: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", kind, identifier, err)
 	}
 
-	// Add required headers for entity creation API
-	req.Header.Set("harness-account", c.config.AccountID)
-	req.Header.Set("harness-org", c.config.OrgID)
-	req.Header.Set("harness-project", c.config.ProjectID)
+	return componentFromEntity(resp)
+}
 
-	var resp interface{}
-	err = c.doRequest(req, &resp)
-	
-	log.Printf("DEBUG: GetComponent dry-run response for '%s': err=%v", name, err)
-	
-	if err != nil {
-		// Use the same error detection logic as CreateComponent
-		if httpErr, ok := err.(*HTTPError); ok {
-			log.Printf("DEBUG: HTTP Error - Status: %d, Body: %s", httpErr.StatusCode, httpErr.Body)
-			if httpErr.StatusCode == 409 || strings.Contains(strings.ToLower(httpErr.Body), "already exists") {
-				log.Printf("DEBUG: Component '%s' detected as existing via error response", name)
-				// Component exists - return a basic component object
-				return &models.HarnessComponent{
-					Identifier: name,
-					Name:       name,
-				}, nil
-			}
-			// For other HTTP errors (like 401, 403), return nil to indicate "not found"
-			if httpErr.StatusCode == 401 || httpErr.StatusCode == 403 {
-				return nil, fmt.Errorf("authentication/authorization error: %w", err)
-			}
+// entityScope is the scope path segment of the entities API: account, account.org or
+// account.org.project
+func (c *Client) entityScope() string {
+	orgID, projectID := ScopeIdentifiers(c.config)
+	scope := "account"
+	if orgID != "" {
+		scope += "." + orgID
+		if projectID != "" {
+			scope += "." + projectID
 		}
-		log.Printf("DEBUG: Component '%s' not detected as existing, error: %v", name, err)
-		// Component doesn't exist or other error - return nil (not found)
-		return nil, nil
 	}
-	
-	// If dry_run succeeded without errors, component doesn't exist
-	return nil, nil
+	return scope
 }
 
-func (c *Client) ListComponents(ctx context.Context) ([]models.HarnessComponent, error) {
-	endpoint := fmt.Sprintf("/gateway/idp/api/v1/accounts/%s/orgs/%s/projects/%s/catalog/components",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID)
+// ScopeIdentifiers returns the org and project identifiers entities are created with
+// under the configured scope; org scope drops the project, account scope both
+func ScopeIdentifiers(cfg models.HarnessConfig) (orgID, projectID string) {
+	switch cfg.Scope {
+	case "account":
+		return "", ""
+	case "org":
+		return cfg.OrgID, ""
+	default:
+		return cfg.OrgID, cfg.ProjectID
+	}
+}
 
-	req, err := c.newRequest(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// componentFromEntity converts an entities API response, preferring the fields of its
+// YAML definition where both are present
+func componentFromEntity(entity EntityResponse) (*models.HarnessComponent, error) {
+	component := &models.HarnessComponent{
+		Kind:        entity.Kind,
+		Identifier:  entity.Identifier,
+		Name:        entity.Name,
+		Type:        entity.Type,
+		Lifecycle:   entity.Lifecycle,
+		Owner:       entity.Owner,
+		Description: entity.Description,
+		Tags:        entity.Tags,
+	}
+	if entity.YAML == "" {
+		return component, nil
 	}
 
-	var resp ListComponentsResponse
-	if err := c.doRequest(req, &resp); err != nil {
-		return nil, fmt.Errorf("failed to list components: %w", err)
+	var definition CatalogEntity
+	if err := yaml.Unmarshal([]byte(entity.YAML), &definition); err != nil {
+		return nil, fmt.Errorf("failed to parse definition of %s: %w", entity.Identifier, err)
+	}
+	if definition.Kind != "" {
+		component.Kind = definition.Kind
+	}
+	if definition.Type != "" {
+		component.Type = definition.Type
+	}
+	if definition.Owner != "" {
+		component.Owner = definition.Owner
 	}
+	if definition.Spec.Lifecycle != "" {
+		component.Lifecycle = definition.Spec.Lifecycle
+	}
+	if definition.Metadata.Description != "" {
+		component.Description = definition.Metadata.Description
+	}
+	if len(definition.Metadata.Tags) > 0 {
+		component.Tags = definition.Metadata.Tags
+	}
+	component.System = definition.Spec.System
+	component.DependsOn = definition.Spec.DependsOn
+	component.ProvidesAPIs = definition.Spec.ProvidesAPIs
+	component.Domain = definition.Spec.Domain
+	component.Members = definition.Spec.Members
+	if ref, ok := definition.Spec.Definition.(map[string]interface{}); ok {
+		component.Definition, _ = ref["$text"].(string)
+	}
+	component.Annotations = definition.Metadata.Annotations
+	for _, link := range definition.Metadata.Links {
+		component.Links = append(component.Links, models.ComponentLink{URL: link.URL, Title: link.Title, Icon: link.Icon, Type: link.Type})
+	}
+	return component, nil
+}
+
+// ComponentFilter narrows ListComponents; empty fields match everything
+type ComponentFilter struct {
+	Kind  string // Component when empty
+	Type  string
+	Owner string
+	Tags  []string // components must have every tag
+}
+
+// listPageSize is how many entities ListComponents requests per page
+const listPageSize = 100
+
+// ListComponents pages through every Component, or entity of the filter's kind, at the
+// configured scope matching the filter on the IDP 2.0 entities API
+func (c *Client) ListComponents(ctx context.Context, filter ComponentFilter) ([]models.HarnessComponent, error) {
+	query := url.Values{}
+	query.Set("kind", strings.ToLower(componentKind(models.HarnessComponent{Kind: filter.Kind})))
+	query.Set("scopes", c.entityScope())
+	query.Set("limit", fmt.Sprint(listPageSize))
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Owner != "" {
+		query.Set("owner", filter.Owner)
+	}
+	if len(filter.Tags) > 0 {
+		query.Set("tags", strings.Join(filter.Tags, ","))
+	}
+
+	var components []models.HarnessComponent
+	for page := 0; ; page++ {
+		query.Set("page", fmt.Sprint(page))
+		req, err := c.newRequest(ctx, "GET", "/gateway/v1/entities?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("harness-account", c.config.AccountID)
 
-	if resp.Status != "success" && resp.Status != "SUCCESS" {
-		return nil, fmt.Errorf("list components failed: %s - %s", resp.Status, resp.Error)
+		var entities []EntityResponse
+		if err := c.doRequest(req, &entities); err != nil {
+			return nil, fmt.Errorf("failed to list components (page %d): %w", page, err)
+		}
+		for _, entity := range entities {
+			component, err := componentFromEntity(entity)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, *component)
+		}
+		if len(entities) < listPageSize {
+			break
+		}
 	}
 
-	return resp.Components, nil
+	log.Printf("Listed %d components", len(components))
+	return components, nil
 }
 
-func (c *Client) DeleteComponent(ctx context.Context, name string) error {
-	endpoint := fmt.Sprintf("/gateway/idp/api/v1/accounts/%s/orgs/%s/projects/%s/catalog/components/%s",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID, name)
+// DeleteComponent deletes a Component at the configured scope
+func (c *Client) DeleteComponent(ctx context.Context, identifier string) error {
+	return c.DeleteEntity(ctx, "component", identifier)
+}
+
+// DeleteEntity deletes an entity of the given kind through the IDP 2.0 entities API at
+// the configured scope
+func (c *Client) DeleteEntity(ctx context.Context, kind, identifier string) error {
+	endpoint := fmt.Sprintf("/gateway/v1/entities/%s/%s/%s", c.entityScope(), strings.ToLower(kind), url.PathEscape(identifier))
 
 	req, err := c.newRequest(ctx, "DELETE", endpoint, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
-
-	var resp ComponentResponse
-	if err := c.doRequest(req, &resp); err != nil {
-		return fmt.Errorf("failed to delete component: %w", err)
+	orgID, projectID := ScopeIdentifiers(c.config)
+	req.Header.Set("harness-account", c.config.AccountID)
+	if orgID != "" {
+		req.Header.Set("harness-org", orgID)
+	}
+	if projectID != "" {
+		req.Header.Set("harness-project", projectID)
 	}
 
-	if resp.Status != "success" && resp.Status != "SUCCESS" {
-		return fmt.Errorf("component deletion failed: %s - %s", resp.Status, resp.Error)
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("failed to delete %s %s: %w", strings.ToLower(kind), identifier, err)
 	}
 
-	log.Printf("Successfully deleted component: %s", name)
+	log.Printf("Successfully deleted %s: %s", strings.ToLower(kind), identifier)
 	return nil
 }
 
-// RegisterCatalogLocation registers a repository for entity import with Harness IDP
+// RegisterCatalogLocation registers a GitHub repository for entity import with Harness IDP
 func (c *Client) RegisterCatalogLocation(ctx context.Context, repoFullName, branchName, filePath, catalogContent string) error {
+	return c.ImportCatalogLocation(ctx, CatalogLocation{Repository: repoFullName, Branch: branchName, Path: filePath, Content: catalogContent})
+}
+
+// ImportCatalogLocation registers a repository's catalog file for entity import with
+// Harness IDP. Harness Code repositories are imported without a connector.
+func (c *Client) ImportCatalogLocation(ctx context.Context, location CatalogLocation) error {
+	repoFullName, branchName, filePath, catalogContent := location.Repository, location.Branch, location.Path, location.Content
 	// Extract just the repository name from the full name (owner/repo -> repo)
-	repoName := strings.Split(repoFullName, "/")[1]
+	repoName := repoFullName[strings.LastIndex(repoFullName, "/")+1:]
 	
 	// Parse catalog content to extract entity identifier for IDP 2.0
 	entityIdentifier, err := c.extractEntityIdentifier(catalogContent)
@@ -420,20 +867,24 @@ func (c *Client) RegisterCatalogLocation(ctx context.Context, repoFullName, bran
 	entityIdentifier = strings.ReplaceAll(entityIdentifier, "-", "_")
 	
 	connectorRef := c.config.ConnectorRef
-	if connectorRef == "" {
-		connectorRef = "account.Gihubapp" // Default fallback
+	if connectorRef == "" && !location.HarnessCode {
+		return errMissingConnectorRef
+	}
+	if location.HarnessCode {
+		connectorRef = ""
 	}
+	orgID, projectID := ScopeIdentifiers(c.config)
 
 	reqBody := EntityImportRequest{
 		BranchName:        branchName,
 		ConnectorRef:      connectorRef,
 		RepoName:          repoName, // Use just the repo name, not the full name
-		IsHarnessCodeRepo: false,
+		IsHarnessCodeRepo: location.HarnessCode,
 		FilePath:          filePath,
 		Identifier:        entityIdentifier, // IDP 2.0 requires identifier
 		AccountIdentifier: c.config.AccountID,
-		OrgIdentifier:     c.config.OrgID,
-		ProjectIdentifier: c.config.ProjectID,
+		OrgIdentifier:     orgID,
+		ProjectIdentifier: projectID,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -444,8 +895,13 @@ func (c *Client) RegisterCatalogLocation(ctx context.Context, repoFullName, bran
 	log.Printf("DEBUG: Sending payload to /gateway/v1/entities/import: %s", string(jsonData))
 
 	// Add org and project identifiers as query parameters
-	endpoint := fmt.Sprintf("/gateway/v1/entities/import?accountIdentifier=%s&orgIdentifier=%s&projectIdentifier=%s",
-		c.config.AccountID, c.config.OrgID, c.config.ProjectID)
+	endpoint := "/gateway/v1/entities/import?accountIdentifier=" + c.config.AccountID
+	if orgID != "" {
+		endpoint += "&orgIdentifier=" + orgID
+	}
+	if projectID != "" {
+		endpoint += "&projectIdentifier=" + projectID
+	}
 
 	log.Printf("DEBUG: POST %s", endpoint)
 
@@ -484,8 +940,61 @@ func (c *Client) RegisterCatalogLocation(ctx context.Context, repoFullName, bran
 	return nil
 }
 
+// CatalogLocation is a repository's catalog file to import with RegisterCatalogLocations
+type CatalogLocation struct {
+	Repository  string // owner/name
+	Branch      string
+	Path        string
+	Content     string
+	HarnessCode bool // hosted in Harness Code rather than GitHub
+}
+
+// batchRetries is the minimum number of retries RegisterCatalogLocations makes of
+// imports that get a 429 or server error, backing off exponentially
+const batchRetries = 3
+
+// RegisterCatalogLocations imports many catalog files, returning one error per location
+// in the same order (nil for success). The import API takes one file per request, so
+// the requests are pipelined over shared keep-alive connections like CreateComponents,
+// and rate-limited or failed requests are retried with backoff rather than the whole
+// batch being paced by a fixed delay.
+func (c *Client) RegisterCatalogLocations(ctx context.Context, locations []CatalogLocation) []error {
+	batch := *c
+	batch.config.Retries = max(c.config.Retries, batchRetries)
+
+	errs := make([]error, len(locations))
+	semaphore := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, location := range locations {
+		wg.Add(1)
+		go func(i int, location CatalogLocation) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			errs[i] = batch.ImportCatalogLocation(ctx, location)
+		}(i, location)
+	}
+	wg.Wait()
+
+	registered := 0
+	for _, err := range errs {
+		if err == nil {
+			registered++
+		}
+	}
+	log.Printf("Registered %d of %d catalog locations in batch", registered, len(locations))
+	return errs
+}
+
 // extractEntityIdentifier parses catalog-info.yaml content and extracts the entity identifier
 func (c *Client) extractEntityIdentifier(catalogContent string) (string, error) {
+	return ExtractEntityIdentifier(catalogContent)
+}
+
+// ExtractEntityIdentifier returns the identifier of a catalog entity, falling back
+// to metadata.name for legacy Backstage-format files
+func ExtractEntityIdentifier(catalogContent string) (string, error) {
 	var entity CatalogEntity
 	
 	err := yaml.Unmarshal([]byte(catalogContent), &entity)
@@ -580,12 +1089,62 @@ func (c *Client) newEntityImportRequest(ctx context.Context, method, endpoint st
 	return req, nil
 }
 
+// doRequest sends the request, retrying timeouts, 429s and server errors up to the
+// configured number of times with exponential backoff, and decodes a successful
+// response into result
 func (c *Client) doRequest(req *http.Request, result interface{}) error {
+	for attempt := 0; ; attempt++ {
+		err := c.sendRequest(req, result)
+		if attempt >= c.config.Retries || !isTransient(err) || req.Context().Err() != nil {
+			return err
+		}
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return err
+			}
+			req.Body = body
+		} else if req.Body != nil && req.Body != http.NoBody {
+			return err
+		}
+
+		backoff := time.Duration(1<<attempt) * 500 * time.Millisecond
+		log.Printf("Harness request %s %s failed (%v), retrying in %s (%d/%d)", req.Method, req.URL.Path, err, backoff, attempt+1, c.config.Retries)
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return err
+		}
+	}
+}
+
+// isTransient reports whether a failed request is worth retrying
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if httpErr, ok := err.(*HTTPError); ok {
+		return httpErr.IsRateLimited() || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return stderrors.As(err, &netErr)
+}
+
+func (c *Client) sendRequest(req *http.Request, result interface{}) error {
+	if err := c.breaker.wait(req.Context()); err != nil {
+		return err
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		// A cancelled run says nothing about the API's health
+		if req.Context().Err() == nil {
+			c.breaker.record(true)
+		}
 		return err
 	}
 	defer resp.Body.Close()
+	c.breaker.record(resp.StatusCode >= 500)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -617,15 +1176,36 @@ func (c *Client) validateComponent(component models.HarnessComponent) error {
 	if component.Name == "" {
 		return fmt.Errorf("component name is required")
 	}
+	if component.Owner == "" {
+		return fmt.Errorf("component owner is required")
+	}
+
+	switch kind := componentKind(component); kind {
+	case "System", "Group":
+		// Neither kind has a lifecycle, and a Group's type is free-form
+		return nil
+	case "API":
+		if component.Type == "" {
+			return fmt.Errorf("API type is required")
+		}
+		if component.Lifecycle == "" {
+			return fmt.Errorf("API lifecycle is required")
+		}
+		if component.Definition == "" {
+			return fmt.Errorf("API definition is required")
+		}
+		return nil
+	case "Component", "Resource":
+	default:
+		return fmt.Errorf("unsupported entity kind: %s (supported: %s)", kind, strings.Join(EntityKinds, ", "))
+	}
+
 	if component.Type == "" {
 		return fmt.Errorf("component type is required")
 	}
 	if component.Lifecycle == "" {
 		return fmt.Errorf("component lifecycle is required")
 	}
-	if component.Owner == "" {
-		return fmt.Errorf("component owner is required")
-	}
 
 	validTypes := map[string]bool{
 		"service":   true,
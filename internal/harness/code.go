@@ -0,0 +1,145 @@
+package harness
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"harness-onboarder/internal/models"
+)
+
+// SourceHarnessCode marks repositories hosted in Harness Code rather than GitHub
+const SourceHarnessCode = "harness-code"
+
+// codeRepository is a repository as returned by the Harness Code API; times are Unix
+// milliseconds
+type codeRepository struct {
+	ID            int64  `json:"id"`
+	Identifier    string `json:"identifier"`
+	Path          string `json:"path"`
+	Description   string `json:"description"`
+	DefaultBranch string `json:"default_branch"`
+	IsPublic      bool   `json:"is_public"`
+	Archived      bool   `json:"archived"`
+	Created       int64  `json:"created"`
+	Updated       int64  `json:"updated"`
+	GitURL        string `json:"git_url"`
+	NumForks      int    `json:"num_forks"`
+	Size          int64  `json:"size"` // KiB
+}
+
+// codeContent is a file as returned by the Harness Code content API
+type codeContent struct {
+	Type    string `json:"type"`
+	Content struct {
+		Encoding string `json:"encoding"`
+		Data     string `json:"data"`
+	} `json:"content"`
+}
+
+// codePageSize is how many repositories ListCodeRepositories requests per page
+const codePageSize = 100
+
+// ListCodeRepositories pages through the Harness Code repositories of the configured
+// org and project
+func (c *Client) ListCodeRepositories(ctx context.Context) ([]models.Repository, error) {
+	var repos []models.Repository
+	for page := 1; ; page++ {
+		query := c.codeQuery()
+		query.Set("page", fmt.Sprint(page))
+		query.Set("limit", fmt.Sprint(codePageSize))
+
+		req, err := c.newRequest(ctx, "GET", "/code/api/v1/repos?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		var list []codeRepository
+		if err := c.doRequest(req, &list); err != nil {
+			return nil, fmt.Errorf("failed to list Harness Code repositories (page %d): %w", page, err)
+		}
+		for _, repo := range list {
+			repos = append(repos, c.codeRepositoryModel(repo))
+		}
+		if len(list) < codePageSize {
+			break
+		}
+	}
+	return repos, nil
+}
+
+// GetCodeFile returns the content of a file on a Harness Code repository's branch, and
+// false without an error when it doesn't exist
+func (c *Client) GetCodeFile(ctx context.Context, repo models.Repository, branch, path string) (string, bool, error) {
+	query := c.codeQuery()
+	query.Set("git_ref", branch)
+	query.Set("include_commit", "false")
+	endpoint := fmt.Sprintf("/code/api/v1/repos/%s/content/%s?%s", url.PathEscape(repo.Name), path, query.Encode())
+
+	req, err := c.newRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var content codeContent
+	if err := c.doRequest(req, &content); err != nil {
+		if isNotFoundError(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to read %s from %s: %w", path, repo.FullName, err)
+	}
+	if content.Type != "file" {
+		return "", false, nil
+	}
+	if content.Content.Encoding != "base64" {
+		return content.Content.Data, true, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(content.Content.Data)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode %s from %s: %w", path, repo.FullName, err)
+	}
+	return string(data), true, nil
+}
+
+// codeQuery returns the scope parameters of the Harness Code API
+func (c *Client) codeQuery() url.Values {
+	query := url.Values{}
+	query.Set("accountIdentifier", c.config.AccountID)
+	query.Set("orgIdentifier", c.config.OrgID)
+	query.Set("projectIdentifier", c.config.ProjectID)
+	return query
+}
+
+// codeRepositoryModel converts a Harness Code repository. FullName is its
+// org/project/name path, and HTMLURL its page in the Harness UI.
+func (c *Client) codeRepositoryModel(repo codeRepository) models.Repository {
+	htmlURL := strings.TrimSuffix(c.baseURL.String(), "/") + fmt.Sprintf("/ng/account/%s/module/code/orgs/%s/projects/%s/repos/%s",
+		c.config.AccountID, c.config.OrgID, c.config.ProjectID, repo.Identifier)
+	updated := time.UnixMilli(repo.Updated)
+	visibility := "private"
+	if repo.IsPublic {
+		visibility = "public"
+	}
+	return models.Repository{
+		ID:            repo.ID,
+		Name:          repo.Identifier,
+		FullName:      c.config.OrgID + "/" + c.config.ProjectID + "/" + repo.Identifier,
+		Description:   repo.Description,
+		HTMLURL:       htmlURL,
+		CloneURL:      repo.GitURL,
+		Private:       !repo.IsPublic,
+		Visibility:    visibility,
+		Archived:      repo.Archived,
+		CreatedAt:     time.UnixMilli(repo.Created),
+		UpdatedAt:     updated,
+		PushedAt:      updated,
+		DefaultBranch: repo.DefaultBranch,
+		Forks:         repo.NumForks,
+		SizeKB:        int(repo.Size),
+		Source:        SourceHarnessCode,
+		Metadata:      make(map[string]string),
+	}
+}
@@ -0,0 +1,230 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// errMissingConnectorRef is returned when register mode has no connector to import with
+var errMissingConnectorRef = stderrors.New("harness.connector_ref (--harness-connector-ref) is required to register catalog files")
+
+// connectorResponse is the part of the connector API response the preflight checks
+type connectorResponse struct {
+	Data struct {
+		Connector struct {
+			Identifier string `json:"identifier"`
+			Type       string `json:"type"`
+			Spec       struct {
+				URL  string `json:"url"`
+				Type string `json:"type"` // Account or Repo
+			} `json:"spec"`
+		} `json:"connector"`
+	} `json:"data"`
+}
+
+// connectionTestResponse is the connector test API response
+type connectionTestResponse struct {
+	Data struct {
+		Status       string `json:"status"`
+		ErrorSummary string `json:"errorSummary"`
+	} `json:"data"`
+}
+
+// CheckGitHubConnector verifies that the configured connector exists, is a GitHub
+// connector for the organization and can connect, so register mode fails fast with a
+// clear message instead of every import failing
+func (c *Client) CheckGitHubConnector(ctx context.Context, organization string) error {
+	ref := c.config.ConnectorRef
+	if ref == "" {
+		return errMissingConnectorRef
+	}
+	query := c.connectorQuery(ref)
+	identifier := ref[strings.LastIndex(ref, ".")+1:]
+
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/ng/api/connectors/%s?%s", url.PathEscape(identifier), query.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	var resp connectorResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		if isNotFoundError(err) {
+			return fmt.Errorf("connector %s not found; check harness.connector_ref and its account./org. scope prefix", ref)
+		}
+		return fmt.Errorf("failed to get connector %s: %w", ref, err)
+	}
+
+	connector := resp.Data.Connector
+	if connector.Identifier == "" {
+		return fmt.Errorf("connector %s not found; check harness.connector_ref and its account./org. scope prefix", ref)
+	}
+	if !strings.EqualFold(connector.Type, "Github") {
+		return fmt.Errorf("connector %s is a %s connector, a GitHub connector is required", ref, connector.Type)
+	}
+	if !connectorCoversOrg(connector.Spec.URL, connector.Spec.Type, organization) {
+		return fmt.Errorf("connector %s points at %s, not the %s organization", ref, connector.Spec.URL, organization)
+	}
+
+	req, err = c.newRequest(ctx, "POST", fmt.Sprintf("/ng/api/connectors/testConnection/%s?%s", url.PathEscape(identifier), query.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	var test connectionTestResponse
+	if err := c.doRequest(req, &test); err != nil {
+		return fmt.Errorf("failed to test connector %s: %w", ref, err)
+	}
+	if test.Data.Status != "" && test.Data.Status != "SUCCESS" {
+		return fmt.Errorf("connector %s can't connect to GitHub: %s", ref, orDefault(test.Data.ErrorSummary, test.Data.Status))
+	}
+	return nil
+}
+
+// connectorQuery returns the scope query parameters of a connector reference:
+// account.x and org.x refer to higher scopes, a bare identifier to the project
+func (c *Client) connectorQuery(ref string) url.Values {
+	query := url.Values{}
+	query.Set("accountIdentifier", c.config.AccountID)
+	switch {
+	case strings.HasPrefix(ref, "account."):
+	case strings.HasPrefix(ref, "org."):
+		query.Set("orgIdentifier", c.config.OrgID)
+	default:
+		query.Set("orgIdentifier", c.config.OrgID)
+		query.Set("projectIdentifier", c.config.ProjectID)
+	}
+	return query
+}
+
+// connectorCoversOrg reports whether a GitHub connector URL reaches the organization:
+// account connectors point at the organization, repo connectors at one of its repos
+func connectorCoversOrg(connectorURL, urlType, organization string) bool {
+	u := strings.ToLower(strings.TrimSuffix(connectorURL, "/"))
+	u = strings.TrimSuffix(u, ".git")
+	org := "/" + strings.ToLower(organization)
+	if strings.EqualFold(urlType, "Repo") {
+		return strings.Contains(u, org+"/") || strings.Contains(u, ":"+strings.ToLower(organization)+"/")
+	}
+	// SSH URLs use git@github.com:org
+	return strings.HasSuffix(u, org) || strings.HasSuffix(u, ":"+strings.ToLower(organization))
+}
+
+func orDefault(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GitHubAppConnector is a GitHub connector authenticating as the onboarder's GitHub App
+type GitHubAppConnector struct {
+	Organization string
+	AppID        int64
+	InstallID    int64
+	PrivateKey   []byte // PEM
+}
+
+// ProvisionGitHubConnector creates or updates the configured connector as a GitHub App
+// connector for the organization. The app's private key is stored as a Harness secret
+// next to it, named after the connector.
+func (c *Client) ProvisionGitHubConnector(ctx context.Context, app GitHubAppConnector) error {
+	ref := c.config.ConnectorRef
+	if ref == "" {
+		return errMissingConnectorRef
+	}
+	query := c.connectorQuery(ref)
+	identifier := ref[strings.LastIndex(ref, ".")+1:]
+	orgID, projectID := query.Get("orgIdentifier"), query.Get("projectIdentifier")
+
+	secretID := identifier + "_private_key"
+	secret := map[string]interface{}{
+		"secret": map[string]interface{}{
+			"type":              "SecretText",
+			"name":              identifier + " private key",
+			"identifier":        secretID,
+			"orgIdentifier":     orgID,
+			"projectIdentifier": projectID,
+			"spec": map[string]interface{}{
+				"secretManagerIdentifier": "harnessSecretManager",
+				"valueType":               "Inline",
+				"value":                   string(app.PrivateKey),
+			},
+		},
+	}
+	secretPath := "/ng/api/v2/secrets/" + url.PathEscape(secretID)
+	if err := c.upsert(ctx, secretPath, "/ng/api/v2/secrets", secretPath, query, secret); err != nil {
+		return fmt.Errorf("failed to store GitHub App private key: %w", err)
+	}
+
+	// The secret lives at the connector's scope, so it's referenced with the same prefix
+	secretRef := secretID
+	if prefix, _, found := strings.Cut(ref, "."); found && (prefix == "account" || prefix == "org") {
+		secretRef = prefix + "." + secretID
+	}
+	appSpec := map[string]interface{}{
+		"applicationId":  fmt.Sprint(app.AppID),
+		"installationId": fmt.Sprint(app.InstallID),
+		"privateKeyRef":  secretRef,
+	}
+	connector := map[string]interface{}{
+		"connector": map[string]interface{}{
+			"name":              identifier,
+			"identifier":        identifier,
+			"orgIdentifier":     orgID,
+			"projectIdentifier": projectID,
+			"type":              "Github",
+			"spec": map[string]interface{}{
+				"url":  "https://github.com/" + app.Organization,
+				"type": "Account",
+				"authentication": map[string]interface{}{
+					"type": "Http",
+					"spec": map[string]interface{}{"type": "GithubApp", "spec": appSpec},
+				},
+				"apiAccess":         map[string]interface{}{"type": "GithubApp", "spec": appSpec},
+				"executeOnDelegate": false,
+			},
+		},
+	}
+	connectorPath := "/ng/api/connectors/" + url.PathEscape(identifier)
+	if err := c.upsert(ctx, connectorPath, "/ng/api/connectors", "/ng/api/connectors", query, connector); err != nil {
+		return fmt.Errorf("failed to provision connector %s: %w", ref, err)
+	}
+
+	log.Printf("Provisioned GitHub connector %s for %s", ref, app.Organization)
+	return nil
+}
+
+// upsert updates a resource with a PUT to updatePath when a GET of getPath finds it,
+// creating it with a POST to createPath otherwise
+func (c *Client) upsert(ctx context.Context, getPath, createPath, updatePath string, query url.Values, body interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, "GET", getPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	exists := true
+	if err := c.doRequest(req, nil); err != nil {
+		if !isNotFoundError(err) {
+			return err
+		}
+		exists = false
+	}
+
+	method, endpoint := "POST", createPath
+	if exists {
+		method, endpoint = "PUT", updatePath
+	}
+	req, err = c.newRequest(ctx, method, endpoint+"?"+query.Encode(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.doRequest(req, nil)
+}
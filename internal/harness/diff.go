@@ -0,0 +1,89 @@
+package harness
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"reflect"
+	"sort"
+
+	"harness-onboarder/internal/models"
+)
+
+// ErrUnchanged is returned instead of updating a component whose entity in IDP already
+// matches it, so repeated runs don't add no-op changes to the IDP audit trail
+var ErrUnchanged = stderrors.New("component unchanged")
+
+// UpdateComponentIfChanged fetches the existing entity and updates it only when the
+// component differs from it in a field written to IDP, returning ErrUnchanged otherwise.
+// A component that can't be fetched is updated as before.
+func (c *Client) UpdateComponentIfChanged(ctx context.Context, component models.HarnessComponent) error {
+	existing, err := c.GetEntity(ctx, componentKind(component), component.Identifier)
+	if err != nil {
+		log.Printf("Warning: could not fetch %s to compare, updating it: %v", component.Identifier, err)
+	}
+	return c.updateChanged(ctx, existing, component)
+}
+
+// updateChanged updates a component unless existing, when known, already matches it
+func (c *Client) updateChanged(ctx context.Context, existing *models.HarnessComponent, component models.HarnessComponent) error {
+	if existing != nil && ComponentsEqual(*existing, component) {
+		log.Printf("Component %s (identifier: %s) unchanged, skipping update", component.Name, component.Identifier)
+		return ErrUnchanged
+	}
+	return c.UpdateComponent(ctx, component)
+}
+
+// ComponentsEqual reports whether two components have the same kind, type, owner,
+// lifecycle, relations, kind-specific spec fields, description, tags, annotations and
+// links. Tag order and the
+// free-form Metadata, which isn't part of the entity YAML, are ignored.
+func ComponentsEqual(a, b models.HarnessComponent) bool {
+	return componentKind(a) == componentKind(b) &&
+		a.Identifier == b.Identifier &&
+		a.Name == b.Name &&
+		a.Type == b.Type &&
+		a.Lifecycle == b.Lifecycle &&
+		a.Owner == b.Owner &&
+		a.System == b.System &&
+		a.Domain == b.Domain &&
+		a.Definition == b.Definition &&
+		equalStrings(a.Members, b.Members, true) &&
+		a.Description == b.Description &&
+		equalStrings(a.DependsOn, b.DependsOn, false) &&
+		equalStrings(a.ProvidesAPIs, b.ProvidesAPIs, false) &&
+		equalStrings(a.Tags, b.Tags, true) &&
+		(len(a.Annotations) == 0 && len(b.Annotations) == 0 || reflect.DeepEqual(a.Annotations, b.Annotations)) &&
+		(len(a.Links) == 0 && len(b.Links) == 0 || reflect.DeepEqual(a.Links, b.Links))
+}
+
+// EntityKinds are the entity kinds the onboarder can create in API mode
+var EntityKinds = []string{"Component", "API", "Resource", "System", "Group"}
+
+// componentKind is a component's entity kind, Component when unset
+func componentKind(component models.HarnessComponent) string {
+	if component.Kind == "" {
+		return "Component"
+	}
+	return component.Kind
+}
+
+// equalStrings compares two lists, treating nil and empty alike and ignoring order
+// when unordered is set
+func equalStrings(a, b []string, unordered bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if unordered {
+		a = append([]string(nil), a...)
+		b = append([]string(nil), b...)
+		sort.Strings(a)
+		sort.Strings(b)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+)
+
+// userGroupResponse is the user-group API response; Data is null for unknown groups
+type userGroupResponse struct {
+	Status string                 `json:"status"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// GroupExists reports whether an owner group exists at the given scope, either as a
+// Harness user group or as an IDP Group entity such as those created by --sync-teams.
+// Empty identifiers select a higher scope.
+func (c *Client) GroupExists(ctx context.Context, identifier, orgID, projectID string) (bool, error) {
+	query := url.Values{}
+	query.Set("accountIdentifier", c.config.AccountID)
+	if orgID != "" {
+		query.Set("orgIdentifier", orgID)
+	}
+	if projectID != "" {
+		query.Set("projectIdentifier", projectID)
+	}
+	endpoint := fmt.Sprintf("/ng/api/user-groups/%s?%s", url.PathEscape(identifier), query.Encode())
+
+	req, err := c.newRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp userGroupResponse
+	if err := c.doRequest(req, &resp); err != nil && !isNotFoundError(err) {
+		return false, fmt.Errorf("failed to get user group %s: %w", identifier, err)
+	}
+	if resp.Data != nil {
+		return true, nil
+	}
+
+	scoped := c.WithProject(orgID, projectID)
+	scoped.config.Scope = ""
+	entity, err := scoped.GetEntity(ctx, "Group", identifier)
+	if err != nil {
+		return false, err
+	}
+	return entity != nil, nil
+}
+
+// userGroupRequest is the body of the user-group create API
+type userGroupRequest struct {
+	Identifier        string   `json:"identifier"`
+	Name              string   `json:"name"`
+	Description       string   `json:"description,omitempty"`
+	AccountIdentifier string   `json:"accountIdentifier"`
+	OrgIdentifier     string   `json:"orgIdentifier,omitempty"`
+	ProjectIdentifier string   `json:"projectIdentifier,omitempty"`
+	Users             []string `json:"users"`
+}
+
+// CreateUserGroup creates an empty Harness user group at the given scope, so owner
+// references to it resolve. Members are left to be added in Harness.
+func (c *Client) CreateUserGroup(ctx context.Context, identifier, name, description, orgID, projectID string) error {
+	body := userGroupRequest{
+		Identifier:        identifier,
+		Name:              name,
+		Description:       description,
+		AccountIdentifier: c.config.AccountID,
+		OrgIdentifier:     orgID,
+		ProjectIdentifier: projectID,
+		Users:             []string{},
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user group: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("accountIdentifier", c.config.AccountID)
+	if orgID != "" {
+		query.Set("orgIdentifier", orgID)
+	}
+	if projectID != "" {
+		query.Set("projectIdentifier", projectID)
+	}
+
+	req, err := c.newRequest(ctx, "POST", "/ng/api/user-groups?"+query.Encode(), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := c.doRequest(req, nil); err != nil {
+		return fmt.Errorf("failed to create user group %s: %w", identifier, err)
+	}
+
+	log.Printf("Created user group %s", identifier)
+	return nil
+}
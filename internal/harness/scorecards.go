@@ -0,0 +1,34 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ScorecardScore is an entity's score, from 0 to 100, on one scorecard
+type ScorecardScore struct {
+	Scorecard string  `json:"scorecard_name"`
+	Score     float64 `json:"score"`
+}
+
+// GetScores returns the scorecard scores of an entity. Entities scorecards haven't
+// evaluated yet, such as those created moments ago, have none.
+func (c *Client) GetScores(ctx context.Context, kind, identifier string) ([]ScorecardScore, error) {
+	endpoint := fmt.Sprintf("/gateway/v1/entities/%s/%s/%s/scores", c.entityScope(), strings.ToLower(kind), url.PathEscape(identifier))
+
+	req, err := c.newRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var scores []ScorecardScore
+	if err := c.doRequest(req, &scores); err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scores of %s %s: %w", kind, identifier, err)
+	}
+	return scores, nil
+}
@@ -0,0 +1,150 @@
+// Package httpcache keeps GitHub GET responses on disk with their ETags for --cache-dir.
+// Cached responses are revalidated with conditional requests, which GitHub answers with
+// 304 Not Modified without counting them against the rate limit, so repeated runs
+// against the same organization only transfer what changed.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Dir is the cache directory for transports wrapped afterwards; empty disables caching
+var Dir string
+
+// entry is a cached response, stored as JSON under the hash of its request
+type entry struct {
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Wrap returns base wrapped in a caching transport, or base itself when no cache
+// directory is set. A nil base means http.DefaultTransport.
+func Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if Dir == "" {
+		return base
+	}
+	return &transport{base: base, dir: Dir}
+}
+
+type transport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	file := t.file(req)
+	cached := load(file)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return cached.response(req, resp), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	store(file, entry{ETag: etag, Header: resp.Header, Body: body})
+	return resp, nil
+}
+
+// file names the cache file of a request. Credentials aren't part of the key, since
+// installation tokens change every hour while the responses stay valid.
+func (t *transport) file(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept")))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(t.dir, key[:2], key+".json")
+}
+
+// response rebuilds the cached response, with the headers of the 304 so rate limit
+// information stays current
+func (e *entry) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := e.Header.Clone()
+	for name, values := range notModified.Header {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func load(file string) *entry {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var e entry
+	if err := json.Unmarshal(content, &e); err != nil || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+// store writes an entry through a temporary file, so concurrent workers never read a
+// partial one. The cache holds repository contents, so it's readable by its owner only.
+func store(file string, e entry) {
+	content, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		log.Printf("Warning: failed to create cache directory: %v", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		log.Printf("Warning: failed to write cache entry: %v", err)
+		return
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
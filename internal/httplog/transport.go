@@ -0,0 +1,176 @@
+// Package httplog traces HTTP calls to GitHub and Harness for --trace-http, with
+// credentials redacted so the output can be attached to support cases.
+package httplog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Enabled turns tracing on for transports wrapped afterwards
+var Enabled bool
+
+// maxBody is how much of each request and response body is logged
+const maxBody = 2048
+
+// sensitiveHeaders are logged as [REDACTED]
+var sensitiveHeaders = []string{"Authorization", "X-Api-Key", "Cookie", "Set-Cookie"}
+
+// sensitiveParams are query parameters whose values are redacted
+var sensitiveParams = []string{"token", "access_token", "api_key", "apikey", "key", "secret"}
+
+var secretPatterns = []*regexp.Regexp{
+	// JSON fields such as "token", "api_key", "client_secret" or "password"
+	regexp.MustCompile(`("(?i:[a-z_]*(?:token|secret|password|api_?key|private_?key))"\s*:\s*)"[^"]*"`),
+	// Harness personal access and service account tokens
+	regexp.MustCompile(`\b(?:pat|sat)\.[A-Za-z0-9_.-]+`),
+	// GitHub tokens and JWTs
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]+`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	// PEM private keys
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// Wrap returns base wrapped in a tracing transport labelled with service, or base
+// itself when tracing is disabled. A nil base means http.DefaultTransport.
+func Wrap(base http.RoundTripper, service string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !Enabled {
+		return base
+	}
+	return &transport{base: base, service: service}
+}
+
+type transport struct {
+	base    http.RoundTripper
+	service string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody := peekRequestBody(req)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	target := redactURL(req.URL)
+	if err != nil {
+		log.Printf("HTTP [%s] %s %s -> error after %s: %s", t.service, req.Method, target, latency, Redact(err.Error()))
+		return resp, err
+	}
+
+	log.Printf("HTTP [%s] %s %s -> %s (%s)", t.service, req.Method, target, resp.Status, latency)
+	log.Printf("HTTP [%s]   request headers: %s", t.service, redactHeaders(req.Header))
+	if reqBody != "" {
+		log.Printf("HTTP [%s]   request body: %s", t.service, reqBody)
+	}
+	if respBody := peekResponseBody(resp); respBody != "" {
+		log.Printf("HTTP [%s]   response body: %s", t.service, respBody)
+	}
+	return resp, nil
+}
+
+// peekRequestBody returns the redacted, truncated request body without consuming it
+func peekRequestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return ""
+		}
+		defer body.Close()
+		data, _ := io.ReadAll(io.LimitReader(body, maxBody+1))
+		return formatBody(data)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	return formatBody(data)
+}
+
+// peekResponseBody returns the redacted, truncated response body, leaving it readable
+func peekResponseBody(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	return formatBody(data)
+}
+
+func formatBody(data []byte) string {
+	truncated := len(data) > maxBody
+	if truncated {
+		data = data[:maxBody]
+	}
+	body := Redact(strings.TrimSpace(string(data)))
+	if truncated {
+		body += " ...(truncated)"
+	}
+	return body
+}
+
+// Redact masks API keys, tokens, passwords and private keys in s
+func Redact(s string) string {
+	for i, pattern := range secretPatterns {
+		if i == 0 {
+			s = pattern.ReplaceAllString(s, `${1}"[REDACTED]"`)
+			continue
+		}
+		s = pattern.ReplaceAllString(s, "[REDACTED]")
+	}
+	return s
+}
+
+func redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		values := header[name]
+		value := strings.Join(values, ", ")
+		for _, sensitive := range sensitiveHeaders {
+			if strings.EqualFold(name, sensitive) {
+				value = "[REDACTED]"
+			}
+		}
+		parts = append(parts, name+": "+Redact(value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for name := range query {
+		for _, sensitive := range sensitiveParams {
+			if strings.EqualFold(name, sensitive) {
+				query.Set(name, "REDACTED")
+			}
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	redacted.User = nil
+	return redacted.String()
+}
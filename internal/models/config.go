@@ -7,6 +7,71 @@ type Config struct {
 	Harness  HarnessConfig  `yaml:"harness"`
 	Defaults DefaultsConfig `yaml:"defaults"`
 	Runtime  RuntimeConfig  `yaml:"runtime"`
+	Network  NetworkConfig  `yaml:"network"`
+
+	// Templates customize generated entities per component type (service, library, website, ...)
+	Templates map[string]ComponentTemplate `yaml:"templates"`
+
+	// Rules conditionally override defaults for repositories matching their conditions
+	Rules []Rule `yaml:"rules"`
+
+	// Projects routes matching repositories to other Harness orgs and projects; the
+	// first match wins and unmatched repositories use harness.org_id and project_id
+	Projects []ProjectRoute `yaml:"projects"`
+
+	// Targets are additional Harness accounts, by name, that project routes can send
+	// repositories to. Unset fields are taken from the harness section.
+	Targets map[string]HarnessConfig `yaml:"targets"`
+
+	// Domains and Systems are created as catalog entities; components matching a
+	// system get it as spec.system
+	Domains []DomainConfig `yaml:"domains"`
+	Systems []SystemConfig `yaml:"systems"`
+
+	// CustomProperties maps GitHub repository custom properties to catalog values
+	CustomProperties CustomPropertyMapping `yaml:"custom_properties"`
+
+	// TagPolicy normalizes and filters tags before entities are generated
+	TagPolicy TagPolicy `yaml:"tag_policy"`
+}
+
+// TagPolicy controls how tags from topics, languages, rules and templates are cleaned
+// up. Tags are lowercased unless KeepCase is set, and characters other than letters,
+// digits, "-", "_", "." and ":" are replaced with Replacement (default "-"). Allow and
+// Deny hold glob patterns matched against the normalized tag.
+type TagPolicy struct {
+	KeepCase    bool     `yaml:"keep_case"`
+	Replacement string   `yaml:"replacement"`
+	Allow       []string `yaml:"allow"` // when set, only matching tags are kept
+	Deny        []string `yaml:"deny"`
+	MaxTags     int      `yaml:"max_tags"` // 0 means no limit
+}
+
+// CustomPropertyMapping names the GitHub custom properties that supply catalog values.
+// Owner, System and Lifecycle name a single property; Tags lists properties whose
+// values become tags; Annotations maps annotation keys to property names.
+type CustomPropertyMapping struct {
+	Owner       string            `yaml:"owner"`
+	System      string            `yaml:"system"`
+	Lifecycle   string            `yaml:"lifecycle"`
+	Tags        []string          `yaml:"tags"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// DomainConfig describes a Domain entity to create
+type DomainConfig struct {
+	Name        string `yaml:"name"`
+	Owner       string `yaml:"owner"`
+	Description string `yaml:"description"`
+}
+
+// SystemConfig describes a System entity to create and the repositories that belong to it
+type SystemConfig struct {
+	Name        string    `yaml:"name"`
+	Owner       string    `yaml:"owner"`
+	Description string    `yaml:"description"`
+	Domain      string    `yaml:"domain"`
+	Match       RuleMatch `yaml:"match"`
 }
 
 type GitHubConfig struct {
@@ -14,6 +79,51 @@ type GitHubConfig struct {
 	AppID        int64  `yaml:"app_id"`
 	PrivateKey   string `yaml:"private_key"`
 	InstallID    int64  `yaml:"install_id"`
+
+	// Applied to every onboarding PR; labels also identify existing onboarding PRs
+	PRLabels    []string `yaml:"pr_labels"`
+	PRAssignees []string `yaml:"pr_assignees"`
+	PRMilestone string   `yaml:"pr_milestone"` // milestone title
+
+	// Go template files for onboarding PR titles and bodies, rendered against the repository
+	PRTitleTemplate string `yaml:"pr_title_template"`
+	PRBodyTemplate  string `yaml:"pr_body_template"`
+
+	// BranchTemplate names onboarding branches, e.g. "chore/idp-onboarding". Without
+	// {{ .Timestamp }} the name is stable across runs and the branch is reused.
+	BranchTemplate string `yaml:"branch_template"`
+
+	// Author and committer of onboarding commits; the app identity is used when unset
+	CommitAuthorName  string `yaml:"commit_author_name"`
+	CommitAuthorEmail string `yaml:"commit_author_email"`
+
+	// CatalogRepo (owner/name) collects every generated catalog file under CatalogDir
+	// in one central repository instead of opening a PR in each repository
+	CatalogRepo string `yaml:"catalog_repo"`
+	CatalogDir  string `yaml:"catalog_dir"`
+
+	// CommitStatus posts a "harness-onboarder/catalog" status with the lint result of
+	// the generated file on each onboarding commit
+	CommitStatus bool `yaml:"commit_status"`
+
+	// BaseBranch is the branch onboarding PRs target, e.g. "develop", instead of each
+	// repository's default branch. The repositories CSV can override it per repository.
+	BaseBranch string `yaml:"base_branch"`
+}
+
+// NetworkConfig applies to both the GitHub and Harness HTTP clients
+type NetworkConfig struct {
+	// ProxyURL routes all requests through this proxy, e.g. http://proxy.example.com:3128.
+	// Without it HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honored; NO_PROXY applies either way.
+	ProxyURL string `yaml:"proxy_url"`
+
+	// CABundle is a PEM file of certificates trusted in addition to the system roots,
+	// for TLS-intercepting proxies and internally-signed GitHub Enterprise Server instances
+	CABundle string `yaml:"ca_bundle"`
+
+	// ClientCert and ClientKey are PEM files presented when a server or proxy requires mTLS
+	ClientCert string `yaml:"client_cert"`
+	ClientKey  string `yaml:"client_key"`
 }
 
 type HarnessConfig struct {
@@ -23,26 +133,184 @@ type HarnessConfig struct {
 	OrgID         string `yaml:"org_id"`
 	ProjectID     string `yaml:"project_id"`
 	ConnectorRef  string `yaml:"connector_ref,omitempty"`
+
+	// Scope is where entities are created: "project" (default), "org" or "account".
+	// At org and account scope the narrower identifiers are left out of entity YAML.
+	Scope string `yaml:"scope"`
+
+	// After BreakerThreshold consecutive server errors or timeouts, requests pause for
+	// BreakerCooldown before trying again
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`
+
+	// HTTP transport settings, for proxies that add latency; zero values keep the defaults
+	// of a 30s request timeout, 10 idle connections, a 10s TLS handshake and no retries
+	Timeout             time.Duration `yaml:"timeout"`
+	MaxIdleConns        int           `yaml:"max_idle_conns"`
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout"`
+	Retries             int           `yaml:"retries"` // retries of timeouts, 429s and 5xx responses
 }
 
 type DefaultsConfig struct {
 	Owner       string            `yaml:"owner"`
+	Kind        string            `yaml:"kind"` // Component (default), API, Resource, System or Group
 	Type        string            `yaml:"type"`
 	Lifecycle   string            `yaml:"lifecycle"`
 	System      string            `yaml:"system"`
+	Domain      string            `yaml:"domain"` // spec.domain of System entities
 	Tags        map[string]string `yaml:"tags"`
 	Annotations map[string]string `yaml:"annotations"`
+
+	SkipTypeInference bool `yaml:"skip_type_inference"`
+
+	// Lifecycle inference: archived repositories are deprecated; young repositories and
+	// those with an experimental topic are experimental
+	SkipLifecycleInference bool     `yaml:"skip_lifecycle_inference"`
+	ExperimentalDays       int      `yaml:"experimental_days"` // negative disables the age check
+	ExperimentalTopics     []string `yaml:"experimental_topics"`
+}
+
+// ComponentTemplate adds type-specific annotations, tags and links to generated
+// entities. Values are Go templates rendered against the repository; annotations
+// and links that render empty are omitted.
+type ComponentTemplate struct {
+	Annotations map[string]string `yaml:"annotations"`
+	Tags        []string          `yaml:"tags"`
+	Links       []ComponentLink   `yaml:"links"`
+}
+
+// Rule sets component values for repositories that match all of its conditions.
+// Rules are applied in order, so later matches override earlier ones.
+type Rule struct {
+	Name  string      `yaml:"name"`
+	Match RuleMatch   `yaml:"match"`
+	Set   RuleActions `yaml:"set"`
+}
+
+// RuleMatch lists the conditions of a rule. Empty conditions always match.
+type RuleMatch struct {
+	Languages []string `yaml:"languages"` // any of, case-insensitive
+	Topics    []string `yaml:"topics"`    // any of
+	Name      string   `yaml:"name"`      // regular expression on the repository name
+	Archived  *bool    `yaml:"archived"`
+	Teams     []string `yaml:"teams"` // any of the CODEOWNERS teams, as org/team or team
+}
+
+// ProjectRoute sends repositories matching its conditions to a Harness org and
+// project, in another account when Target names one. Empty identifiers keep those of
+// the target, or of the harness section.
+type ProjectRoute struct {
+	Name      string    `yaml:"name"`
+	Match     RuleMatch `yaml:"match"`
+	Target    string    `yaml:"target"`
+	OrgID     string    `yaml:"org_id"`
+	ProjectID string    `yaml:"project_id"`
+}
+
+// RuleActions are the values a matching rule sets
+type RuleActions struct {
+	Kind        string            `yaml:"kind"`
+	Type        string            `yaml:"type"`
+	Lifecycle   string            `yaml:"lifecycle"`
+	System      string            `yaml:"system"`
+	Domain      string            `yaml:"domain"`
+	Owner       string            `yaml:"owner"`
+	Tags        []string          `yaml:"tags"`
+	Annotations map[string]string `yaml:"annotations"` // Values may be templates, e.g. "{{ .Name }}"
 }
 
 type RuntimeConfig struct {
-	Mode          string        `yaml:"mode"`
-	Concurrency   int           `yaml:"concurrency"`
-	DryRun        bool          `yaml:"dry_run"`
-	RateLimit     time.Duration `yaml:"rate_limit"`
-	LogLevel      string        `yaml:"log_level"`
-	IncludeRepos  []string      `yaml:"include_repos"`
-	ExcludeRepos  []string      `yaml:"exclude_repos"`
-	RequiredFiles []string      `yaml:"required_files"`
+	Mode               string        `yaml:"mode"`
+	Source             string        `yaml:"source"` // Where repositories are hosted: github (default) or harness-code
+	Concurrency        int           `yaml:"concurrency"`
+	DryRun             bool          `yaml:"dry_run"`
+	RateLimit          time.Duration `yaml:"rate_limit"`
+	LogLevel           string        `yaml:"log_level"`
+	TraceHTTP          bool          `yaml:"trace_http"` // Log redacted GitHub and Harness HTTP calls
+	CacheDir           string        `yaml:"cache_dir"`  // Keep GitHub responses here and revalidate them with ETags
+	IncludeRepos       []string      `yaml:"include_repos"`
+	ExcludeRepos       []string      `yaml:"exclude_repos"`
+	IncludeTopics      []string      `yaml:"include_topics"` // Only repositories with any of these topics
+	ExcludeTopics      []string      `yaml:"exclude_topics"` // Skip repositories with any of these topics
+	Visibility         []string      `yaml:"visibility"`     // Only repositories with one of these visibilities: public, private, internal
+	ExcludeForks       bool          `yaml:"exclude_forks"`
+	ExcludeTemplates   bool          `yaml:"exclude_templates"`
+	ExcludeMirrors     bool          `yaml:"exclude_mirrors"`
+	ActiveWithin       string        `yaml:"active_within"` // Skip repositories not pushed to within this age, e.g. 180d
+	MinStars           int           `yaml:"min_stars"`
+	MinSizeKB          int           `yaml:"min_size_kb"`
+	MaxSizeKB          int           `yaml:"max_size_kb"` // 0 for no limit
+	Filter             string        `yaml:"filter"`      // CEL expression over repo, e.g. repo.Language == "Go"
+	Team               string        `yaml:"team"`        // Only repositories this GitHub team can write to
+	RequiredFiles      []string      `yaml:"required_files"`
+	StateFile          string        `yaml:"state_file"`
+	StateLockWait      time.Duration `yaml:"state_lock_wait"` // wait for another run's state lock, 0 to fail at once
+	StateLockTTL       time.Duration `yaml:"state_lock_ttl"`  // expiry of the state lock, renewed while the run goes on
+	Daemon             bool          `yaml:"daemon"`
+	Interval           time.Duration `yaml:"interval"`
+	SyncTeams          bool          `yaml:"sync_teams"`
+	ReposCSV           string        `yaml:"repos_csv"`
+	ReposFile          string        `yaml:"repos_file"` // Repositories to process, one per line; "-" reads stdin
+	Search             string        `yaml:"search"`     // GitHub repository search query selecting the repositories to process
+	Sort               string        `yaml:"sort"`       // Processing order: name (default) or pushed, most recently pushed first
+	Limit              int           `yaml:"limit"`      // Process at most this many repositories, 0 for all
+	Shard              string        `yaml:"shard"`      // index/count part of the repositories this runner processes, e.g. 2/5
+	Stream             bool          `yaml:"stream"`     // process each page of repositories as it's listed instead of after discovery
+	OwnersMap          string        `yaml:"owners_map"`
+	ValidateOwners     bool          `yaml:"validate_owners"`     // replace owner groups missing in Harness with the default owner
+	CreateOwnerGroups  bool          `yaml:"create_owner_groups"` // create owner groups missing in Harness instead
+	ReportFile         string        `yaml:"report_file"`
+	OnlyFailed         bool          `yaml:"only_failed"`
+	RetryBackoff       time.Duration `yaml:"retry_backoff"`
+	LegacyStrategy     string        `yaml:"legacy_strategy"`
+	TechDocs           bool          `yaml:"techdocs"`
+	LanguageThreshold  float64       `yaml:"language_threshold"`  // Minimum share (percent) for a language to be tagged
+	IdentifierTemplate string        `yaml:"identifier_template"` // Go template, e.g. "{{ .Org }}_{{ .Repo | snakecase }}"
+	IdentifierPrefix   string        `yaml:"identifier_prefix"`
+	IdentifierSuffix   string        `yaml:"identifier_suffix"`
+	MergeExisting      bool          `yaml:"merge_existing"`
+	PatchExisting      bool          `yaml:"patch_existing"`
+	IncludeArchived    bool          `yaml:"include_archived"`
+	UpdateOpenPRs      bool          `yaml:"update_open_prs"`
+	CatalogPath        string        `yaml:"catalog_path"`      // Where yaml mode writes new catalog files
+	CommitDirect       bool          `yaml:"commit_direct"`     // Commit to the default branch instead of opening a PR
+	BatchBy            string        `yaml:"batch_by"`          // Split catalog repository PRs by "owner"
+	BatchSize          int           `yaml:"batch_size"`        // Maximum repositories per catalog repository PR
+	IssueFallback      bool          `yaml:"issue_fallback"`    // Open an issue when permissions or branch protection block the PR
+	OnboardedTopic     string        `yaml:"onboarded_topic"`   // GitHub topic added to repositories once they are in IDP
+	PipelineStarter    bool          `yaml:"pipeline_starter"`  // Add .harness/pipeline.yaml to onboarding PRs
+	PipelineTemplate   string        `yaml:"pipeline_template"` // Go template file for the pipeline starter
+	ReadmeBadge        bool          `yaml:"readme_badge"`      // Add a "View in Harness IDP" badge to README.md in onboarding PRs
+	ReadmeBadgeURL     string        `yaml:"readme_badge_url"`  // Go template for the badge link
+	APIBatchSize       int           `yaml:"api_batch_size"`    // Components created per batch in api mode, 0 for one at a time
+	RegisterBatchSize  int           `yaml:"register_batch_size"` // Catalog files imported per batch in register mode, 0 for one at a time
+	CodeSearch         bool          `yaml:"code_search"`         // Find catalog files with one org-wide code search in register mode
+
+	// SkipHarnessValidation turns off the dry run of generated catalog files against
+	// the Harness entities API before onboarding PRs are opened
+	SkipHarnessValidation bool `yaml:"skip_harness_validation"`
+
+	// ProvisionConnector creates or updates harness.connector_ref as a GitHub App
+	// connector from the github section's app credentials before register mode runs
+	ProvisionConnector bool `yaml:"provision_connector"`
+
+	// Policies are Rego files or directories whose data.idp.deny rules every generated
+	// entity must pass before it is submitted
+	Policies []string `yaml:"policies"`
+
+	// Scorecards fetches the scorecard scores of created and registered entities into
+	// the run summary and report
+	Scorecards bool `yaml:"scorecards"`
+}
+
+// RepoOverride holds per-repository values that take precedence over the global defaults
+type RepoOverride struct {
+	Owner      string
+	Type       string
+	Lifecycle  string
+	System     string
+	Tags       []string
+	BaseBranch string
 }
 
 type Repository struct {
@@ -51,24 +319,43 @@ type Repository struct {
 	FullName        string            `json:"full_name"`
 	Description     string            `json:"description"`
 	HTMLURL         string            `json:"html_url"`
+	Homepage        string            `json:"homepage"`
 	CloneURL        string            `json:"clone_url"`
 	Language        string            `json:"language"`
+	Languages       map[string]int    `json:"languages,omitempty"` // Bytes of code per language
+	Properties      map[string]string `json:"custom_properties,omitempty"` // GitHub custom property values
 	Topics          []string          `json:"topics"`
 	Private         bool              `json:"private"`
+	Visibility      string            `json:"visibility"` // public, private or internal
 	Archived        bool              `json:"archived"`
+	Fork            bool              `json:"fork"`
+	IsTemplate      bool              `json:"is_template"`
+	MirrorURL       string            `json:"mirror_url,omitempty"` // Upstream of a mirror repository
 	CreatedAt       time.Time         `json:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at"`
 	PushedAt        time.Time         `json:"pushed_at"`
 	CodeOwners      []string          `json:"code_owners"`
 	HasDockerfile   bool              `json:"has_dockerfile"`
 	HasKubernetes   bool              `json:"has_kubernetes"`
+	K8sSelector     string            `json:"kubernetes_selector,omitempty"`  // Pod label selector from the repository's manifests or Helm chart
+	K8sNamespace    string            `json:"kubernetes_namespace,omitempty"` // Namespace from the repository's manifests
 	HasCI           bool              `json:"has_ci"`
+	CISystems       []string          `json:"ci_systems,omitempty"` // e.g. github-actions, jenkins, circleci, harness, gitlab-ci
+	HasDocs         bool              `json:"has_docs"` // mkdocs.yml or docs/ at the repository root
+	Modules         []string          `json:"modules,omitempty"`      // Module names published by the repository
+	Dependencies    []string          `json:"dependencies,omitempty"` // Module names the repository depends on
+	APISpecPath     string            `json:"api_spec_path,omitempty"` // OpenAPI/Swagger definition, if any
+	IaCTool         string            `json:"iac_tool,omitempty"`      // terraform or pulumi for infrastructure repositories
+	CatalogFiles    []string          `json:"catalog_files,omitempty"` // Catalog search paths found in the tree during enrichment; nil when not known
 	DefaultBranch   string            `json:"default_branch"`
+	BaseBranch      string            `json:"base_branch,omitempty"` // PR base when it differs from DefaultBranch
 	Stars           int               `json:"stars"`
+	SizeKB          int               `json:"size_kb"`
 	Forks           int               `json:"forks"`
 	OpenIssues      int               `json:"open_issues"`
 	License         string            `json:"license"`
 	Metadata        map[string]string `json:"metadata"`
+	Source          string            `json:"source,omitempty"` // harness-code for Harness Code repositories, empty for GitHub
 }
 
 type CatalogInfo struct {
@@ -76,9 +363,9 @@ type CatalogInfo struct {
 	Identifier        string            `yaml:"identifier"`
 	Name              string            `yaml:"name"`
 	Kind              string            `yaml:"kind"`
-	Type              string            `yaml:"type"`
-	ProjectIdentifier string            `yaml:"projectIdentifier"`
-	OrgIdentifier     string            `yaml:"orgIdentifier"`
+	Type              string            `yaml:"type,omitempty"`
+	ProjectIdentifier string            `yaml:"projectIdentifier,omitempty"`
+	OrgIdentifier     string            `yaml:"orgIdentifier,omitempty"`
 	Owner             string            `yaml:"owner"`
 	Metadata          CatalogMetadata   `yaml:"metadata,omitempty"`
 	Spec              CatalogSpec       `yaml:"spec"`
@@ -91,27 +378,76 @@ type CatalogMetadata struct {
 	Links       []ComponentLink   `yaml:"links,omitempty"`
 }
 
+// CatalogSpec holds the spec fields of every supported kind; fields a kind doesn't use
+// are left empty
 type CatalogSpec struct {
-	Lifecycle string `yaml:"lifecycle"`
+	Lifecycle    string            `yaml:"lifecycle,omitempty"`
+	System       string            `yaml:"system,omitempty"`
+	Domain       string            `yaml:"domain,omitempty"`
+	DependsOn    []string          `yaml:"dependsOn,omitempty"`
+	ProvidesAPIs []string          `yaml:"providesApis,omitempty"`
+	Definition   map[string]string `yaml:"definition,omitempty"`
+	Members      []string          `yaml:"members,omitempty"`
 }
 
 type HarnessComponent struct {
-	// IDP 2.0 required fields
-	Identifier  string `json:"identifier"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Lifecycle   string `json:"lifecycle"`
-	Owner       string `json:"owner"`
-	
+	// IDP 2.0 required fields; System and Group entities have no type or lifecycle
+	Kind       string `json:"kind,omitempty"` // Component unless set: API, Resource, System or Group
+	Identifier string `json:"identifier"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Lifecycle  string `json:"lifecycle"`
+	Owner      string `json:"owner"`
+
 	// Optional fields
-	System      string            `json:"system,omitempty"`
+	System       string            `json:"system,omitempty"`
+	DependsOn    []string          `json:"dependsOn,omitempty"`
+	ProvidesAPIs []string          `json:"providesApis,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Links        []ComponentLink   `json:"links,omitempty"`
+
+	// Kind-specific spec fields
+	Definition string   `json:"definition,omitempty"` // API: URL of the API definition
+	Domain     string   `json:"domain,omitempty"`     // System
+	Members    []string `json:"members,omitempty"`    // Group
+
+	// IDP 2.0 metadata structure
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// HarnessEntity is a generic catalog entity such as a System or Domain
+type HarnessEntity struct {
+	Kind        string                 `json:"kind"`
+	Type        string                 `json:"type,omitempty"`
+	Identifier  string                 `json:"identifier"`
+	Name        string                 `json:"name"`
+	Owner       string                 `json:"owner"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	Spec        map[string]interface{} `json:"spec,omitempty"`
+}
+
+// Team is a GitHub team with its member logins
+type Team struct {
+	Slug        string   `json:"slug"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Parent      string   `json:"parent,omitempty"`
+	HTMLURL     string   `json:"html_url"`
+	Members     []string `json:"members"`
+}
+
+// HarnessGroup is an IDP Group entity generated from a GitHub team
+type HarnessGroup struct {
+	Identifier  string            `json:"identifier"`
+	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
-	Tags        []string          `json:"tags,omitempty"`
+	Parent      string            `json:"parent,omitempty"`
+	Members     []string          `json:"members"`
 	Annotations map[string]string `json:"annotations,omitempty"`
-	Links       []ComponentLink   `json:"links,omitempty"`
-	
-	// IDP 2.0 metadata structure
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type ComponentLink struct {
@@ -0,0 +1,133 @@
+// Package network builds the HTTP transports shared by the GitHub and Harness clients,
+// applying proxy and TLS settings for corporate networks.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"harness-onboarder/internal/models"
+)
+
+// settings is the configuration applied by Transport, set once by Configure
+var settings models.NetworkConfig
+
+// tlsConfig holds the CA bundle and client certificate, nil when neither is configured
+var tlsConfig *tls.Config
+
+// Configure validates and records the network settings, loading the CA bundle and
+// client certificate; call it before creating clients
+func Configure(cfg models.NetworkConfig) error {
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q: expected e.g. http://proxy.example.com:3128", cfg.ProxyURL)
+		}
+	}
+
+	tc, err := loadTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	settings = cfg
+	tlsConfig = tc
+	return nil
+}
+
+func loadTLSConfig(cfg models.NetworkConfig) (*tls.Config, error) {
+	if cfg.CABundle == "" && cfg.ClientCert == "" && cfg.ClientKey == "" {
+		return nil, nil
+	}
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", cfg.CABundle)
+		}
+		tc.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("client_cert and client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
+
+// Transport returns a new transport with Go's default settings and the configured
+// proxy, CA bundle and client certificate. Without --proxy-url, HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY are honored.
+func Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+	return transport
+}
+
+func proxyFunc() func(*http.Request) (*url.URL, error) {
+	if settings.ProxyURL == "" {
+		return http.ProxyFromEnvironment
+	}
+	proxy, _ := url.Parse(settings.ProxyURL)
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxy, nil
+	}
+}
+
+// bypassProxy reports whether host matches a NO_PROXY entry: "*", an exact host or IP,
+// or a domain suffix such as ".example.com" or "example.com"
+func bypassProxy(host, noProxy string) bool {
+	host = strings.ToLower(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip := net.ParseIP(host); ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		entry = strings.TrimPrefix(entry, "*")
+		if host == strings.TrimPrefix(entry, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(entry, ".")) {
+			return true
+		}
+	}
+	return false
+}
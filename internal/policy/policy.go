@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+	"gopkg.in/yaml.v3"
+)
+
+// Query is the rule generated entities are evaluated against: a set of messages, one
+// per violation, in package idp. For example:
+//
+//	package idp
+//
+//	deny contains msg if {
+//		not startswith(input.owner, "group:")
+//		msg := sprintf("owner %q is not a group", [input.owner])
+//	}
+const Query = "data.idp.deny"
+
+// Engine evaluates catalog entities against a set of Rego policies
+type Engine struct {
+	query rego.PreparedEvalQuery
+}
+
+// Load compiles the Rego policies in the given files and directories. JSON and YAML
+// files alongside them are loaded as data.
+func Load(ctx context.Context, paths []string) (*Engine, error) {
+	query, err := rego.New(rego.Query(Query), rego.Load(paths, nil)).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+	return &Engine{query: query}, nil
+}
+
+// Violation is a deny message for one document of a catalog file
+type Violation struct {
+	Document int
+	Message  string
+}
+
+func (v Violation) String() string {
+	if v.Document > 1 {
+		return fmt.Sprintf("[doc %d] %s", v.Document, v.Message)
+	}
+	return v.Message
+}
+
+// Evaluate checks each document of catalog YAML content against the policies, with the
+// document as input, and returns the violations sorted by document and message
+func (e *Engine) Evaluate(ctx context.Context, content string) ([]Violation, error) {
+	var violations []Violation
+
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	for doc := 1; ; doc++ {
+		var entity map[string]interface{}
+		err := decoder.Decode(&entity)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", doc, err)
+		}
+		if entity == nil {
+			continue
+		}
+
+		results, err := e.query.Eval(ctx, rego.EvalInput(entity))
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate policies on document %d: %w", doc, err)
+		}
+
+		var messages []string
+		for _, result := range results {
+			for _, expr := range result.Expressions {
+				values, ok := expr.Value.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s must be a set of messages, got %T", Query, expr.Value)
+				}
+				for _, value := range values {
+					messages = append(messages, fmt.Sprint(value))
+				}
+			}
+		}
+		sort.Strings(messages)
+		for _, message := range messages {
+			violations = append(violations, Violation{Document: doc, Message: message})
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,269 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/state"
+)
+
+// Entry is one repository row in a report
+type Entry struct {
+	Repository    string
+	Status        string
+	Action        string
+	Message       string
+	Identifier    string
+	URL           string
+	ErrorCategory string
+	Error         string
+	Scores        string // formatted scorecard scores, when fetched
+	ProcessedAt   time.Time
+}
+
+// Report is a shareable summary of an onboarding run
+type Report struct {
+	Title        string
+	Organization string
+	Mode         string
+	GeneratedAt  time.Time
+	Entries      []Entry
+}
+
+// FromSummary builds report entries from the results of a run
+func FromSummary(summary *errors.ErrorSummary) []Entry {
+	entries := make([]Entry, 0, len(summary.Results))
+	for _, result := range summary.Results {
+		entry := Entry{
+			Repository: result.Repository,
+			Status:     state.StatusSuccess,
+			Action:     result.Action,
+			Message:    result.Message,
+			Identifier: result.Identifier,
+			URL:        result.URL,
+		}
+		if result.Scores != nil {
+			entry.Scores = errors.FormatScores(result.Scores)
+		}
+		if result.Skipped {
+			entry.Status = state.StatusSkipped
+		}
+		if result.Error != nil {
+			if !result.Skipped {
+				entry.Status = state.StatusError
+			}
+			entry.ErrorCategory = string(result.Error.Category)
+			entry.Error = result.Error.GetUserFriendlyMessage()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// FromState builds report entries from the last recorded outcome of each repository
+func FromState(states []state.RepoState) []Entry {
+	entries := make([]Entry, 0, len(states))
+	for _, s := range states {
+		entries = append(entries, Entry{
+			Repository:    s.Repository,
+			Status:        s.Status,
+			Action:        s.Action,
+			Message:       s.Message,
+			Identifier:    s.Identifier,
+			URL:           s.URL,
+			ErrorCategory: s.ErrorCategory,
+			Error:         s.Error,
+			ProcessedAt:   s.LastProcessed,
+		})
+	}
+	return entries
+}
+
+// Counts returns the number of entries per status and per error category
+func (r *Report) Counts() (byStatus map[string]int, byCategory map[string]int) {
+	byStatus = make(map[string]int)
+	byCategory = make(map[string]int)
+	for _, e := range r.Entries {
+		byStatus[e.Status]++
+		if e.ErrorCategory != "" {
+			byCategory[e.ErrorCategory]++
+		}
+	}
+	return byStatus, byCategory
+}
+
+func (r *Report) sortEntries() {
+	sort.Slice(r.Entries, func(i, j int) bool {
+		return r.Entries[i].Repository < r.Entries[j].Repository
+	})
+}
+
+// Write renders the report in the given format ("markdown" or "html")
+func (r *Report) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return r.WriteMarkdown(w)
+	case "html":
+		return r.WriteHTML(w)
+	default:
+		return fmt.Errorf("unsupported report format: %s (supported: markdown, html)", format)
+	}
+}
+
+// FormatForPath infers the report format from a file extension
+func FormatForPath(path string) string {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".html") || strings.HasSuffix(lower, ".htm") {
+		return "html"
+	}
+	return "markdown"
+}
+
+// WriteMarkdown renders the report as a Markdown document
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	r.sortEntries()
+	byStatus, byCategory := r.Counts()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	if r.Organization != "" {
+		fmt.Fprintf(&b, "- **Organization:** %s\n", r.Organization)
+	}
+	if r.Mode != "" {
+		fmt.Fprintf(&b, "- **Mode:** %s\n", r.Mode)
+	}
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", r.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Status | Count |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Total | %d |\n", len(r.Entries))
+	for _, status := range []string{state.StatusSuccess, state.StatusSkipped, state.StatusError} {
+		fmt.Fprintf(&b, "| %s | %d |\n", status, byStatus[status])
+	}
+
+	if len(byCategory) > 0 {
+		b.WriteString("\n## Errors by Category\n\n")
+		b.WriteString("| Category | Count |\n|---|---|\n")
+		for _, category := range sortedKeys(byCategory) {
+			fmt.Fprintf(&b, "| %s | %d |\n", category, byCategory[category])
+		}
+	}
+
+	b.WriteString("\n## Repositories\n\n")
+	b.WriteString("| Repository | Status | Action | Identifier | Link | Details |\n|---|---|---|---|---|---|\n")
+	for _, e := range r.Entries {
+		link := ""
+		if e.URL != "" {
+			link = fmt.Sprintf("[link](%s)", e.URL)
+		}
+		details := e.Message
+		if e.Error != "" {
+			details = fmt.Sprintf("%s: %s", e.Message, e.Error)
+		}
+		if e.Scores != "" {
+			details = fmt.Sprintf("%s (scorecards: %s)", details, e.Scores)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(e.Repository), e.Status, e.Action, markdownCell(e.Identifier), link, markdownCell(details))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Report.Title }}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; }
+th { background: #f4f4f4; }
+.success { color: #1a7f37; }
+.skipped { color: #9a6700; }
+.error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>{{ .Report.Title }}</h1>
+<ul>
+{{ if .Report.Organization }}<li><b>Organization:</b> {{ .Report.Organization }}</li>{{ end }}
+{{ if .Report.Mode }}<li><b>Mode:</b> {{ .Report.Mode }}</li>{{ end }}
+<li><b>Generated:</b> {{ .Generated }}</li>
+</ul>
+<h2>Summary</h2>
+<table>
+<tr><th>Status</th><th>Count</th></tr>
+<tr><td>Total</td><td>{{ len .Report.Entries }}</td></tr>
+{{ range .Statuses }}<tr><td class="{{ .Name }}">{{ .Name }}</td><td>{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ if .Categories }}<h2>Errors by Category</h2>
+<table>
+<tr><th>Category</th><th>Count</th></tr>
+{{ range .Categories }}<tr><td>{{ .Name }}</td><td>{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ end }}<h2>Repositories</h2>
+<table>
+<tr><th>Repository</th><th>Status</th><th>Action</th><th>Identifier</th><th>Link</th><th>Details</th></tr>
+{{ range .Report.Entries }}<tr>
+<td>{{ .Repository }}</td>
+<td class="{{ .Status }}">{{ .Status }}</td>
+<td>{{ .Action }}</td>
+<td>{{ .Identifier }}</td>
+<td>{{ if .URL }}<a href="{{ .URL }}">link</a>{{ end }}</td>
+<td>{{ .Message }}{{ if .Error }}<br><span class="error">{{ .Error }}</span>{{ end }}{{ if .Scores }}<br>Scorecards: {{ .Scores }}{{ end }}</td>
+</tr>
+{{ end }}</table>
+</body>
+</html>
+`))
+
+type count struct {
+	Name  string
+	Count int
+}
+
+// WriteHTML renders the report as a standalone HTML page
+func (r *Report) WriteHTML(w io.Writer) error {
+	r.sortEntries()
+	byStatus, byCategory := r.Counts()
+
+	var statuses []count
+	for _, status := range []string{state.StatusSuccess, state.StatusSkipped, state.StatusError} {
+		statuses = append(statuses, count{Name: status, Count: byStatus[status]})
+	}
+
+	var categories []count
+	for _, category := range sortedKeys(byCategory) {
+		categories = append(categories, count{Name: category, Count: byCategory[category]})
+	}
+
+	return htmlTemplate.Execute(w, map[string]interface{}{
+		"Report":     r,
+		"Generated":  r.GeneratedAt.Format(time.RFC3339),
+		"Statuses":   statuses,
+		"Categories": categories,
+	})
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// markdownCell escapes characters that would break a Markdown table row
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,217 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Backend stores the state between runs. Runs in ephemeral containers point
+// --state-file at object storage or a database instead of a local file.
+type Backend interface {
+	// Load returns the stored state, or nil when nothing has been saved yet
+	Load(ctx context.Context) (*File, error)
+	// Save replaces the stored state
+	Save(ctx context.Context, data *File) error
+	// Location describes where the state is stored, for logs
+	Location() string
+
+	// ReadLease returns the current lease on the state and its version, or nil and
+	// an empty version when there is none
+	ReadLease(ctx context.Context) (*Lease, string, error)
+	// WriteLease replaces the lease at version, or creates it when version is empty,
+	// and returns the new version. It fails with ErrLeaseConflict when the lease
+	// changed since version was read, so two runs can't both take it.
+	WriteLease(ctx context.Context, lease Lease, version string) (string, error)
+	// DeleteLease removes the lease if it's still at version
+	DeleteLease(ctx context.Context, version string) error
+}
+
+// NewBackend picks the backend for a state location:
+//
+//	s3://bucket/key.json       Amazon S3, or an S3-compatible store via AWS_ENDPOINT_URL_S3
+//	gs://bucket/object.json    Google Cloud Storage
+//	sqlite:///path/state.db    SQLite database
+//	anything else              local JSON file
+func NewBackend(ctx context.Context, location string) (Backend, error) {
+	scheme, rest, ok := strings.Cut(location, "://")
+	if !ok {
+		return &fileBackend{path: location}, nil
+	}
+
+	switch scheme {
+	case "s3":
+		bucket, key, err := splitObjectURL(scheme, rest)
+		if err != nil {
+			return nil, err
+		}
+		return newS3Backend(ctx, bucket, key)
+	case "gs":
+		bucket, key, err := splitObjectURL(scheme, rest)
+		if err != nil {
+			return nil, err
+		}
+		return newGCSBackend(ctx, bucket, key)
+	case "sqlite":
+		if rest == "" {
+			return nil, fmt.Errorf("invalid state location %s: missing database path", location)
+		}
+		return newSQLiteBackend(ctx, rest)
+	default:
+		return nil, fmt.Errorf("unsupported state location %s (supported: s3://, gs://, sqlite:// or a file path)", location)
+	}
+}
+
+// splitObjectURL splits the bucket/key part of an object storage URL
+func splitObjectURL(scheme, rest string) (string, string, error) {
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid state location %s://%s: expected %s://bucket/key", scheme, rest, scheme)
+	}
+	return bucket, key, nil
+}
+
+// decodeFile parses state saved as JSON, returning nil for empty content
+func decodeFile(content []byte, location string) (*File, error) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+	var data File
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", location, err)
+	}
+	return &data, nil
+}
+
+// fileBackend keeps the state in a local JSON file
+type fileBackend struct {
+	path string
+}
+
+func (b *fileBackend) Location() string {
+	return b.path
+}
+
+func (b *fileBackend) Load(ctx context.Context) (*File, error) {
+	content, err := os.ReadFile(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	return decodeFile(content, b.path)
+}
+
+// Save writes the state file atomically
+func (b *fileBackend) Save(ctx context.Context, data *File) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	dir := filepath.Dir(b.path)
+	tmpFile, err := os.CreateTemp(dir, ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	tmpFile.Close()
+
+	if err := os.Rename(tmpFile.Name(), b.path); err != nil {
+		os.Remove(tmpFile.Name())
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
+
+// lockPath is the file holding the lease on the state file
+func (b *fileBackend) lockPath() string {
+	return b.path + ".lock"
+}
+
+// ReadLease reads the lease file; its content is the lease's version
+func (b *fileBackend) ReadLease(ctx context.Context) (*Lease, string, error) {
+	content, err := os.ReadFile(b.lockPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	var lease Lease
+	if err := json.Unmarshal(content, &lease); err != nil {
+		return nil, "", fmt.Errorf("failed to parse state lock %s: %w", b.lockPath(), err)
+	}
+	return &lease, string(content), nil
+}
+
+// WriteLease moves the current lease file aside with a rename, which only one run
+// can do, then links the new one into place, which fails if another run got there
+// first. Readers never see a partly written lease.
+func (b *fileBackend) WriteLease(ctx context.Context, lease Lease, version string) (string, error) {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state lock: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(b.path), ".state-lock-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary state lock: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(content)
+	tmpFile.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write state lock: %w", err)
+	}
+
+	if version != "" {
+		if err := b.removeLease(version); err != nil {
+			return "", err
+		}
+	}
+	if err := os.Link(tmpFile.Name(), b.lockPath()); err != nil {
+		if os.IsExist(err) {
+			return "", ErrLeaseConflict
+		}
+		return "", fmt.Errorf("failed to write state lock: %w", err)
+	}
+	return string(content), nil
+}
+
+func (b *fileBackend) DeleteLease(ctx context.Context, version string) error {
+	return b.removeLease(version)
+}
+
+// removeLease renames the lease file aside and deletes it if it's still at version.
+// Otherwise it's linked back into place, which fails rather than overwriting a lease
+// another run wrote meanwhile; that run then holds the lock.
+func (b *fileBackend) removeLease(version string) error {
+	aside := b.lockPath() + "." + strconv.Itoa(os.Getpid())
+	if err := os.Rename(b.lockPath(), aside); err != nil {
+		if os.IsNotExist(err) {
+			return ErrLeaseConflict
+		}
+		return fmt.Errorf("failed to replace state lock: %w", err)
+	}
+	content, err := os.ReadFile(aside)
+	if err != nil || string(content) != version {
+		if err := os.Link(aside, b.lockPath()); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("failed to restore state lock from %s: %w", aside, err)
+		}
+		os.Remove(aside)
+		return ErrLeaseConflict
+	}
+	return os.Remove(aside)
+}
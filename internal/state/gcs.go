@@ -0,0 +1,147 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+// gcsBackend keeps the state as a JSON object in Google Cloud Storage, using
+// Application Default Credentials
+type gcsBackend struct {
+	client *storage.Client
+	bucket string
+	object string
+}
+
+func newGCSBackend(ctx context.Context, bucket, object string) (*gcsBackend, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Cloud Storage client: %w", err)
+	}
+	return &gcsBackend{client: client, bucket: bucket, object: object}, nil
+}
+
+func (b *gcsBackend) Location() string {
+	return "gs://" + b.bucket + "/" + b.object
+}
+
+func (b *gcsBackend) Load(ctx context.Context) (*File, error) {
+	reader, err := b.client.Bucket(b.bucket).Object(b.object).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	return decodeFile(content, b.Location())
+}
+
+// Save uploads the state; the object is only replaced once the upload completes
+func (b *gcsBackend) Save(ctx context.Context, data *File) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	writer := b.client.Bucket(b.bucket).Object(b.object).NewWriter(ctx)
+	writer.ContentType = "application/json"
+	if _, err := writer.Write(content); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	return nil
+}
+
+func (b *gcsBackend) lockObject() *storage.ObjectHandle {
+	return b.client.Bucket(b.bucket).Object(b.object + ".lock")
+}
+
+// ReadLease reads the lease object; its generation is the lease's version
+func (b *gcsBackend) ReadLease(ctx context.Context) (*Lease, string, error) {
+	reader, err := b.lockObject().NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	var lease Lease
+	if err := json.NewDecoder(reader).Decode(&lease); err != nil {
+		return nil, "", fmt.Errorf("failed to parse state lock gs://%s/%s.lock: %w", b.bucket, b.object, err)
+	}
+	return &lease, strconv.FormatInt(reader.Attrs.Generation, 10), nil
+}
+
+// WriteLease writes the lease object on the precondition that its generation is
+// still the one read, or that it doesn't exist yet
+func (b *gcsBackend) WriteLease(ctx context.Context, lease Lease, version string) (string, error) {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state lock: %w", err)
+	}
+	conds, err := gcsConditions(version)
+	if err != nil {
+		return "", err
+	}
+
+	writer := b.lockObject().If(conds).NewWriter(ctx)
+	writer.ContentType = "application/json"
+	if _, err := writer.Write(content); err != nil {
+		writer.Close()
+		return "", gcsLeaseError(err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", gcsLeaseError(err)
+	}
+	return strconv.FormatInt(writer.Attrs().Generation, 10), nil
+}
+
+func (b *gcsBackend) DeleteLease(ctx context.Context, version string) error {
+	conds, err := gcsConditions(version)
+	if err != nil {
+		return err
+	}
+	return gcsLeaseError(b.lockObject().If(conds).Delete(ctx))
+}
+
+func gcsConditions(version string) (storage.Conditions, error) {
+	if version == "" {
+		return storage.Conditions{DoesNotExist: true}, nil
+	}
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return storage.Conditions{}, fmt.Errorf("invalid state lock generation %q", version)
+	}
+	return storage.Conditions{GenerationMatch: generation}, nil
+}
+
+// gcsLeaseError maps failed preconditions to ErrLeaseConflict
+func gcsLeaseError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return ErrLeaseConflict
+	}
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrLeaseConflict
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package state
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Lease is the advisory lock on the state, held by one run at a time. It expires
+// unless renewed, so a run that was killed doesn't block the next one for longer
+// than the lease's TTL.
+type Lease struct {
+	Owner    string    `json:"owner"` // host:pid:nonce of the holding process
+	Acquired time.Time `json:"acquired"`
+	Expires  time.Time `json:"expires"`
+}
+
+// ErrLeaseConflict is returned by Backend.WriteLease and DeleteLease when the lease
+// changed since the version they were given was read
+var ErrLeaseConflict = errors.New("state lease was changed by another run")
+
+// LockedError reports that another run holds the state's lease
+type LockedError struct {
+	Location string
+	Holder   Lease
+}
+
+func (e *LockedError) Error() string {
+	if e.Holder.Owner == "" {
+		return fmt.Sprintf("state %s is locked by another run", e.Location)
+	}
+	return fmt.Sprintf("state %s is locked by %s until %s", e.Location, e.Holder.Owner, e.Holder.Expires.Format(time.RFC3339))
+}
+
+// lockPollInterval is how often a run waiting for the lease checks it again
+const lockPollInterval = 10 * time.Second
+
+// stateLock is the lease a Manager holds and the goroutine renewing it
+type stateLock struct {
+	mu       sync.Mutex
+	owner    string
+	acquired time.Time
+	ttl      time.Duration
+	version  string
+	lost     bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Lock takes the state's lease, waiting up to wait for another run to release it.
+// The state is then reloaded so changes saved by the previous holder aren't
+// overwritten, and the lease is renewed in the background until Unlock. Without
+// the lease held, Save refuses to write.
+func (m *Manager) Lock(ctx context.Context, ttl, wait time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("state lock TTL must be positive, got %s", ttl)
+	}
+	l := &stateLock{owner: leaseOwner(), ttl: ttl}
+	deadline := time.Now().Add(wait)
+	for {
+		err := m.acquire(ctx, l)
+		var locked *LockedError
+		if err == nil || !errors.As(err, &locked) || !time.Now().Before(deadline) {
+			if err != nil {
+				return err
+			}
+			break
+		}
+
+		log.Printf("Waiting for the state lock: %v", err)
+		delay := min(lockPollInterval, time.Until(deadline))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	loadCtx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := m.load(loadCtx); err != nil {
+		m.releaseLease(l)
+		return err
+	}
+
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	m.lock = l
+	go m.renewLease(l)
+	return nil
+}
+
+// acquire writes a new lease unless another run holds an unexpired one
+func (m *Manager) acquire(ctx context.Context, l *stateLock) error {
+	current, version, err := m.backend.ReadLease(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read state lock: %w", err)
+	}
+	now := time.Now().UTC()
+	if current != nil && current.Owner != l.owner && now.Before(current.Expires) {
+		return &LockedError{Location: m.Path(), Holder: *current}
+	}
+	if current != nil {
+		log.Printf("Taking over the state lock of %s, which expired at %s", current.Owner, current.Expires.Format(time.RFC3339))
+	}
+
+	lease := Lease{Owner: l.owner, Acquired: now, Expires: now.Add(l.ttl)}
+	newVersion, err := m.backend.WriteLease(ctx, lease, version)
+	if errors.Is(err, ErrLeaseConflict) {
+		// Another run took the lease between the read and the write
+		holder, _, _ := m.backend.ReadLease(ctx)
+		locked := &LockedError{Location: m.Path()}
+		if holder != nil {
+			locked.Holder = *holder
+		}
+		return locked
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write state lock: %w", err)
+	}
+	l.version, l.acquired = newVersion, now
+	return nil
+}
+
+// renewLease extends the lease every third of its TTL. If another run took it over
+// meanwhile, the lease is marked lost so this run stops saving.
+func (m *Manager) renewLease(l *stateLock) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), l.ttl/3)
+		l.mu.Lock()
+		now := time.Now().UTC()
+		version, err := m.backend.WriteLease(ctx, Lease{Owner: l.owner, Acquired: l.acquired, Expires: now.Add(l.ttl)}, l.version)
+		switch {
+		case errors.Is(err, ErrLeaseConflict):
+			log.Printf("Warning: the state lock on %s was taken over by another run, state will not be saved", m.Path())
+			l.lost = true
+		case err != nil:
+			log.Printf("Warning: failed to renew the state lock on %s: %v", m.Path(), err)
+		default:
+			l.version = version
+		}
+		lost := l.lost
+		l.mu.Unlock()
+		cancel()
+		if lost {
+			return
+		}
+	}
+}
+
+// Unlock stops renewing the lease and releases it for the next run
+func (m *Manager) Unlock() error {
+	l := m.lock
+	if l == nil {
+		return nil
+	}
+	m.lock = nil
+	close(l.stop)
+	<-l.done
+	return m.releaseLease(l)
+}
+
+func (m *Manager) releaseLease(l *stateLock) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lost {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := m.backend.DeleteLease(ctx, l.version); err != nil && !errors.Is(err, ErrLeaseConflict) {
+		return fmt.Errorf("failed to release state lock: %w", err)
+	}
+	return nil
+}
+
+// lockLost reports whether the lease this manager took was taken over by another run
+func (m *Manager) lockLost() bool {
+	l := m.lock
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lost
+}
+
+// leaseOwner identifies this process in the lease, so a blocked run can say which
+// host holds it
+func leaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	nonce := make([]byte, 4)
+	rand.Read(nonce)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(nonce))
+}
@@ -0,0 +1,334 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"harness-onboarder/internal/errors"
+	"harness-onboarder/internal/models"
+)
+
+// Repository processing statuses recorded in the state file
+const (
+	StatusSuccess = "success"
+	StatusSkipped = "skipped"
+	StatusError   = "error"
+)
+
+// RepoState is the last known processing outcome for a single repository
+type RepoState struct {
+	Repository    string    `json:"repository"`
+	Identifier    string    `json:"identifier,omitempty"`
+	Mode          string    `json:"mode"`
+	Status        string    `json:"status"`
+	Action        string    `json:"action,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Fingerprint   string    `json:"fingerprint,omitempty"`
+	Generated     string    `json:"generated,omitempty"` // Last generated catalog-info.yaml, the base for merges
+	URL           string    `json:"url,omitempty"`
+	ErrorCategory string    `json:"error_category,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	PushedAt      time.Time `json:"pushed_at"`
+	LastProcessed time.Time `json:"last_processed"`
+	Attempts      int       `json:"attempts"`
+}
+
+// File is the on-disk representation of the state file
+type File struct {
+	Version      int                  `json:"version"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	Repositories map[string]RepoState `json:"repositories"`
+}
+
+// Manager tracks per-repository processing state across runs
+type Manager struct {
+	mu      sync.Mutex
+	backend Backend
+	data    File
+	lock    *stateLock
+}
+
+// storeTimeout bounds loading and saving the state. Saves don't use the run's
+// context, so state is still written when a run is interrupted.
+const storeTimeout = 2 * time.Minute
+
+// NewManager loads the state at location, starting empty if none was saved yet. The
+// location is a file path or a URL selecting another backend, see NewBackend.
+func NewManager(location string) (*Manager, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	backend, err := NewBackend(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{backend: backend}
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// load replaces the state in memory with the one stored in the backend
+func (m *Manager) load(ctx context.Context) error {
+	data, err := m.backend.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = &File{Version: 1}
+	}
+	if data.Repositories == nil {
+		data.Repositories = make(map[string]RepoState)
+	}
+
+	m.mu.Lock()
+	m.data = *data
+	m.mu.Unlock()
+	return nil
+}
+
+// Path returns the location of the state
+func (m *Manager) Path() string {
+	return m.backend.Location()
+}
+
+// Get returns the recorded state for a repository
+func (m *Manager) Get(fullName string) (RepoState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.data.Repositories[fullName]
+	return s, ok
+}
+
+// All returns a snapshot of every recorded repository state
+func (m *Manager) All() []RepoState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	states := make([]RepoState, 0, len(m.data.Repositories))
+	for _, s := range m.data.Repositories {
+		states = append(states, s)
+	}
+	return states
+}
+
+// Remove deletes the recorded state for a repository
+func (m *Manager) Remove(fullName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.data.Repositories[fullName]; !ok {
+		return false
+	}
+	delete(m.data.Repositories, fullName)
+	return true
+}
+
+// IsUnchanged reports whether the repository was processed successfully in the
+// given mode and hasn't been pushed to since
+func (m *Manager) IsUnchanged(repo models.Repository, mode string) bool {
+	s, ok := m.Get(repo.FullName)
+	if !ok {
+		return false
+	}
+	if s.Mode != mode || s.Status == StatusError {
+		return false
+	}
+	return !repo.PushedAt.After(s.PushedAt)
+}
+
+// maxRetryBackoff caps the exponential backoff between retries of a failing repository
+const maxRetryBackoff = 24 * time.Hour
+
+// Reset removes every recorded repository state and returns how many were removed
+func (m *Manager) Reset() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.data.Repositories)
+	m.data.Repositories = make(map[string]RepoState)
+	return n
+}
+
+// Prune removes repositories that haven't been processed since before the cutoff
+// and returns their names
+func (m *Manager) Prune(cutoff time.Time) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var removed []string
+	for name, s := range m.data.Repositories {
+		if s.LastProcessed.Before(cutoff) {
+			delete(m.data.Repositories, name)
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// Export writes the state as indented JSON in the state file format
+func (m *Manager) Export(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m.data)
+}
+
+// Import loads state previously written by Export. Entries replace the current
+// state unless merge is set, in which case they overwrite matching repositories only.
+func (m *Manager) Import(r io.Reader, merge bool) (int, error) {
+	var imported File
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return 0, fmt.Errorf("failed to parse state: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !merge {
+		m.data.Repositories = make(map[string]RepoState)
+	}
+	for name, s := range imported.Repositories {
+		m.data.Repositories[name] = s
+	}
+	return len(imported.Repositories), nil
+}
+
+// NextRetry returns the earliest time a failed repository should be retried. The
+// backoff doubles with every consecutive failed attempt.
+func (s RepoState) NextRetry(backoff time.Duration) time.Time {
+	wait := backoff
+	for i := 1; i < s.Attempts && wait < maxRetryBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return s.LastProcessed.Add(wait)
+}
+
+// DueForRetry returns the repositories whose last run in the given mode failed and
+// whose backoff window has passed, along with the number still waiting
+func (m *Manager) DueForRetry(mode string, backoff time.Duration, now time.Time) ([]RepoState, int) {
+	var due []RepoState
+	waiting := 0
+	for _, s := range m.All() {
+		if s.Status != StatusError || s.Mode != mode {
+			continue
+		}
+		if now.Before(s.NextRetry(backoff)) {
+			waiting++
+			continue
+		}
+		due = append(due, s)
+	}
+	return due, waiting
+}
+
+// Record stores the outcome of processing a repository
+func (m *Manager) Record(repo models.Repository, mode string, identifier string, result errors.ProcessingResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev := m.data.Repositories[repo.FullName]
+
+	status := StatusSuccess
+	if result.Skipped {
+		status = StatusSkipped
+	}
+	if result.Error != nil && !result.Skipped {
+		status = StatusError
+	}
+
+	attempts := 1
+	if status == StatusError && prev.Status == StatusError {
+		attempts = prev.Attempts + 1
+	}
+
+	if identifier == "" {
+		identifier = prev.Identifier
+	}
+
+	url := result.URL
+	if url == "" {
+		url = prev.URL
+	}
+
+	var errCategory, errMessage string
+	if result.Error != nil {
+		errCategory = string(result.Error.Category)
+		errMessage = result.Error.GetUserFriendlyMessage()
+	}
+
+	m.data.Repositories[repo.FullName] = RepoState{
+		Repository:    repo.FullName,
+		Identifier:    identifier,
+		Mode:          mode,
+		Status:        status,
+		Action:        result.Action,
+		Message:       result.Message,
+		Fingerprint:   prev.Fingerprint,
+		Generated:     prev.Generated,
+		URL:           url,
+		ErrorCategory: errCategory,
+		Error:         errMessage,
+		PushedAt:      repo.PushedAt,
+		LastProcessed: time.Now().UTC(),
+		Attempts:      attempts,
+	}
+}
+
+// SetFingerprint stores a hash of the last entity content pushed for a repository
+func (m *Manager) SetFingerprint(fullName string, fingerprint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.data.Repositories[fullName]
+	s.Repository = fullName
+	s.Fingerprint = fingerprint
+	m.data.Repositories[fullName] = s
+}
+
+// SetGenerated stores the catalog content generated for a repository, used as the
+// common ancestor when merging into its catalog file on later runs
+func (m *Manager) SetGenerated(fullName string, content string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.data.Repositories[fullName]
+	s.Repository = fullName
+	s.Generated = content
+	m.data.Repositories[fullName] = s
+}
+
+// Save writes the state to the backend. It fails once another run has taken over
+// the lease taken with Lock, since that run's state would be overwritten.
+func (m *Manager) Save() error {
+	if m.lockLost() {
+		return fmt.Errorf("state lock on %s was taken over by another run", m.Path())
+	}
+
+	m.mu.Lock()
+	m.data.UpdatedAt = time.Now().UTC()
+	data := m.data
+	data.Repositories = make(map[string]RepoState, len(m.data.Repositories))
+	for name, s := range m.data.Repositories {
+		data.Repositories[name] = s
+	}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	return m.backend.Save(ctx, &data)
+}
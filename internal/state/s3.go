@@ -0,0 +1,156 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// s3Backend keeps the state as a JSON object in S3. Credentials and region come
+// from the usual AWS environment variables, shared config or instance role.
+type s3Backend struct {
+	client *s3.Client
+	bucket string
+	key    string
+}
+
+func newS3Backend(ctx context.Context, bucket, key string) (*s3Backend, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	return &s3Backend{client: s3.NewFromConfig(cfg), bucket: bucket, key: key}, nil
+}
+
+func (b *s3Backend) Location() string {
+	return "s3://" + b.bucket + "/" + b.key
+}
+
+func (b *s3Backend) Load(ctx context.Context) (*File, error) {
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key),
+	})
+	if err != nil {
+		var missing *types.NoSuchKey
+		if errors.As(err, &missing) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	defer out.Body.Close()
+
+	content, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	return decodeFile(content, b.Location())
+}
+
+// Save uploads the state in one PutObject, which replaces the object atomically
+func (b *s3Backend) Save(ctx context.Context, data *File) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	_, err = b.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(b.bucket),
+		Key:         aws.String(b.key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	return nil
+}
+
+func (b *s3Backend) lockKey() string {
+	return b.key + ".lock"
+}
+
+// ReadLease reads the lease object; its ETag is the lease's version
+func (b *s3Backend) ReadLease(ctx context.Context) (*Lease, string, error) {
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.lockKey()),
+	})
+	if err != nil {
+		var missing *types.NoSuchKey
+		if errors.As(err, &missing) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	defer out.Body.Close()
+
+	var lease Lease
+	if err := json.NewDecoder(out.Body).Decode(&lease); err != nil {
+		return nil, "", fmt.Errorf("failed to parse state lock s3://%s/%s: %w", b.bucket, b.lockKey(), err)
+	}
+	return &lease, aws.ToString(out.ETag), nil
+}
+
+// WriteLease uses a conditional PutObject, so S3 rejects the write when the lease
+// object was created or replaced since it was read
+func (b *s3Backend) WriteLease(ctx context.Context, lease Lease, version string) (string, error) {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state lock: %w", err)
+	}
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(b.bucket),
+		Key:         aws.String(b.lockKey()),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String("application/json"),
+	}
+	if version == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(version)
+	}
+
+	out, err := b.client.PutObject(ctx, input)
+	if err != nil {
+		if s3PreconditionFailed(err) {
+			return "", ErrLeaseConflict
+		}
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func (b *s3Backend) DeleteLease(ctx context.Context, version string) error {
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(b.bucket),
+		Key:     aws.String(b.lockKey()),
+		IfMatch: aws.String(version),
+	})
+	if err != nil && s3PreconditionFailed(err) {
+		return ErrLeaseConflict
+	}
+	return err
+}
+
+// s3PreconditionFailed reports whether a conditional request failed because the
+// object changed, including concurrent conditional writes
+func s3PreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema stores one row per repository, so the database can also be queried
+// directly, plus the state file's version and update time and the lease of the run
+// holding it
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS repositories (
+	repository TEXT PRIMARY KEY,
+	state      TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS lease (
+	id    INTEGER PRIMARY KEY CHECK (id = 1),
+	lease TEXT NOT NULL
+);`
+
+// sqliteBackend keeps the state in a SQLite database, e.g. on a volume shared
+// between runs
+type sqliteBackend struct {
+	db   *sql.DB
+	path string
+}
+
+func newSQLiteBackend(ctx context.Context, path string) (*sqliteBackend, error) {
+	// Other processes may be writing the lease; wait for them instead of failing
+	dsn := path + "?_pragma=busy_timeout(10000)"
+	if strings.Contains(path, "?") {
+		dsn = path + "&_pragma=busy_timeout(10000)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %s: %w", path, err)
+	}
+	// SQLite allows one writer at a time
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize state database %s: %w", path, err)
+	}
+	return &sqliteBackend{db: db, path: path}, nil
+}
+
+func (b *sqliteBackend) Location() string {
+	return "sqlite://" + b.path
+}
+
+func (b *sqliteBackend) Load(ctx context.Context) (*File, error) {
+	data := &File{Version: 1, Repositories: make(map[string]RepoState)}
+
+	found, err := b.loadMetadata(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	rows, err := b.db.QueryContext(ctx, `SELECT repository, state FROM repositories`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, content string
+		if err := rows.Scan(&name, &content); err != nil {
+			return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+		}
+		var s RepoState
+		if err := json.Unmarshal([]byte(content), &s); err != nil {
+			return nil, fmt.Errorf("failed to parse state of %s in %s: %w", name, b.Location(), err)
+		}
+		data.Repositories[name] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	return data, nil
+}
+
+// loadMetadata reads the version and update time, reporting whether state was saved
+// before
+func (b *sqliteBackend) loadMetadata(ctx context.Context, data *File) (bool, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT key, value FROM metadata`)
+	if err != nil {
+		return false, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return false, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+		}
+		found = true
+		switch key {
+		case "version":
+			data.Version, _ = strconv.Atoi(value)
+		case "updated_at":
+			data.UpdatedAt, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to read state from %s: %w", b.Location(), err)
+	}
+	return found, nil
+}
+
+// Save replaces every row in one transaction, so a failed save leaves the previous
+// state in place
+func (b *sqliteBackend) Save(ctx context.Context, data *File) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM repositories`); err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	insert, err := tx.PrepareContext(ctx, `INSERT INTO repositories (repository, state) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	defer insert.Close()
+	for name, s := range data.Repositories {
+		content, err := json.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("failed to marshal state of %s: %w", name, err)
+		}
+		if _, err := insert.ExecContext(ctx, name, string(content)); err != nil {
+			return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+		}
+	}
+
+	for key, value := range map[string]string{
+		"version":    strconv.Itoa(data.Version),
+		"updated_at": data.UpdatedAt.Format(time.RFC3339Nano),
+	} {
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO metadata (key, value) VALUES (?, ?)`, key, value); err != nil {
+			return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write state to %s: %w", b.Location(), err)
+	}
+	return nil
+}
+
+// ReadLease reads the lease row; its JSON is the lease's version
+func (b *sqliteBackend) ReadLease(ctx context.Context) (*Lease, string, error) {
+	var content string
+	err := b.db.QueryRowContext(ctx, `SELECT lease FROM lease WHERE id = 1`).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	var lease Lease
+	if err := json.Unmarshal([]byte(content), &lease); err != nil {
+		return nil, "", fmt.Errorf("failed to parse state lock in %s: %w", b.Location(), err)
+	}
+	return &lease, content, nil
+}
+
+// WriteLease inserts the lease row, or updates it only while it still holds the
+// version read, so the database decides which of two runs gets it
+func (b *sqliteBackend) WriteLease(ctx context.Context, lease Lease, version string) (string, error) {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state lock: %w", err)
+	}
+
+	var result sql.Result
+	if version == "" {
+		result, err = b.db.ExecContext(ctx, `INSERT OR IGNORE INTO lease (id, lease) VALUES (1, ?)`, string(content))
+	} else {
+		result, err = b.db.ExecContext(ctx, `UPDATE lease SET lease = ? WHERE id = 1 AND lease = ?`, string(content), version)
+	}
+	if err != nil {
+		return "", err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return "", ErrLeaseConflict
+	}
+	return string(content), nil
+}
+
+func (b *sqliteBackend) DeleteLease(ctx context.Context, version string) error {
+	result, err := b.db.ExecContext(ctx, `DELETE FROM lease WHERE id = 1 AND lease = ?`, version)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return ErrLeaseConflict
+	}
+	return nil
+}